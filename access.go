@@ -0,0 +1,425 @@
+package logger
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	访问日志格式
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:48 	agent		创建
+*******************************************************************************/
+type ACCESS_FORMAT int
+
+const (
+	ACCESS_TEXT     ACCESS_FORMAT = iota //普通日志格式，请求信息以key=value的形式输出
+	ACCESS_COMBINED                      //Apache/Nginx的combined格式，可以直接使用GoAccess、awstats等工具分析
+	ACCESS_W3C                           //W3C扩展日志格式，每个文件开头带有#Fields头，时间为UTC
+)
+
+const (
+	//W3C扩展日志格式的字段列表
+	accessW3CFields = "date time c-ip cs-username cs-method cs-uri-stem cs-uri-query sc-status sc-bytes time-taken cs(User-Agent) cs(Referer)"
+)
+
+/******************************************************************************
+ @brief
+ 	记录响应状态和大小的应答包装，转发Flush和Hijack，流式应答和协议升级不受影响
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:48 	agent		创建
+ 	2026-10-16_15:37 	agent		转发Flush和Hijack
+*******************************************************************************/
+type accessWriter struct {
+	http.ResponseWriter
+	status int   //响应状态码
+	size   int64 //响应内容大小
+}
+
+/******************************************************************************
+ @brief
+ 	HTTP访问日志中间件，每个请求完成后写入一条访问日志到category分类日志文件
+ 		例：
+ 			http.ListenAndServe(":8080", logger.AccessLog("access", logger.ACCESS_COMBINED, mux))
+
+ 		输出：
+ 			10.0.0.1 - - [16/Oct/2026:01:30:00 +0800] "GET /rank?page=2 HTTP/1.1" 200 1532 "-" "curl/8.0"
+
+ 		ACCESS_COMBINED和ACCESS_W3C格式的日志行不带日志头，也不输出到扩展输出目标和终端控制台，
+ 		ACCESS_TEXT格式与普通INFO日志相同，受日志级别控制。
+ 		ACCESS_W3C格式会在分类日志的每个新文件开头写入#Fields等文件头，
+ 		所以同一个分类不要混用其它格式
+ @author
+ 	agent
+ @param
+	category			分类名称，访问日志写入该分类的日志文件
+	format				访问日志格式
+	next				实际处理请求的Handler
+ @return
+ 	http.Handler		返回包装后的Handler
+ @history
+ 	2026-10-16_14:48 	agent		创建
+ 	2026-10-16_14:49 	agent		支持W3C扩展日志格式
+*******************************************************************************/
+func AccessLog(category string, format ACCESS_FORMAT, next http.Handler) http.Handler {
+	c := Category(category)
+	if format == ACCESS_W3C {
+		c.file.header.Store(accessW3CHeader)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		aw := &accessWriter{ResponseWriter: w}
+		next.ServeHTTP(aw, r)
+
+		if aw.status == 0 {
+			aw.status = http.StatusOK
+		}
+
+		switch format {
+		case ACCESS_COMBINED:
+			c.file.write(accessCombined(r, aw.status, aw.size, start))
+		case ACCESS_W3C:
+			c.file.write(accessW3C(r, aw.status, aw.size, start))
+		default:
+			if currentLevel() <= INFO {
+				c.output(INFO, accessText(r, aw.status, aw.size, time.Since(start)))
+			}
+		}
+	})
+}
+
+/******************************************************************************
+ @brief
+ 	生成combined格式的访问日志行
+ @author
+ 	agent
+ @param
+	r					HTTP请求
+	status				响应状态码
+	size				响应内容大小
+	start				请求开始时间
+ @return
+ 	[]byte				返回日志行
+ @history
+ 	2026-10-16_14:48 	agent		创建
+*******************************************************************************/
+func accessCombined(r *http.Request, status int, size int64, start time.Time) []byte {
+
+	user := "-"
+	if r.URL.User != nil && r.URL.User.Username() != "" {
+		user = r.URL.User.Username()
+	} else if name, _, ok := r.BasicAuth(); ok && name != "" {
+		user = name
+	}
+
+	bytes := "-"
+	if size > 0 {
+		bytes = strconv.FormatInt(size, 10)
+	}
+
+	line := fmt.Sprintf("%s - %s [%s] \"%s %s %s\" %d %s \"%s\" \"%s\"\n",
+		accessHost(r),
+		accessEscape(user),
+		start.Format("02/Jan/2006:15:04:05 -0700"),
+		accessEscape(r.Method),
+		accessEscape(r.RequestURI),
+		accessEscape(r.Proto),
+		status,
+		bytes,
+		accessEscape(r.Referer()),
+		accessEscape(r.UserAgent()))
+
+	return []byte(line)
+}
+
+/******************************************************************************
+ @brief
+ 	生成W3C扩展日志格式的文件头
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	[]byte				返回文件头
+ @history
+ 	2026-10-16_14:49 	agent		创建
+*******************************************************************************/
+func accessW3CHeader() []byte {
+	header := fmt.Sprintf("#Version: 1.0\n#Software: github.com/baickl/logger\n#Date: %s\n#Fields: %s\n",
+		time.Now().UTC().Format("2006-01-02 15:04:05"),
+		accessW3CFields)
+
+	return []byte(header)
+}
+
+/******************************************************************************
+ @brief
+ 	生成W3C扩展日志格式的访问日志行，字段顺序与accessW3CFields一致
+ @author
+ 	agent
+ @param
+	r					HTTP请求
+	status				响应状态码
+	size				响应内容大小
+	start				请求开始时间
+ @return
+ 	[]byte				返回日志行
+ @history
+ 	2026-10-16_14:49 	agent		创建
+ 	2026-10-16_15:37 	agent		time-taken改为秒
+*******************************************************************************/
+func accessW3C(r *http.Request, status int, size int64, start time.Time) []byte {
+
+	user := ""
+	if name, _, ok := r.BasicAuth(); ok {
+		user = name
+	}
+
+	fields := []string{
+		start.UTC().Format("2006-01-02"),
+		start.UTC().Format("15:04:05"),
+		accessW3CValue(accessHost(r)),
+		accessW3CValue(user),
+		accessW3CValue(r.Method),
+		accessW3CValue(r.URL.EscapedPath()),
+		accessW3CValue(r.URL.RawQuery),
+		strconv.Itoa(status),
+		strconv.FormatInt(size, 10),
+		fmt.Sprintf("%.3f", time.Since(start).Seconds()), //time-taken的单位为秒
+		accessW3CValue(r.UserAgent()),
+		accessW3CValue(r.Referer()),
+	}
+
+	return []byte(strings.Join(fields, " ") + "\n")
+}
+
+/******************************************************************************
+ @brief
+ 	转换W3C扩展日志格式的字段值，与IIS一致将空格替换为+，空字符串输出为-
+ @author
+ 	agent
+ @param
+	s					原始字符串
+ @return
+ 	string				返回转换后的字符串
+ @history
+ 	2026-10-16_14:49 	agent		创建
+*******************************************************************************/
+func accessW3CValue(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == ' ':
+			return '+'
+		case r < 0x20 || r == 0x7f:
+			return -1
+		}
+		return r
+	}, s)
+}
+
+/******************************************************************************
+ @brief
+ 	生成普通格式的访问日志内容
+ @author
+ 	agent
+ @param
+	r					HTTP请求
+	status				响应状态码
+	size				响应内容大小
+	cost				请求耗时
+ @return
+ 	string				返回日志内容
+ @history
+ 	2026-10-16_14:48 	agent		创建
+*******************************************************************************/
+func accessText(r *http.Request, status int, size int64, cost time.Duration) string {
+	fields := []string{
+		"access",
+		configField("remote", accessHost(r)),
+		configField("method", r.Method),
+		configField("uri", r.RequestURI),
+		configField("status", strconv.Itoa(status)),
+		configField("size", strconv.FormatInt(size, 10)),
+		configField("dur_ms", strconv.FormatInt(cost.Milliseconds(), 10)),
+		configField("ua", r.UserAgent()),
+	}
+
+	return strings.Join(fields, " ") + "\n"
+}
+
+/******************************************************************************
+ @brief
+ 	获取请求的客户端地址，不带端口
+ @author
+ 	agent
+ @param
+	r					HTTP请求
+ @return
+ 	string				返回客户端地址
+ @history
+ 	2026-10-16_14:48 	agent		创建
+*******************************************************************************/
+func accessHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+/******************************************************************************
+ @brief
+ 	按照Apache的规则转义双引号、反斜杠和控制字符，空字符串输出为-
+ @author
+ 	agent
+ @param
+	s					原始字符串
+ @return
+ 	string				返回转义后的字符串
+ @history
+ 	2026-10-16_14:48 	agent		创建
+*******************************************************************************/
+func accessEscape(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c == 0x7f:
+			fmt.Fprintf(&b, "\\x%02x", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+/******************************************************************************
+ @brief
+ 	记录响应状态码
+ @author
+ 	agent
+ @param
+	status				响应状态码
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:48 	agent		创建
+*******************************************************************************/
+func (w *accessWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+/******************************************************************************
+ @brief
+ 	记录响应内容大小
+ @author
+ 	agent
+ @param
+	b					响应内容
+ @return
+ 	int					返回写入的大小
+ 	error				写入失败时返回错误信息
+ @history
+ 	2026-10-16_14:48 	agent		创建
+*******************************************************************************/
+func (w *accessWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+	return n, err
+}
+
+/******************************************************************************
+ @brief
+ 	返回被包装的应答，http.ResponseController通过它访问Flush、Hijack等功能
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	http.ResponseWriter	返回被包装的应答
+ @history
+ 	2026-10-16_14:48 	agent		创建
+*******************************************************************************/
+func (w *accessWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+/******************************************************************************
+ @brief
+ 	转发Flush，Server-Sent Events等流式应答需要类型断言http.Flusher
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:37 	agent		创建
+*******************************************************************************/
+func (w *accessWriter) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	转发Hijack，WebSocket等协议升级需要类型断言http.Hijacker，
+ 	接管连接后访问日志的状态码记为101
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	net.Conn			返回被接管的连接
+ 	*bufio.ReadWriter	返回连接的读写缓冲
+ 	error				被包装的应答不支持接管时返回错误信息
+ @history
+ 	2026-10-16_15:37 	agent		创建
+*******************************************************************************/
+func (w *accessWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+
+	conn, rw, err := h.Hijack()
+	if err == nil && w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
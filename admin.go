@@ -0,0 +1,329 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	注册只读的日志管理接口，和pprof一样挂在默认的HTTP服务上，通过StartPPROF启动后即可访问
+ 		GET  /debug/logger/sinks						查看输出目标健康状态
+ 		GET  /debug/logger/stats						查看日志自身开销统计
+ 		GET  /debug/logger/errors						查看日志自身的错误记录
+ 	修改日志级别、过滤规则等接口不在这里注册，需要通过AdminHandler显式挂载
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:09 	agent		创建
+ 	2026-10-16_14:13 	agent		增加输出目标健康状态
+ 	2026-10-16_14:44 	agent		增加日志自身开销统计
+ 	2026-10-16_14:47 	agent		增加日志自身的错误记录
+ 	2026-10-16_14:58 	agent		增加过滤规则
+ 	2026-10-16_15:37 	agent		只注册只读接口，可以修改的接口移到AdminHandler
+*******************************************************************************/
+func init() {
+	http.HandleFunc("/debug/logger/sinks", handleSinks)
+	http.HandleFunc("/debug/logger/stats", handleStats)
+	http.HandleFunc("/debug/logger/errors", handleErrors)
+}
+
+/******************************************************************************
+ @brief
+ 	获取全部日志管理接口，需要调用者自己挂载，修改类请求（POST、DELETE等）需要auth通过：
+ 		GET  /debug/logger/level						查看当前日志级别
+ 		POST /debug/logger/level?level=INFO				设置日志级别
+ 		POST /debug/logger/boost?level=DEBUG&duration=10m	临时调整日志级别
+ 		GET  /debug/logger/sinks						查看输出目标健康状态
+ 		GET  /debug/logger/stats						查看日志自身开销统计
+ 		GET  /debug/logger/errors						查看日志自身的错误记录
+ 		GET  /debug/logger/filters						查看过滤规则
+ 		POST /debug/logger/filters						添加过滤规则，内容为JSON
+ 		DELETE /debug/logger/filters?name=reset			删除过滤规则
+ 		例：
+ 			http.Handle("/debug/logger/", logger.AdminHandler(func(r *http.Request) bool {
+ 				return r.Header.Get("X-Admin-Token") == adminToken
+ 			}))
+ @author
+ 	agent
+ @param
+	auth				修改类请求的认证函数，返回true时允许，为nil时拒绝全部修改类请求
+ @return
+ 	http.Handler		返回日志管理接口
+ @history
+ 	2026-10-16_15:37 	agent		创建
+*******************************************************************************/
+func AdminHandler(auth func(r *http.Request) bool) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/logger/level", handleLevel)
+	mux.HandleFunc("/debug/logger/boost", handleBoost)
+	mux.HandleFunc("/debug/logger/sinks", handleSinks)
+	mux.HandleFunc("/debug/logger/stats", handleStats)
+	mux.HandleFunc("/debug/logger/errors", handleErrors)
+	mux.HandleFunc("/debug/logger/filters", handleFilters)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead && (auth == nil || !auth(r)) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	})
+}
+
+/******************************************************************************
+ @brief
+ 	查看或设置日志级别
+ @author
+ 	agent
+ @param
+	w					HTTP应答
+	r					HTTP请求
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:09 	agent		创建
+*******************************************************************************/
+func handleLevel(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method == http.MethodPost {
+		level, err := ParseLevel(r.FormValue("level"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		SetLevel(level)
+	}
+
+	scheduleLock.Lock()
+	defer scheduleLock.Unlock()
+
+	fmt.Fprintf(w, "level=%s base=%s", currentLevel(), logLevelBase)
+	if time.Now().Before(boostUntil) {
+		fmt.Fprintf(w, " boost=%s boost_until=%s", boostLevel, boostUntil.Format("2006/01/02_15:04:05"))
+	}
+	fmt.Fprintln(w)
+}
+
+/******************************************************************************
+ @brief
+ 	临时调整日志级别
+ @author
+ 	agent
+ @param
+	w					HTTP应答
+	r					HTTP请求
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:09 	agent		创建
+*******************************************************************************/
+func handleBoost(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	level, err := ParseLevel(r.FormValue("level"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	duration, err := time.ParseDuration(r.FormValue("duration"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	BoostLevel(level, duration)
+	fmt.Fprintf(w, "boost=%s duration=%v\n", level, duration)
+}
+
+/******************************************************************************
+ @brief
+ 	查看输出目标健康状态
+ @author
+ 	agent
+ @param
+	w					HTTP应答
+	r					HTTP请求
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:13 	agent		创建
+*******************************************************************************/
+func handleSinks(w http.ResponseWriter, r *http.Request) {
+
+	for _, st := range SinkStatus() {
+		fmt.Fprintf(w, "name=%s connected=%v retries=%d queue=%d spilled=%d",
+			st.Name, st.Connected, st.RetryCount, st.QueueDepth, st.Spilled)
+		if st.LastError != "" {
+			fmt.Fprintf(w, " last_error_at=%s last_error=%q", st.LastErrorAt.Format("2006/01/02_15:04:05"), st.LastError)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	查看日志自身开销统计
+ @author
+ 	agent
+ @param
+	w					HTTP应答
+	r					HTTP请求
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:44 	agent		创建
+ 	2026-10-16_14:53 	agent		增加工作协程异常次数
+ 	2026-10-16_14:57 	agent		增加慢操作耗时统计
+ 	2026-10-16_15:10 	agent		统计输出移到printStats
+*******************************************************************************/
+func handleStats(w http.ResponseWriter, r *http.Request) {
+	printStats(w)
+}
+
+/******************************************************************************
+ @brief
+ 	以文本形式输出日志自身开销统计，管理接口和问题收集包共用
+ @author
+ 	agent
+ @param
+	w					输出目标
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:10 	agent		创建
+*******************************************************************************/
+func printStats(w io.Writer) {
+	st := Stats()
+	fmt.Fprintf(w, "profiling=%v\n", st.Profiling)
+	fmt.Fprintf(w, "entries=%d p50=%v p90=%v p99=%v max=%v\n",
+		st.Entries, st.EntryP50, st.EntryP90, st.EntryP99, st.EntryMax)
+	fmt.Fprintf(w, "lock_waits=%d p50=%v p90=%v p99=%v max=%v\n",
+		st.LockWaits, st.LockP50, st.LockP90, st.LockP99, st.LockMax)
+	fmt.Fprintf(w, "async queued=%d dropped=%d\n", st.Queued, st.Dropped)
+	for name, n := range st.Panics {
+		fmt.Fprintf(w, "panics name=%s count=%d\n", name, n)
+	}
+	for name, sl := range st.Slow {
+		fmt.Fprintf(w, "slow name=%s count=%d slow=%d p50=%v p90=%v p99=%v max=%v\n",
+			name, sl.Count, sl.Slow, sl.P50, sl.P90, sl.P99, sl.Max)
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	查看日志自身的错误记录
+ @author
+ 	agent
+ @param
+	w					HTTP应答
+	r					HTTP请求
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:47 	agent		创建
+ 	2026-10-16_15:10 	agent		错误记录输出移到printDiagnostics
+*******************************************************************************/
+func handleErrors(w http.ResponseWriter, r *http.Request) {
+	printDiagnostics(w)
+}
+
+/******************************************************************************
+ @brief
+ 	以文本形式输出日志自身的错误记录，管理接口和问题收集包共用
+ @author
+ 	agent
+ @param
+	w					输出目标
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:10 	agent		创建
+*******************************************************************************/
+func printDiagnostics(w io.Writer) {
+	for _, e := range Diagnostics() {
+		fmt.Fprintf(w, "%s %s\n", e.Time.Format("2006/01/02_15:04:05.000"), e.Msg)
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	查看、添加或删除过滤规则，添加时提交JSON：
+ 		{"name":"reset","match":"connection reset","level":"WARN","sample":100,"duration":"10m"}
+ 	level默认为WARN，sample为0时丢弃全部匹配的日志，duration为空时一直有效
+ @author
+ 	agent
+ @param
+	w					HTTP应答
+	r					HTTP请求
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:58 	agent		创建
+*******************************************************************************/
+func handleFilters(w http.ResponseWriter, r *http.Request) {
+
+	switch r.Method {
+	case http.MethodPost:
+		var req struct {
+			Name     string `json:"name"`
+			Match    string `json:"match"`
+			Level    string `json:"level"`
+			Sample   int    `json:"sample"`
+			Duration string `json:"duration"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64*1024)).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		rule := FILTER_RULE{Name: req.Name, Match: req.Match, Level: WARN, Sample: req.Sample}
+		if len(req.Level) > 0 {
+			level, err := ParseLevel(req.Level)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			rule.Level = level
+		}
+
+		if len(req.Duration) > 0 {
+			duration, err := time.ParseDuration(req.Duration)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			rule.Expires = time.Now().Add(duration)
+		}
+
+		if err := AddFilter(rule); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+	case http.MethodDelete:
+		if !RemoveFilter(r.FormValue("name")) {
+			http.Error(w, "filter not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	for _, rule := range Filters() {
+		fmt.Fprintf(w, "name=%s match=%q level=%s sample=%d dropped=%d",
+			rule.Name, rule.Match, rule.Level, rule.Sample, rule.Dropped)
+		if !rule.Expires.IsZero() {
+			fmt.Fprintf(w, " expires=%s", rule.Expires.Format("2006/01/02_15:04:05"))
+		}
+		fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,318 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	readChunk = 1024 * 1024 //每次读取的最大大小
+)
+
+/******************************************************************************
+ @brief
+ 	日志转发代理类结构，读取logger生成的日志文件，将新写入的完整行转发到输出目标，
+ 	已经转发的偏移记录在状态文件中，重启后从上次的位置继续
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:29 	agent		创建
+*******************************************************************************/
+type AGENT struct {
+	sync.Mutex                  //线程锁
+	pattern    string           //日志文件匹配规则
+	writer     io.Writer        //输出目标
+	stateFile  string           //偏移状态文件
+	offsets    map[string]int64 //每个文件已经转发的偏移
+	stop       chan struct{}    //停止转发
+}
+
+/******************************************************************************
+ @brief
+ 	创建日志转发代理，可以在进程内运行，也可以通过logger-agent作为独立进程运行
+ 		例：
+ 			w, _ := logger.NewNetWriter("tcp", "collector:5170", nil)
+ 			a, err := agent.New(filepath.Join("./logs", "*", "LoginServer.*.log"), w, "./logs/agent.state")
+ 			if err == nil {
+ 				a.Start(5 * time.Second)
+ 				defer a.Stop()
+ 			}
+ @author
+ 	agent
+ @param
+	pattern				日志文件匹配规则，规则同filepath.Glob
+	w					输出目标，例如logger.NewNetWriter创建的网络输出目标
+	stateFile			偏移状态文件
+ @return
+ 	*AGENT				返回日志转发代理
+ 	error				匹配规则或状态文件错误时返回错误信息
+ @history
+ 	2026-10-16_14:29 	agent		创建
+*******************************************************************************/
+func New(pattern string, w io.Writer, stateFile string) (*AGENT, error) {
+
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("agent: invalid pattern %q: %v", pattern, err)
+	}
+
+	offsets, err := readState(stateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AGENT{pattern: pattern, writer: w, stateFile: stateFile, offsets: offsets}, nil
+}
+
+/******************************************************************************
+ @brief
+ 	启动后台转发，每隔interval检查一次日志文件
+ @author
+ 	agent
+ @param
+	interval			检查时间间隔
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:29 	agent		创建
+*******************************************************************************/
+func (a *AGENT) Start(interval time.Duration) {
+	a.Lock()
+	defer a.Unlock()
+
+	if a.stop != nil {
+		return
+	}
+
+	a.stop = make(chan struct{})
+	go a.monitor(interval, a.stop)
+}
+
+/******************************************************************************
+ @brief
+ 	停止后台转发
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:29 	agent		创建
+*******************************************************************************/
+func (a *AGENT) Stop() {
+	a.Lock()
+	defer a.Unlock()
+
+	if a.stop != nil {
+		close(a.stop)
+		a.stop = nil
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	执行一次转发，将所有匹配文件新写入的完整行转发到输出目标并保存偏移状态
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	error				转发或保存状态失败时返回错误信息
+ @history
+ 	2026-10-16_14:29 	agent		创建
+*******************************************************************************/
+func (a *AGENT) Scan() error {
+	a.Lock()
+	defer a.Unlock()
+
+	files, _ := filepath.Glob(a.pattern)
+	sort.Strings(files)
+
+	//已经删除的文件不再记录
+	exists := map[string]bool{}
+	for _, fn := range files {
+		exists[fn] = true
+	}
+	for fn := range a.offsets {
+		if !exists[fn] {
+			delete(a.offsets, fn)
+		}
+	}
+
+	var err error
+	for _, fn := range files {
+		if err = a.forward(fn); err != nil {
+			break
+		}
+	}
+
+	if serr := writeState(a.stateFile, a.offsets); err == nil {
+		err = serr
+	}
+
+	return err
+}
+
+/******************************************************************************
+ @brief
+ 	后台转发监控函数
+ @author
+ 	agent
+ @param
+	interval			检查时间间隔
+	stop				停止信号
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:29 	agent		创建
+*******************************************************************************/
+func (a *AGENT) monitor(interval time.Duration, stop chan struct{}) {
+	timer := time.NewTicker(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			a.Scan()
+		case <-stop:
+			return
+		}
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	转发单个文件新写入的完整行，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	fn					文件路径
+ @return
+ 	error				转发失败时返回错误信息
+ @history
+ 	2026-10-16_14:29 	agent		创建
+*******************************************************************************/
+func (a *AGENT) forward(fn string) error {
+
+	file, err := os.Open(fn)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	//文件被截断时从头开始
+	if fi, err := file.Stat(); err == nil && fi.Size() < a.offsets[fn] {
+		a.offsets[fn] = 0
+	}
+
+	buf := make([]byte, readChunk)
+	for {
+		offset := a.offsets[fn]
+		n, err := file.ReadAt(buf, offset)
+
+		//只转发完整的行，不完整的行等待下次
+		i := bytes.LastIndexByte(buf[:n], '\n')
+		if i < 0 {
+			if n == len(buf) {
+				i = n - 1 //单行超过读取大小
+			} else {
+				return nil
+			}
+		}
+
+		if _, err := a.writer.Write(buf[:i+1]); err != nil {
+			return err
+		}
+		a.offsets[fn] = offset + int64(i+1)
+
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	读取偏移状态文件，每行格式为：偏移 文件路径
+ @author
+ 	agent
+ @param
+	stateFile			状态文件
+ @return
+ 	map[string]int64	返回每个文件的偏移
+ 	error				读取失败时返回错误信息，文件不存在时不算失败
+ @history
+ 	2026-10-16_14:29 	agent		创建
+*******************************************************************************/
+func readState(stateFile string) (map[string]int64, error) {
+
+	offsets := map[string]int64{}
+	file, err := os.Open(stateFile)
+	if os.IsNotExist(err) {
+		return offsets, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("agent: open state: %v", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), " ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		n, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		offsets[parts[1]] = n
+	}
+
+	return offsets, scanner.Err()
+}
+
+/******************************************************************************
+ @brief
+ 	保存偏移状态文件，先写入临时文件再替换，避免写入一半时进程退出
+ @author
+ 	agent
+ @param
+	stateFile			状态文件
+	offsets				每个文件的偏移
+ @return
+ 	error				保存失败时返回错误信息
+ @history
+ 	2026-10-16_14:29 	agent		创建
+*******************************************************************************/
+func writeState(stateFile string, offsets map[string]int64) error {
+
+	files := make([]string, 0, len(offsets))
+	for fn := range offsets {
+		files = append(files, fn)
+	}
+	sort.Strings(files)
+
+	var buf bytes.Buffer
+	for _, fn := range files {
+		fmt.Fprintf(&buf, "%d %s\n", offsets[fn], fn)
+	}
+
+	tmp := stateFile + ".tmp"
+	if err := ioutil.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("agent: write state: %v", err)
+	}
+
+	return os.Rename(tmp, stateFile)
+}
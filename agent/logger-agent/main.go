@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/baickl/logger"
+	"github.com/baickl/logger/agent"
+)
+
+/******************************************************************************
+ @brief
+ 	日志转发代理独立进程，读取logger生成的日志文件并转发到网络输出目标
+ 		例：
+ 			logger-agent -pattern "./logs/????-??-??/LoginServer.*.log" -addr collector:5170
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:29 	agent		创建
+*******************************************************************************/
+func main() {
+
+	pattern := flag.String("pattern", "./logs/*/*.log", "log file pattern")
+	network := flag.String("network", "tcp", "network of the collector")
+	addr := flag.String("addr", "127.0.0.1:5170", "address of the collector")
+	state := flag.String("state", "./logger-agent.state", "offset state file")
+	interval := flag.Duration("interval", 5*time.Second, "scan interval")
+	ca := flag.String("ca", "", "CA file, enables TLS")
+	cert := flag.String("cert", "", "client certificate file")
+	key := flag.String("key", "", "client key file")
+	flag.Parse()
+
+	var cfg *logger.TLS_CONFIG
+	if len(*ca) > 0 {
+		cfg = &logger.TLS_CONFIG{CAFile: *ca, CertFile: *cert, KeyFile: *key}
+	}
+
+	w, err := logger.NewNetWriter(*network, *addr, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	a, err := agent.New(*pattern, w, *state)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	for {
+		if err := a.Scan(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		time.Sleep(*interval)
+	}
+}
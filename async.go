@@ -0,0 +1,542 @@
+package logger
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	异步写入队列满时的处理方式
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:11 	agent		创建
+ 	2026-10-16_14:45 	agent		增加丢弃并记录
+*******************************************************************************/
+type OVERFLOW_POLICY int
+
+const (
+	OVERFLOW_BLOCK       OVERFLOW_POLICY = iota //等待队列有空位，不丢日志
+	OVERFLOW_DROP                               //直接丢弃，写日志不会等待
+	OVERFLOW_DROP_RECORD                        //直接丢弃，之后在日志文件中记录丢弃的条数、时间范围和各级别的条数
+)
+
+const (
+	levelNone LEVEL = -1 //没有级别的日志行，例如事件日志
+)
+
+/******************************************************************************
+ @brief
+ 	异步写入队列压力回调，pressure为队列使用率，范围0到1
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+type PRESSURE_CALLBACK func(pressure float64)
+
+/******************************************************************************
+ @brief
+ 	队列压力回调的配置
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+type pressureConfig struct {
+	thresholds []float64         //使用率阈值，从小到大排序
+	callback   PRESSURE_CALLBACK //穿过阈值时的回调
+}
+
+/******************************************************************************
+ @brief
+ 	异步写入的一条记录
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+type asyncRecord struct {
+	f    *LOG_FILE     //日志文件，为nil表示这是Flush的标记
+	ll   LEVEL         //日志级别
+	b    []byte        //日志内容
+	done chan struct{} //Flush的标记处理完成后关闭
+}
+
+/******************************************************************************
+ @brief
+ 	一个日志文件在队列满时被丢弃的日志统计
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:45 	agent		创建
+*******************************************************************************/
+type asyncDrops struct {
+	count  int64           //丢弃的条数
+	first  time.Time       //第一条丢弃的时间
+	last   time.Time       //最后一条丢弃的时间
+	levels map[LEVEL]int64 //各级别丢弃的条数
+}
+
+/******************************************************************************
+ @brief
+ 	异步写入队列
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+type asyncQueue struct {
+	records chan asyncRecord //有界队列
+	policy  OVERFLOW_POLICY  //队列满时的处理方式
+	stopped chan struct{}    //后台协程退出后关闭
+	band    int              //当前使用率超过的阈值个数，只在后台协程中访问
+	notify  chan struct{}    //使用率穿过阈值时通知回调协程
+
+	dropLock    sync.Mutex                //丢弃统计锁
+	drops       map[*LOG_FILE]*asyncDrops //每个日志文件尚未记录的丢弃统计
+	dropPending int32                     //是否有尚未记录的丢弃统计
+}
+
+var (
+	asyncLock    sync.RWMutex //写入队列时加读锁，切换队列时加写锁
+	asyncCurrent *asyncQueue  //当前队列，为nil表示同步写入
+	asyncDropped int64        //队列满被丢弃的条数
+
+	asyncPressure atomic.Value //队列压力回调的配置，*pressureConfig
+)
+
+/******************************************************************************
+ @brief
+ 	开启或关闭异步写入，开启后写日志只把日志行放入有界队列，由后台协程写入日志文件，
+ 	写日志的协程不再等待磁盘；终端控制台和扩展输出目标不受影响。
+ 	Flush会等待队列中的日志写入完成，队列中的日志在切分时可能写入下一个文件
+ 		例：
+ 			logger.SetAsync(64*1024, logger.OVERFLOW_DROP)
+ @author
+ 	agent
+ @param
+	size				队列长度，小于等于0表示关闭异步写入，关闭时会先写入队列中的日志
+	policy				队列满时的处理方式，OVERFLOW_DROP和OVERFLOW_DROP_RECORD也会丢弃分类日志
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:11 	agent		创建
+ 	2026-10-16_14:11 	agent		启动队列压力回调协程
+*******************************************************************************/
+func SetAsync(size int, policy OVERFLOW_POLICY) {
+	asyncLock.Lock()
+	defer asyncLock.Unlock()
+
+	if old := asyncCurrent; old != nil {
+		close(old.records)
+		<-old.stopped
+		asyncCurrent = nil
+	}
+
+	if size <= 0 {
+		return
+	}
+
+	q := &asyncQueue{
+		records: make(chan asyncRecord, size),
+		policy:  policy,
+		stopped: make(chan struct{}),
+		notify:  make(chan struct{}, 1),
+	}
+	go q.run()
+	go q.notifyPressure()
+
+	asyncCurrent = q
+}
+
+/******************************************************************************
+ @brief
+ 	获取异步写入队列满时被丢弃的日志条数
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	int64				返回丢弃的条数
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+func AsyncDropped() int64 {
+	return atomic.LoadInt64(&asyncDropped)
+}
+
+/******************************************************************************
+ @brief
+ 	获取异步写入队列的使用率，队列中等待写入的条数可以通过AsyncQueued获取，
+ 	应用可以根据使用率减少自己的可选日志
+ 		例：
+ 			if logger.Pressure() < 0.5 {
+ 				logger.Debugf("cache miss %s", key)
+ 			}
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	float64				返回队列使用率，范围0到1，没有开启异步写入时返回0
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+func Pressure() float64 {
+	asyncLock.RLock()
+	defer asyncLock.RUnlock()
+
+	if asyncCurrent == nil {
+		return 0
+	}
+
+	return asyncCurrent.pressure(len(asyncCurrent.records))
+}
+
+/******************************************************************************
+ @brief
+ 	设置异步写入队列的压力回调，队列使用率上升或下降穿过任一阈值时调用，
+ 	回调在单独的协程中执行，可以写日志，连续穿过多个阈值时只回调最新的使用率
+ 		例：
+ 			logger.SetPressureCallback([]float64{0.5, 0.9}, func(pressure float64) {
+ 				verbose.Store(pressure < 0.5)
+ 			})
+ @author
+ 	agent
+ @param
+	thresholds			使用率阈值，范围0到1
+	callback			回调函数，nil表示关闭
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+func SetPressureCallback(thresholds []float64, callback PRESSURE_CALLBACK) {
+	cfg := &pressureConfig{callback: callback}
+	if callback != nil {
+		cfg.thresholds = append([]float64(nil), thresholds...)
+		sort.Float64s(cfg.thresholds)
+	}
+
+	asyncPressure.Store(cfg)
+}
+
+/******************************************************************************
+ @brief
+ 	将日志行放入异步写入队列
+ @author
+ 	agent
+ @param
+	f					日志文件
+	ll					日志级别，levelNone表示没有级别
+	b					日志内容，放入队列后调用者不能再修改
+ @return
+ 	bool				没有开启异步写入时返回false，由调用者同步写入
+ @history
+ 	2026-10-16_14:11 	agent		创建
+ 	2026-10-16_14:45 	agent		记录丢弃的日志级别
+*******************************************************************************/
+func asyncWrite(f *LOG_FILE, ll LEVEL, b []byte) bool {
+	asyncLock.RLock()
+	defer asyncLock.RUnlock()
+
+	q := asyncCurrent
+	if q == nil {
+		return false
+	}
+
+	r := asyncRecord{f: f, ll: ll, b: b}
+	if q.policy == OVERFLOW_BLOCK {
+		q.records <- r
+		return true
+	}
+
+	select {
+	case q.records <- r:
+	default:
+		atomic.AddInt64(&asyncDropped, 1)
+		if q.policy == OVERFLOW_DROP_RECORD {
+			q.recordDrop(f, ll)
+		}
+	}
+	return true
+}
+
+/******************************************************************************
+ @brief
+ 	统计一条被丢弃的日志
+ @author
+ 	agent
+ @param
+	f					日志文件
+	ll					日志级别
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:45 	agent		创建
+*******************************************************************************/
+func (q *asyncQueue) recordDrop(f *LOG_FILE, ll LEVEL) {
+	now := time.Now()
+
+	q.dropLock.Lock()
+	defer q.dropLock.Unlock()
+
+	if q.drops == nil {
+		q.drops = map[*LOG_FILE]*asyncDrops{}
+	}
+
+	d := q.drops[f]
+	if d == nil {
+		d = &asyncDrops{first: now, levels: map[LEVEL]int64{}}
+		q.drops[f] = d
+	}
+	d.count++
+	d.last = now
+	d.levels[ll]++
+
+	atomic.StoreInt32(&q.dropPending, 1)
+}
+
+/******************************************************************************
+ @brief
+ 	将尚未记录的丢弃统计写入各自的日志文件，只在后台协程中调用
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:45 	agent		创建
+*******************************************************************************/
+func (q *asyncQueue) writeDrops() {
+	if atomic.LoadInt32(&q.dropPending) == 0 {
+		return
+	}
+
+	q.dropLock.Lock()
+	drops := q.drops
+	q.drops = nil
+	atomic.StoreInt32(&q.dropPending, 0)
+	q.dropLock.Unlock()
+
+	for f, d := range drops {
+		f.writeFile(d.line())
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	生成丢弃记录的日志行，格式与WARN日志相同
+ 		例：
+ 			2026/10/18 08:30:00 WARN logger: async queue full, 120 entries dropped
+ 			from 2026/10/18 08:29:59.120 to 2026/10/18 08:30:00.005, levels INFO=100 WARN=20
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	[]byte				返回日志行
+ @history
+ 	2026-10-16_14:45 	agent		创建
+*******************************************************************************/
+func (d *asyncDrops) line() []byte {
+	levels := make([]LEVEL, 0, len(d.levels))
+	for ll := range d.levels {
+		levels = append(levels, ll)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+
+	histogram := make([]string, 0, len(levels))
+	for _, ll := range levels {
+		name := ll.String()
+		if ll == levelNone {
+			name = "OTHER"
+		}
+		histogram = append(histogram, fmt.Sprintf("%s=%d", name, d.levels[ll]))
+	}
+
+	const layout = "2006/01/02 15:04:05.000"
+	text := fmt.Sprintf("logger: async queue full, %d entries dropped from %s to %s, levels %s",
+		d.count, d.first.Format(layout), d.last.Format(layout), strings.Join(histogram, " "))
+
+	//丢弃记录没有调用者
+	flags := logLevelFlags[WARN] &^ (log.Lshortfile | log.Llongfile)
+	l := &logLine{flags: flags, t: time.Now(), context: fmt.Sprintf("%s %s", WARN, text)}
+	return l.bytes(lineFormat{precision: logFilePrecision, humanize: logFileHumanize, location: logFileLocation, packed: true, checksum: logFileChecksum})
+}
+
+/******************************************************************************
+ @brief
+ 	等待异步写入队列中已有的日志写入完成
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+func asyncFlush() {
+	asyncLock.RLock()
+	q := asyncCurrent
+	if q == nil {
+		asyncLock.RUnlock()
+		return
+	}
+
+	done := make(chan struct{})
+	q.records <- asyncRecord{done: done}
+	asyncLock.RUnlock()
+
+	<-done
+}
+
+/******************************************************************************
+ @brief
+ 	获取异步写入队列中等待写入的条数
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	int					返回等待写入的条数，没有开启异步写入时返回0
+ @history
+ 	2026-10-16_14:11 	agent		创建
+ 	2026-10-16_14:11 	agent		公开队列深度
+*******************************************************************************/
+func AsyncQueued() int {
+	asyncLock.RLock()
+	defer asyncLock.RUnlock()
+
+	if asyncCurrent == nil {
+		return 0
+	}
+
+	return len(asyncCurrent.records)
+}
+
+/******************************************************************************
+ @brief
+ 	后台协程，按顺序写入队列中的日志，队列关闭后退出
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:11 	agent		创建
+ 	2026-10-16_14:11 	agent		检查队列使用率是否穿过阈值
+ 	2026-10-16_14:45 	agent		写入丢弃记录
+*******************************************************************************/
+func (q *asyncQueue) run() {
+	defer close(q.stopped)
+
+	for r := range q.records {
+		//包含当前取出的这条
+		q.checkPressure(len(q.records) + 1)
+
+		if r.f == nil {
+			close(r.done)
+			continue
+		}
+
+		r.f.writeFile(r.b)
+		q.writeDrops()
+	}
+
+	q.checkPressure(0)
+	q.writeDrops()
+}
+
+/******************************************************************************
+ @brief
+ 	计算队列使用率
+ @author
+ 	agent
+ @param
+	depth				队列中的条数
+ @return
+ 	float64				返回使用率，范围0到1
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+func (q *asyncQueue) pressure(depth int) float64 {
+	if cap(q.records) == 0 {
+		return 0
+	}
+
+	return float64(depth) / float64(cap(q.records))
+}
+
+/******************************************************************************
+ @brief
+ 	检查队列使用率是否穿过阈值，穿过时通知回调协程，只在后台协程中调用
+ @author
+ 	agent
+ @param
+	depth				队列中的条数
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+func (q *asyncQueue) checkPressure(depth int) {
+	cfg, _ := asyncPressure.Load().(*pressureConfig)
+	if cfg == nil || cfg.callback == nil {
+		return
+	}
+
+	p := q.pressure(depth)
+	band := sort.Search(len(cfg.thresholds), func(i int) bool { return cfg.thresholds[i] > p })
+	if band == q.band {
+		return
+	}
+
+	q.band = band
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	队列压力回调协程，收到通知后使用最新的使用率调用回调，队列关闭后退出
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+func (q *asyncQueue) notifyPressure() {
+	for {
+		select {
+		case <-q.notify:
+		case <-q.stopped:
+			return
+		}
+
+		cfg, _ := asyncPressure.Load().(*pressureConfig)
+		if cfg == nil || cfg.callback == nil {
+			continue
+		}
+
+		func() {
+			defer catchError()
+			cfg.callback(q.pressure(len(q.records)))
+		}()
+	}
+}
@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 测试用的存储，打开日志文件时阻塞，直到放行
+type gateStorage struct {
+	STORAGE
+	entered chan struct{} //第一次打开日志文件时关闭
+	enter   sync.Once
+	gate    chan struct{} //关闭后放行
+}
+
+func (s *gateStorage) OpenFile(name string, flag int, perm os.FileMode) (STORAGE_FILE, error) {
+	if strings.HasSuffix(name, ".log") {
+		s.enter.Do(func() { close(s.entered) })
+		<-s.gate
+	}
+
+	return s.STORAGE.OpenFile(name, flag, perm)
+}
+
+// 异步写入队列满时丢弃日志，之后在日志文件中写入丢弃的条数和各级别的条数
+func TestAsyncDropRecord(t *testing.T) {
+	dir := setupStress(t)
+
+	gs := &gateStorage{STORAGE: logStorage, entered: make(chan struct{}), gate: make(chan struct{})}
+	SetStorage(gs)
+	released := false
+	release := func() {
+		if !released {
+			released = true
+			close(gs.gate)
+		}
+	}
+	defer release()
+
+	//切分后新的日志文件由第一次写入创建
+	Rotate()
+
+	SetAsync(1, OVERFLOW_DROP_RECORD)
+	defer SetAsync(0, OVERFLOW_BLOCK)
+
+	//后台协程写入第一条时阻塞在创建日志文件
+	Info("first")
+	select {
+	case <-gs.entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("background writer did not open the log file")
+	}
+
+	//队列只有一个位置，之后的日志都被丢弃
+	dropped := AsyncDropped()
+	Info("queued")
+	Info("dropped")
+	Info("dropped")
+	Warn("dropped")
+	if n := AsyncDropped() - dropped; n != 3 {
+		t.Fatalf("AsyncDropped grew by %d, want 3", n)
+	}
+
+	release()
+	Flush()
+
+	lines := readLines(t, dir, "stress")
+	if len(lines) != 3 {
+		t.Fatalf("log file has %d lines, want 3: %q", len(lines), lines)
+	}
+	//丢弃记录紧跟在阻塞期间写入的日志之后
+	if !strings.HasSuffix(lines[0], "INFO first") || !strings.HasSuffix(lines[2], "INFO queued") {
+		t.Fatalf("written entries %q", lines)
+	}
+	if !strings.Contains(lines[1], "WARN logger: async queue full, 3 entries dropped") || !strings.HasSuffix(lines[1], "levels INFO=2 WARN=1") {
+		t.Fatalf("drop record %q", lines[1])
+	}
+}
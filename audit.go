@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	auditCategory = "audit" //管理操作审计日志写入的分类
+)
+
+var (
+	auditRequired = []string{"source_ip", "target", "result"} //除操作者外必须提供的字段
+)
+
+/******************************************************************************
+ @brief
+ 	记录一条GM或管理员操作，统一写入audit分类日志，每条记录编码为一行JSON。
+ 	fields为键值对，必须包含source_ip、target、result，缺少任何必填字段时拒绝写入
+ 		例：
+ 			err := logger.AdminAction("gm_tom", "ban_player",
+ 				"source_ip", r.RemoteAddr,
+ 				"target", "player:10086",
+ 				"result", "ok",
+ 				"reason", "cheating")
+
+ 		输出：{"v":1,"time":"2026-10-17T08:30:00.000000+08:00","event":"admin","operator":"gm_tom","action":"ban_player","source_ip":"10.0.0.1","target":"player:10086","result":"ok","reason":"cheating"}
+ @author
+ 	agent
+ @param
+	operator			操作者
+	action				操作名称
+	fields				字段名称和值交替排列
+ @return
+ 	error				缺少必填字段或日志文件不可用时返回错误信息
+ @history
+ 	2026-10-16_14:56 	agent		创建
+*******************************************************************************/
+func AdminAction(operator, action string, fields ...string) error {
+
+	if len(fields)%2 != 0 {
+		return fmt.Errorf("logger: admin action %s has unpaired field %q", action, fields[len(fields)-1])
+	}
+
+	if len(strings.TrimSpace(operator)) == 0 || len(strings.TrimSpace(action)) == 0 {
+		return fmt.Errorf("logger: admin action needs operator and action")
+	}
+
+	values := map[string]string{}
+	for i := 0; i < len(fields); i += 2 {
+		switch fields[i] {
+		case "v", "time", "event", "operator", "action":
+			return fmt.Errorf("logger: admin action %s field name %q is reserved", action, fields[i])
+		}
+		if _, ok := values[fields[i]]; ok {
+			return fmt.Errorf("logger: admin action %s field %s is duplicated", action, fields[i])
+		}
+		values[fields[i]] = fields[i+1]
+	}
+
+	for _, key := range auditRequired {
+		if len(strings.TrimSpace(values[key])) == 0 {
+			return fmt.Errorf("logger: admin action %s field %s is required", action, key)
+		}
+	}
+
+	buf := []byte{'{'}
+	buf = appendJSON(buf, "v", FORMAT_VERSION)
+	buf = append(buf, ',')
+	buf = appendJSON(buf, "time", time.Now().Format("2006-01-02T15:04:05.000000Z07:00"))
+	buf = append(buf, ',')
+	buf = appendJSON(buf, "event", "admin")
+	buf = append(buf, ',')
+	buf = appendJSON(buf, "operator", operator)
+	buf = append(buf, ',')
+	buf = appendJSON(buf, "action", action)
+	for i := 0; i < len(fields); i += 2 {
+		buf = append(buf, ',')
+		buf = appendJSON(buf, fields[i], fields[i+1])
+	}
+	buf = append(buf, '}', '\n')
+
+	f := Category(auditCategory).file
+	if f.current() == nil {
+		return fmt.Errorf("logger: admin action %s has no log file, call Initialize first", action)
+	}
+
+	f.write(buf)
+
+	return nil
+}
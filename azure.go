@@ -0,0 +1,169 @@
+package logger
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var (
+	azureLogType = regexp.MustCompile(`^[A-Za-z0-9_]{1,100}$`)
+)
+
+/******************************************************************************
+ @brief
+ 	Azure Monitor输出目标类结构，通过Log Analytics的HTTP Data Collector API发送日志，
+ 	每次写入的日志行编码为一个JSON数组发送，配合AddSink的BUFFER_INTERVAL批量发送
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:16 	agent		创建
+*******************************************************************************/
+type AZURE_WRITER struct {
+	workspace string       //工作区ID
+	key       []byte       //工作区共享密钥
+	logType   string       //自定义日志类型，Log Analytics中的表名为logType_CL
+	url       string       //Data Collector API地址
+	client    *http.Client //HTTP客户端
+}
+
+/******************************************************************************
+ @brief
+ 	创建Azure Monitor输出目标
+ 		例：
+ 			w, err := logger.NewAzureWriter(workspaceID, sharedKey, "GameServer")
+ 			if err == nil {
+ 				logger.AddSink("azure", w, logger.BUFFER_INTERVAL, 1024*1024, 10*time.Second)
+ 			}
+
+ 		Log Analytics中的记录：TimeGenerated、Level、Caller、Func、Message和日志中的key=value字段，
+ 		表名为GameServer_CL
+ @author
+ 	agent
+ @param
+	workspace			工作区ID
+	sharedKey			工作区的主密钥或辅助密钥，base64格式
+	logType				自定义日志类型，只能包含字母、数字和下划线，最长100个字符
+ @return
+ 	*AZURE_WRITER		返回输出目标
+ 	error				参数无效时返回错误信息
+ @history
+ 	2026-10-16_15:16 	agent		创建
+*******************************************************************************/
+func NewAzureWriter(workspace, sharedKey, logType string) (*AZURE_WRITER, error) {
+	if len(workspace) == 0 {
+		return nil, fmt.Errorf("logger: empty azure workspace id")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(sharedKey)
+	if err != nil {
+		return nil, fmt.Errorf("logger: invalid azure shared key: %v", err)
+	}
+
+	if !azureLogType.MatchString(logType) {
+		return nil, fmt.Errorf("logger: invalid azure log type %q", logType)
+	}
+
+	return &AZURE_WRITER{
+		workspace: workspace,
+		key:       key,
+		logType:   logType,
+		url:       "https://" + workspace + ".ods.opinsights.azure.com/api/logs?api-version=2016-04-01",
+		client:    &http.Client{Timeout: netWriteTimeout},
+	}, nil
+}
+
+/******************************************************************************
+ @brief
+ 	解析日志行并发送到Log Analytics
+ @author
+ 	agent
+ @param
+	b					要写入的日志行
+ @return
+ 	int					返回写入的字节数
+ 	error				发送失败时返回错误信息
+ @history
+ 	2026-10-16_15:16 	agent		创建
+ 	2026-10-16_15:17 	agent		parseEntries返回原文
+*******************************************************************************/
+func (w *AZURE_WRITER) Write(b []byte) (int, error) {
+
+	entries, _ := parseEntries(b)
+	records := make([]map[string]string, 0, len(entries))
+	for _, e := range entries {
+		r := map[string]string{"Level": e.Level.String(), "Message": e.Msg}
+		if !e.Time.IsZero() {
+			r["TimeGenerated"] = e.Time.Format(time.RFC3339Nano)
+		}
+		if len(e.File) > 0 {
+			r["Caller"] = e.File + ":" + strconv.Itoa(e.Line)
+		}
+		if len(e.Func) > 0 {
+			r["Func"] = e.Func
+		}
+		for _, field := range e.Fields {
+			if _, ok := r[field[0]]; !ok {
+				r[field[0]] = field[1]
+			}
+		}
+		records = append(records, r)
+	}
+
+	body, err := json.Marshal(records)
+	if err != nil {
+		return 0, err
+	}
+
+	date := time.Now().UTC().Format(http.TimeFormat)
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Log-Type", w.logType)
+	req.Header.Set("x-ms-date", date)
+	req.Header.Set("time-generated-field", "TimeGenerated")
+	req.Header.Set("Authorization", w.signature(len(body), date))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return 0, fmt.Errorf("logger: azure data collector %s", resp.Status)
+	}
+
+	return len(b), nil
+}
+
+/******************************************************************************
+ @brief
+ 	生成Data Collector API的SharedKey签名
+ @author
+ 	agent
+ @param
+	length				请求内容长度
+	date				x-ms-date头的值
+ @return
+ 	string				返回Authorization头的值
+ @history
+ 	2026-10-16_15:16 	agent		创建
+*******************************************************************************/
+func (w *AZURE_WRITER) signature(length int, date string) string {
+	toSign := fmt.Sprintf("POST\n%d\napplication/json\nx-ms-date:%s\n/api/logs", length, date)
+
+	mac := hmac.New(sha256.New, w.key)
+	mac.Write([]byte(toSign))
+
+	return "SharedKey " + w.workspace + ":" + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+/******************************************************************************
+ @brief
+ 	程序的编译信息，由debug.ReadBuildInfo获取，不需要在编译时通过-ldflags设置
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:03 	agent		创建
+*******************************************************************************/
+type BUILD_INFO struct {
+	Version   string //主模块版本，本地编译时为(devel)
+	Revision  string //版本控制的提交号，没有时为空
+	Time      string //提交时间，没有时为空
+	Dirty     bool   //编译时工作区是否有未提交的修改
+	GoVersion string //编译使用的Go版本
+}
+
+var (
+	logBuildFields bool       //启动日志和异常报告是否带编译信息
+	buildOnce      sync.Once  //编译信息只读取一次
+	buildInfo      BUILD_INFO //编译信息
+)
+
+/******************************************************************************
+ @brief
+ 	设置是否在启动日志和异常报告中带上编译信息，默认关闭，开启后Initialize会写入一条启动日志
+ 		例：
+ 			logger.SetBuildFields(true)
+ 			logger.Initialize("./logs", "game")
+
+ 		输出：INFO startup version=v1.4.2 revision=9f1c2e7a dirty=false go=go1.22.5
+ @author
+ 	agent
+ @param
+	isBuildFields		是否带编译信息
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:03 	agent		创建
+*******************************************************************************/
+func SetBuildFields(isBuildFields bool) {
+	logBuildFields = isBuildFields
+}
+
+/******************************************************************************
+ @brief
+ 	获取程序的编译信息
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	BUILD_INFO			返回编译信息
+ @history
+ 	2026-10-16_15:03 	agent		创建
+*******************************************************************************/
+func Build() BUILD_INFO {
+	buildOnce.Do(func() {
+		buildInfo.GoVersion = runtime.Version()
+
+		info, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+
+		buildInfo.Version = info.Main.Version
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				buildInfo.Revision = s.Value
+			case "vcs.time":
+				buildInfo.Time = s.Value
+			case "vcs.modified":
+				buildInfo.Dirty = s.Value == "true"
+			}
+		}
+	})
+
+	return buildInfo
+}
+
+/******************************************************************************
+ @brief
+ 	生成编译信息字段
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	string				返回key=value形式的编译信息
+ @history
+ 	2026-10-16_15:03 	agent		创建
+*******************************************************************************/
+func buildFields() string {
+	b := Build()
+	fields := []string{
+		configField("version", b.Version),
+		configField("revision", b.Revision),
+		configField("dirty", strconv.FormatBool(b.Dirty)),
+		configField("go", b.GoVersion),
+	}
+
+	return strings.Join(fields, " ")
+}
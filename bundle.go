@@ -0,0 +1,243 @@
+package logger
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	问题收集包选项
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:10 	agent		创建
+*******************************************************************************/
+type BUNDLE_OPTIONS struct {
+	Since       time.Duration //收集最近多长时间内修改过的日志和异常文件，0表示24小时
+	MaxFileSize int64         //单个日志文件最多收集的字节数，超过时只收集末尾，0表示不限制
+	Config      interface{}   //配置快照，按LogConfig的规则展开，log:"secret"字段会被屏蔽，nil表示不收集
+}
+
+/******************************************************************************
+ @brief
+ 	生成问题收集包，将最近的日志文件、异常文件、配置快照、日志自身开销统计、
+ 	版本信息和日志自身的错误记录打包为zip写入w，统一独立服务器用户需要提供的内容
+ 		例：
+ 			f, _ := os.Create("support.zip")
+ 			defer f.Close()
+ 			logger.CollectSupportBundle(f, logger.BUNDLE_OPTIONS{Since: 48 * time.Hour, Config: cfg})
+
+ 		包内目录：
+ 			logs/...			日志文件，保留原有的相对路径
+ 			exceptions/...		异常文件和minidump
+ 			config.txt			配置快照
+ 			stats.txt			日志自身开销统计
+ 			build.txt			版本信息
+ 			errors.txt			日志自身的错误记录
+ @author
+ 	agent
+ @param
+	w					输出目标
+	opts				收集选项
+ @return
+ 	error				写入失败时返回错误信息，单个文件读取失败只记录到errors.txt
+ @history
+ 	2026-10-16_15:10 	agent		创建
+ 	2026-10-16_15:42 	agent		通过日志存储读取异常文件
+*******************************************************************************/
+func CollectSupportBundle(w io.Writer, opts BUNDLE_OPTIONS) error {
+
+	since := opts.Since
+	if since <= 0 {
+		since = 24 * time.Hour
+	}
+	from := time.Now().Add(-since)
+
+	zw := zip.NewWriter(w)
+	var failed []string
+
+	for _, fn := range Files(from) {
+		if err := bundleLog(zw, fn, opts.MaxFileSize); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", fn, err))
+		}
+	}
+
+	crashes, _ := logStorage.Glob(filepath.Join("exceptions", "????-??-??", "*"))
+	for _, fn := range crashes {
+		fi, err := logStorage.Stat(fn)
+		if err != nil || fi.IsDir() || fi.ModTime().Before(from) {
+			continue
+		}
+		if err := bundleFile(zw, fn); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", fn, err))
+		}
+	}
+
+	if opts.Config != nil {
+		fields := configFields("", reflect.ValueOf(opts.Config), nil)
+		if err := bundleText(zw, "config.txt", strings.Join(fields, "\n")+"\n"); err != nil {
+			return err
+		}
+	}
+
+	var stats strings.Builder
+	printStats(&stats)
+	if err := bundleText(zw, "stats.txt", stats.String()); err != nil {
+		return err
+	}
+
+	b := Build()
+	build := fmt.Sprintf("version=%s\nrevision=%s\ntime=%s\ndirty=%v\ngo=%s\nhost=%s\npid=%d\ncollected=%s\n",
+		b.Version, b.Revision, b.Time, b.Dirty, b.GoVersion, hostname(), os.Getpid(), time.Now().Format(time.RFC3339))
+	if err := bundleText(zw, "build.txt", build); err != nil {
+		return err
+	}
+
+	var errs strings.Builder
+	printDiagnostics(&errs)
+	for _, e := range failed {
+		fmt.Fprintf(&errs, "bundle %s\n", e)
+	}
+	if err := bundleText(zw, "errors.txt", errs.String()); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+/******************************************************************************
+ @brief
+ 	将日志文件加入问题收集包，超过大小上限时只收集末尾
+ @author
+ 	agent
+ @param
+	zw					zip输出
+	fn					日志文件路径
+	maxSize				最多收集的字节数，0表示不限制
+ @return
+ 	error				读取失败时返回错误信息
+ @history
+ 	2026-10-16_15:10 	agent		创建
+*******************************************************************************/
+func bundleLog(zw *zip.Writer, fn string, maxSize int64) error {
+	f, err := logStorage.OpenFile(fn, os.O_RDONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	start := int64(0)
+	if maxSize > 0 && fi.Size() > maxSize {
+		start = fi.Size() - maxSize
+	}
+
+	out, err := zw.CreateHeader(&zip.FileHeader{Name: bundleName("logs", fn), Method: zip.Deflate, Modified: fi.ModTime()})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(out, io.NewSectionReader(f, start, fi.Size()-start))
+	return err
+}
+
+/******************************************************************************
+ @brief
+ 	将异常文件加入问题收集包
+ @author
+ 	agent
+ @param
+	zw					zip输出
+	fn					文件路径
+ @return
+ 	error				读取失败时返回错误信息
+ @history
+ 	2026-10-16_15:10 	agent		创建
+ 	2026-10-16_15:42 	agent		通过日志存储读取
+*******************************************************************************/
+func bundleFile(zw *zip.Writer, fn string) error {
+	f, err := logStorage.OpenFile(fn, os.O_RDONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := zw.CreateHeader(&zip.FileHeader{Name: bundleName("", fn), Method: zip.Deflate, Modified: fi.ModTime()})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(out, f)
+	return err
+}
+
+/******************************************************************************
+ @brief
+ 	将文本加入问题收集包
+ @author
+ 	agent
+ @param
+	zw					zip输出
+	name				包内文件名
+	text				文本内容
+ @return
+ 	error				写入失败时返回错误信息
+ @history
+ 	2026-10-16_15:10 	agent		创建
+*******************************************************************************/
+func bundleText(zw *zip.Writer, name, text string) error {
+	out, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(out, text)
+	return err
+}
+
+/******************************************************************************
+ @brief
+ 	生成包内文件名，去掉盘符、开头的/和..，避免解压时写到包外
+ @author
+ 	agent
+ @param
+	prefix				包内目录，空字符串表示不加目录
+	fn					文件路径
+ @return
+ 	string				返回包内文件名
+ @history
+ 	2026-10-16_15:10 	agent		创建
+*******************************************************************************/
+func bundleName(prefix, fn string) string {
+	fn = filepath.Clean(fn)
+	fn = filepath.ToSlash(strings.TrimPrefix(fn, filepath.VolumeName(fn)))
+
+	parts := []string{}
+	for _, part := range strings.Split(fn, "/") {
+		if part != "" && part != "." && part != ".." {
+			parts = append(parts, part)
+		}
+	}
+
+	if len(prefix) > 0 {
+		parts = append([]string{prefix}, parts...)
+	}
+
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,246 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	会话日志抓取
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:56 	agent		创建
+*******************************************************************************/
+type captureSession struct {
+	sync.Mutex              //写入线程锁
+	id         string       //会话或玩家ID
+	path       string       //抓取文件路径
+	file       STORAGE_FILE //抓取文件
+	timer      *time.Timer  //到期后停止抓取
+}
+
+var (
+	captureLock  sync.Mutex   //抓取列表修改线程锁
+	captureValue atomic.Value //抓取列表[]*captureSession，修改时整体替换，写入日志不需要加锁
+)
+
+/******************************************************************************
+ @brief
+ 	抓取带有指定会话或玩家ID的日志，在duration时间内将字段值等于id的日志额外复制一份到
+ 	日志目录下的sessions/id.时间.log文件，方便客服定向排查问题。
+ 	id已经在抓取时只延长抓取时间，返回原来的文件
+ 		例：
+ 			path, err := logger.CaptureSession("10086", 30*time.Minute)
+
+ 			logger.With("player", "10086").Info("enter scene")	//会被抓取
+ 			logger.Infof("login uid=10086")						//会被抓取
+ 			logger.Infof("login uid=100861")					//不会被抓取
+ @author
+ 	agent
+ @param
+	id					会话或玩家ID
+	duration			抓取时间
+ @return
+ 	string				返回抓取文件路径
+ 	error				没有初始化日志文件或创建文件失败时返回错误信息
+ @history
+ 	2026-10-16_14:56 	agent		创建
+ 	2026-10-16_15:00 	agent		文件名转换改用safeName
+*******************************************************************************/
+func CaptureSession(id string, duration time.Duration) (string, error) {
+
+	if len(id) == 0 || duration <= 0 {
+		return "", fmt.Errorf("logger: capture needs a session id and a positive duration")
+	}
+
+	if logFile == nil {
+		return "", fmt.Errorf("logger: capture %s has no log directory, call Initialize first", id)
+	}
+
+	captureLock.Lock()
+	defer captureLock.Unlock()
+
+	for _, s := range captureList() {
+		if s.id == id {
+			s.timer.Reset(duration)
+			return s.path, nil
+		}
+	}
+
+	path := filepath.Join(logFile.log_dir, "sessions", fmt.Sprintf("%s.%s.log", safeName(id), time.Now().Format("2006-01-02_15_04_05")))
+	logStorage.MkdirAll(filepath.Dir(path), os.ModePerm)
+
+	file, err := logStorage.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, os.ModePerm)
+	if err != nil {
+		return "", fmt.Errorf("logger: capture %s: %v", id, err)
+	}
+
+	s := &captureSession{id: id, path: path, file: file}
+	s.timer = time.AfterFunc(duration, func() { StopCapture(id) })
+
+	sessions := captureList()
+	captureValue.Store(append(sessions[:len(sessions):len(sessions)], s))
+
+	return path, nil
+}
+
+/******************************************************************************
+ @brief
+ 	提前停止抓取会话日志
+ @author
+ 	agent
+ @param
+	id					会话或玩家ID
+ @return
+ 	string				返回抓取文件路径
+ 	bool				没有在抓取时返回false
+ @history
+ 	2026-10-16_14:56 	agent		创建
+*******************************************************************************/
+func StopCapture(id string) (string, bool) {
+	captureLock.Lock()
+	var session *captureSession
+	sessions := captureList()
+	for i, s := range sessions {
+		if s.id == id {
+			session = s
+			captureValue.Store(append(sessions[:i:i], sessions[i+1:]...))
+			break
+		}
+	}
+	captureLock.Unlock()
+
+	if session == nil {
+		return "", false
+	}
+
+	session.timer.Stop()
+
+	session.Lock()
+	defer session.Unlock()
+	session.file.Close()
+	session.file = nil
+
+	return session.path, true
+}
+
+/******************************************************************************
+ @brief
+ 	获取当前的抓取列表，返回的列表不能修改
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	[]*captureSession	返回抓取列表
+ @history
+ 	2026-10-16_14:56 	agent		创建
+*******************************************************************************/
+func captureList() []*captureSession {
+	sessions, _ := captureValue.Load().([]*captureSession)
+	return sessions
+}
+
+/******************************************************************************
+ @brief
+ 	将带有会话ID的日志行写入抓取文件，使用日志文件的格式
+ @author
+ 	agent
+ @param
+	l					日志行
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:56 	agent		创建
+*******************************************************************************/
+func captureWrite(l *logLine) {
+	sessions := captureList()
+	if len(sessions) == 0 {
+		return
+	}
+
+	var b []byte
+	for _, s := range sessions {
+		if !captureMatch(l.context, s.id) {
+			continue
+		}
+
+		if b == nil {
+			b = l.bytes(lineFormat{precision: logFilePrecision, humanize: logFileHumanize, location: logFileLocation})
+		}
+
+		s.Lock()
+		if s.file != nil {
+			s.file.Write(b)
+		}
+		s.Unlock()
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	日志内容中是否有值等于id的字段，例如player=10086、uid="10086"
+ @author
+ 	agent
+ @param
+	context				日志内容
+	id					会话或玩家ID
+ @return
+ 	bool				有时返回true
+ @history
+ 	2026-10-16_14:56 	agent		创建
+*******************************************************************************/
+func captureMatch(context, id string) bool {
+	for _, value := range []string{id, `"` + id + `"`} {
+		s := context
+		for {
+			i := strings.Index(s, "="+value)
+			if i < 0 {
+				break
+			}
+
+			end := i + 1 + len(value)
+			if end == len(s) || strings.IndexByte(" \t\r\n,;)]}", s[end]) >= 0 || s[end] == humanMark {
+				return true
+			}
+			s = s[i+1:]
+		}
+	}
+
+	return false
+}
+
+/******************************************************************************
+ @brief
+ 	将字符串转换为可以作为文件名或目录名的形式，只保留字母、数字和-_.，其它字符替换为下划线
+ @author
+ 	agent
+ @param
+	s					原始字符串
+ @return
+ 	string				返回转换后的字符串
+ @history
+ 	2026-10-16_15:00 	agent		创建，从CaptureSession拆分
+*******************************************************************************/
+func safeName(s string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, s)
+
+	//不能是.或..
+	if name == "." || name == ".." {
+		return strings.Repeat("_", len(name))
+	}
+	return name
+}
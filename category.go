@@ -0,0 +1,425 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+)
+
+/******************************************************************************
+ @brief
+ 	分类日志类结构，分类日志写入同一日期目录下以分类名称命名的单独文件，
+ 	文件切分规则与主日志文件相同，同时也会输出到扩展输出目标和终端控制台
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:25 	agent		创建
+ 	2026-10-16_14:27 	agent		支持只读保留模式
+ 	2026-10-16_14:43 	agent		分类日志文件创建后不再替换，写入时不需要加锁
+*******************************************************************************/
+type CATEGORY struct {
+	name string    //分类名称
+	file *LOG_FILE //分类日志文件，Initialize之前没有文件句柄
+}
+
+var (
+	categoryLock sync.RWMutex         //分类日志线程锁
+	categories   map[string]*CATEGORY //分类日志列表
+)
+
+/******************************************************************************
+ @brief
+ 	获取分类日志，同名分类返回同一个实例，可以在Initialize之前调用
+ 		例：
+ 			battle := logger.Category("battle")
+ 			battle.Infof("room %d start, players %v", roomID, players)
+
+ 		日志文件为：
+ 			./logs/2026-10-16/battle.18_50_00.log
+ @author
+ 	agent
+ @param
+	name				分类名称，同时作为日志文件基础名字
+ @return
+ 	*CATEGORY			返回分类日志
+ @history
+ 	2026-10-16_14:25 	agent		创建
+ 	2026-10-16_14:43 	agent		分类日志文件创建后不再替换
+*******************************************************************************/
+func Category(name string) *CATEGORY {
+	categoryLock.Lock()
+	defer categoryLock.Unlock()
+
+	if c, ok := categories[name]; ok {
+		return c
+	}
+
+	if categories == nil {
+		categories = map[string]*CATEGORY{}
+	}
+
+	c := &CATEGORY{name: name, file: &LOG_FILE{log_filename: name}}
+	if f := logFile; f != nil && !logFileOff {
+		c.file.start(f.log_dir)
+	}
+	categories[name] = c
+
+	return c
+}
+
+/******************************************************************************
+ @brief
+ 	为已经获取的分类日志创建日志文件，由Initialize调用
+ @author
+ 	agent
+ @param
+	dir					日志存放目录
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:25 	agent		创建
+ 	2026-10-16_14:43 	agent		分类日志文件创建后不再替换
+*******************************************************************************/
+func initCategories(dir string) {
+	categoryLock.Lock()
+	defer categoryLock.Unlock()
+
+	for _, c := range categories {
+		c.file.start(dir)
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	在日志目录下开始写入分类日志文件，第一次写入时才创建文件
+ @author
+ 	agent
+ @param
+	dir					日志存放目录
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:25 	agent		创建
+ 	2026-10-16_14:27 	agent		支持只读保留模式
+ 	2026-10-16_14:43 	agent		改为替换已有分类日志文件的句柄
+ 	2026-10-16_14:49 	agent		新文件写入文件头
+ 	2026-10-16_15:05 	agent		平滑升级时继续写入旧进程的文件
+*******************************************************************************/
+func (f *LOG_FILE) start(dir string) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.log_dir = dir
+	f.timestamp = rotateTimestamp()
+
+	h := &fileHandle{header: &f.header}
+	if diskCheck(dir) {
+		h.path = handoverPath(f)
+		if len(h.path) == 0 {
+			h.path = f.newlogfile()
+		}
+	}
+
+	if old := f.swap(h); old != nil {
+		old.close()
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	获取所有分类日志文件，用于文件监控
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	[]*LOG_FILE			返回日志文件列表
+ @history
+ 	2026-10-16_14:25 	agent		创建
+ 	2026-10-16_14:43 	agent		只返回已经开始写入的文件
+*******************************************************************************/
+func categoryFiles() []*LOG_FILE {
+	categoryLock.RLock()
+	defer categoryLock.RUnlock()
+
+	files := make([]*LOG_FILE, 0, len(categories))
+	for _, c := range categories {
+		if c.file.current() != nil {
+			files = append(files, c.file)
+		}
+	}
+
+	return files
+}
+
+/******************************************************************************
+ @brief
+ 	输出分类日志，仅供内部使用
+ @author
+ 	agent
+ @param
+	ll					日志等级
+	arg					要输出的内容
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:25 	agent		创建
+ 	2026-10-16_14:43 	agent		写入不再加锁
+*******************************************************************************/
+func (c *CATEGORY) output(ll LEVEL, arg string) {
+	outputFile(c.file, ll, arg, nil)
+}
+
+/******************************************************************************
+ @brief
+ 	输出Debug分类日志
+ @author
+ 	agent
+ @see
+ 	logger.Debug
+ @history
+ 	2026-10-16_14:25 	agent		创建
+*******************************************************************************/
+func (c *CATEGORY) Debug(arg interface{}) {
+	defer catchError()
+	if currentLevel() <= DEBUG {
+		c.output(DEBUG, fmt.Sprintln(arg))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Info分类日志
+ @author
+ 	agent
+ @see
+ 	logger.Info
+ @history
+ 	2026-10-16_14:25 	agent		创建
+*******************************************************************************/
+func (c *CATEGORY) Info(arg interface{}) {
+	defer catchError()
+	if currentLevel() <= INFO {
+		c.output(INFO, fmt.Sprintln(arg))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Warn分类日志
+ @author
+ 	agent
+ @see
+ 	logger.Warn
+ @history
+ 	2026-10-16_14:25 	agent		创建
+*******************************************************************************/
+func (c *CATEGORY) Warn(arg interface{}) {
+	defer catchError()
+	if currentLevel() <= WARN {
+		c.output(WARN, fmt.Sprintln(arg))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Error分类日志
+ @author
+ 	agent
+ @see
+ 	logger.Error
+ @history
+ 	2026-10-16_14:25 	agent		创建
+*******************************************************************************/
+func (c *CATEGORY) Error(arg interface{}) {
+	defer catchError()
+	if currentLevel() <= ERROR {
+		c.output(ERROR, fmt.Sprintln(arg))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Fatal分类日志
+ @author
+ 	agent
+ @see
+ 	logger.Fatal
+ @history
+ 	2026-10-16_14:25 	agent		创建
+*******************************************************************************/
+func (c *CATEGORY) Fatal(arg interface{}) {
+	defer catchError()
+	if currentLevel() <= FATAL {
+		c.output(FATAL, fmt.Sprintln(arg))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Debug分类日志
+ @author
+ 	agent
+ @see
+ 	logger.Debugf
+ @history
+ 	2026-10-16_14:25 	agent		创建
+*******************************************************************************/
+func (c *CATEGORY) Debugf(format string, args ...interface{}) {
+	defer catchError()
+	if currentLevel() <= DEBUG {
+		c.output(DEBUG, fmt.Sprintf(format, args...))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Info分类日志
+ @author
+ 	agent
+ @see
+ 	logger.Infof
+ @history
+ 	2026-10-16_14:25 	agent		创建
+*******************************************************************************/
+func (c *CATEGORY) Infof(format string, args ...interface{}) {
+	defer catchError()
+	if currentLevel() <= INFO {
+		c.output(INFO, fmt.Sprintf(format, args...))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Warn分类日志
+ @author
+ 	agent
+ @see
+ 	logger.Warnf
+ @history
+ 	2026-10-16_14:25 	agent		创建
+*******************************************************************************/
+func (c *CATEGORY) Warnf(format string, args ...interface{}) {
+	defer catchError()
+	if currentLevel() <= WARN {
+		c.output(WARN, fmt.Sprintf(format, args...))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Error分类日志
+ @author
+ 	agent
+ @see
+ 	logger.Errorf
+ @history
+ 	2026-10-16_14:25 	agent		创建
+*******************************************************************************/
+func (c *CATEGORY) Errorf(format string, args ...interface{}) {
+	defer catchError()
+	if currentLevel() <= ERROR {
+		c.output(ERROR, fmt.Sprintf(format, args...))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Fatal分类日志
+ @author
+ 	agent
+ @see
+ 	logger.Fatalf
+ @history
+ 	2026-10-16_14:25 	agent		创建
+*******************************************************************************/
+func (c *CATEGORY) Fatalf(format string, args ...interface{}) {
+	defer catchError()
+	if currentLevel() <= FATAL {
+		c.output(FATAL, fmt.Sprintf(format, args...))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Debug分类日志
+ @author
+ 	agent
+ @see
+ 	logger.Debugln
+ @history
+ 	2026-10-16_14:25 	agent		创建
+*******************************************************************************/
+func (c *CATEGORY) Debugln(args ...interface{}) {
+	defer catchError()
+	if currentLevel() <= DEBUG {
+		c.output(DEBUG, fmt.Sprintln(args...))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Info分类日志
+ @author
+ 	agent
+ @see
+ 	logger.Infoln
+ @history
+ 	2026-10-16_14:25 	agent		创建
+*******************************************************************************/
+func (c *CATEGORY) Infoln(args ...interface{}) {
+	defer catchError()
+	if currentLevel() <= INFO {
+		c.output(INFO, fmt.Sprintln(args...))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Warn分类日志
+ @author
+ 	agent
+ @see
+ 	logger.Warnln
+ @history
+ 	2026-10-16_14:25 	agent		创建
+*******************************************************************************/
+func (c *CATEGORY) Warnln(args ...interface{}) {
+	defer catchError()
+	if currentLevel() <= WARN {
+		c.output(WARN, fmt.Sprintln(args...))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Error分类日志
+ @author
+ 	agent
+ @see
+ 	logger.Errorln
+ @history
+ 	2026-10-16_14:25 	agent		创建
+*******************************************************************************/
+func (c *CATEGORY) Errorln(args ...interface{}) {
+	defer catchError()
+	if currentLevel() <= ERROR {
+		c.output(ERROR, fmt.Sprintln(args...))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Fatal分类日志
+ @author
+ 	agent
+ @see
+ 	logger.Fatalln
+ @history
+ 	2026-10-16_14:25 	agent		创建
+*******************************************************************************/
+func (c *CATEGORY) Fatalln(args ...interface{}) {
+	defer catchError()
+	if currentLevel() <= FATAL {
+		c.output(FATAL, fmt.Sprintln(args...))
+	}
+}
@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"bufio"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	logFileChecksum bool //日志文件的每行末尾是否输出CRC32校验值
+
+	lineChecksum = regexp.MustCompile(` #([0-9a-f]{8})$`)
+)
+
+/******************************************************************************
+ @brief
+ 	设置日志文件的每条日志末尾是否输出CRC32校验值，崩溃或磁盘故障后被截断、损坏的日志
+ 	可以通过VerifyChecksum或logger-verify工具检查出来。校验值覆盖整条日志，
+ 	包括日志头和多行日志中的换行
+ 		例：
+ 			logger.SetFileChecksum(true)
+
+ 		日志文件：2026/10/17 18:30:00.000001 main.go:12: INFO started #1c291ca3
+ @author
+ 	agent
+ @param
+	isChecksum			是否输出校验值
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:12 	agent		创建
+*******************************************************************************/
+func SetFileChecksum(isChecksum bool) {
+	logFileChecksum = isChecksum
+}
+
+/******************************************************************************
+ @brief
+ 	检查日志文件中每条日志的校验值
+ 		例：
+ 			bad, err := logger.VerifyChecksum("./log/2026-10-17/gameserver.18_30_00.log")
+ 			for _, n := range bad {
+ 				fmt.Println("corrupted entry at line", n)
+ 			}
+ @author
+ 	agent
+ @param
+	fn					日志文件路径
+ @return
+ 	[]int				返回校验失败的日志所在的起始行号，从1开始
+ 	error				读取文件失败时返回错误信息
+ @history
+ 	2026-10-16_15:12 	agent		创建
+*******************************************************************************/
+func VerifyChecksum(fn string) ([]int, error) {
+	f, err := logStorage.OpenFile(fn, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return verifyChecksum(f)
+}
+
+/******************************************************************************
+ @brief
+ 	逐行检查校验值，没有校验值的行属于多行日志，与后面的行合并后校验
+ @author
+ 	agent
+ @param
+	r					日志内容
+ @return
+ 	[]int				返回校验失败的日志所在的起始行号
+ 	error				读取失败时返回错误信息
+ @history
+ 	2026-10-16_15:12 	agent		创建
+*******************************************************************************/
+func verifyChecksum(r io.Reader) ([]int, error) {
+
+	bad := []int{}
+	br := bufio.NewReader(r)
+	entry, start, n := "", 0, 0
+	for {
+		line, err := br.ReadString('\n')
+		if len(line) > 0 {
+			n++
+			if len(entry) == 0 {
+				start = n
+			}
+
+			entry += line
+			if !lineChecksum.MatchString(strings.TrimSuffix(line, "\n")) {
+				continue
+			}
+
+			if _, ok := splitChecksum(strings.TrimSuffix(entry, "\n")); !ok {
+				bad = append(bad, start)
+			}
+			entry = ""
+		}
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return bad, err
+		}
+	}
+
+	//文件末尾没有校验值的日志被截断了
+	if len(entry) > 0 {
+		bad = append(bad, start)
+	}
+
+	return bad, nil
+}
+
+/******************************************************************************
+ @brief
+ 	生成日志行的校验值后缀
+ @author
+ 	agent
+ @param
+	line				不带行尾换行符的日志行
+ @return
+ 	string				返回校验值后缀
+ @history
+ 	2026-10-16_15:12 	agent		创建
+*******************************************************************************/
+func checksumSuffix(line []byte) string {
+	return fmt.Sprintf(" #%08x", crc32.ChecksumIEEE(line))
+}
+
+/******************************************************************************
+ @brief
+ 	去掉日志行末尾的校验值
+ @author
+ 	agent
+ @param
+	s					不带行尾换行符的日志行
+ @return
+ 	string				返回去掉校验值后的日志行
+ 	bool				有校验值并且校验通过时返回true
+ @history
+ 	2026-10-16_15:12 	agent		创建
+*******************************************************************************/
+func splitChecksum(s string) (string, bool) {
+	m := lineChecksum.FindStringSubmatchIndex(s)
+	if m == nil {
+		return s, false
+	}
+
+	sum, _ := strconv.ParseUint(s[m[2]:m[3]], 16, 32)
+	if crc32.ChecksumIEEE([]byte(s[:m[0]])) != uint32(sum) {
+		return s, false
+	}
+
+	return s[:m[0]], true
+}
@@ -0,0 +1,176 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	childIdle    = 100 * time.Millisecond //没有换行的输出超过该时间没有后续内容时作为一行输出
+	childMaxLine = 64 * 1024              //单行最大长度，超过时直接输出
+)
+
+/******************************************************************************
+ @brief
+ 	子进程输出的逐行转发
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:22 	agent		创建
+*******************************************************************************/
+type childWriter struct {
+	sync.Mutex             //线程锁
+	log        *TAG_LOG    //带child、stream字段的日志
+	level      LEVEL       //日志级别
+	buf        []byte      //还没有换行的内容
+	timer      *time.Timer //没有换行的内容的输出定时器
+}
+
+/******************************************************************************
+ @brief
+ 	将子进程的标准输出和标准错误逐行写入日志，每行带有child字段为程序名，
+ 	stream字段为stdout或stderr，服务器启动的辅助工具的输出与服务器日志写入同一个文件。
+ 	cmd已经设置了Stdout或Stderr时同时写入原来的目标；
+ 	level高于ERROR时按ERROR输出，子进程的输出不会导致本进程退出
+ 		例：
+ 			cmd := exec.Command("./geoip-updater", "-once")
+ 			logger.WrapCmd(cmd, logger.INFO)
+ 			err := cmd.Run()
+
+ 		输出：INFO downloading GeoLite2-City.mmdb child=geoip-updater stream=stdout
+ @author
+ 	agent
+ @param
+	cmd					还没有启动的子进程
+	level				子进程输出的日志级别
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:22 	agent		创建
+*******************************************************************************/
+func WrapCmd(cmd *exec.Cmd, level LEVEL) {
+	if level > ERROR {
+		level = ERROR
+	}
+
+	name := strings.TrimSuffix(filepath.Base(cmd.Path), ".exe")
+	t := With("child", name)
+
+	cmd.Stdout = childTee(cmd.Stdout, &childWriter{log: t.With("stream", "stdout"), level: level})
+	cmd.Stderr = childTee(cmd.Stderr, &childWriter{log: t.With("stream", "stderr"), level: level})
+}
+
+/******************************************************************************
+ @brief
+ 	与原来的输出目标合并
+ @author
+ 	agent
+ @param
+	old					原来的输出目标，可以为nil
+	w					逐行转发
+ @return
+ 	io.Writer			返回合并后的输出目标
+ @history
+ 	2026-10-16_15:22 	agent		创建
+*******************************************************************************/
+func childTee(old io.Writer, w *childWriter) io.Writer {
+	if old == nil {
+		return w
+	}
+
+	return io.MultiWriter(old, w)
+}
+
+/******************************************************************************
+ @brief
+ 	接收子进程输出，完整的行立即写入日志，剩下的内容等待换行或超时
+ @author
+ 	agent
+ @param
+	b					子进程输出
+ @return
+ 	int					返回写入的字节数
+ 	error				总是返回nil
+ @history
+ 	2026-10-16_15:22 	agent		创建
+*******************************************************************************/
+func (w *childWriter) Write(b []byte) (int, error) {
+	w.Lock()
+	defer w.Unlock()
+
+	w.buf = append(w.buf, b...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+
+	if len(w.buf) >= childMaxLine {
+		w.emit(w.buf)
+		w.buf = nil
+	}
+
+	//子进程退出时最后一行可能没有换行，超时后输出
+	if len(w.buf) > 0 {
+		if w.timer == nil {
+			w.timer = time.AfterFunc(childIdle, w.flush)
+		} else {
+			w.timer.Reset(childIdle)
+		}
+	}
+
+	return len(b), nil
+}
+
+/******************************************************************************
+ @brief
+ 	输出没有换行的剩余内容
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:22 	agent		创建
+*******************************************************************************/
+func (w *childWriter) flush() {
+	w.Lock()
+	defer w.Unlock()
+
+	if len(w.buf) > 0 {
+		w.emit(w.buf)
+		w.buf = nil
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出一行，忽略空行，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	line				一行内容，不带换行
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:22 	agent		创建
+*******************************************************************************/
+func (w *childWriter) emit(line []byte) {
+	defer catchError()
+
+	s := strings.TrimRight(string(line), "\r")
+	if len(strings.TrimSpace(s)) == 0 || currentLevel() > w.level {
+		return
+	}
+
+	w.log.output(w.level, s+"\n")
+}
@@ -0,0 +1,306 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+type COLOR_MODE int //终端颜色支持能力
+type RGB int        //24位颜色，例如0xFF8800
+
+const (
+	COLOR_MODE_NONE      COLOR_MODE = iota //不支持颜色
+	COLOR_MODE_16                          //支持16色
+	COLOR_MODE_256                         //支持256色
+	COLOR_MODE_TRUECOLOR                   //支持24位真彩色
+)
+
+const (
+	RGB_DEFAULT = RGB(-1) //终端默认颜色
+)
+
+var (
+	colorModeOnce sync.Once  //颜色支持能力只检测一次
+	colorMode     COLOR_MODE //颜色支持能力
+)
+
+/******************************************************************************
+ @brief
+ 	检测终端的颜色支持能力，根据NO_COLOR、COLORTERM和TERM环境变量判断
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	COLOR_MODE			返回颜色支持能力
+ @history
+ 	2026-10-16_14:15 	agent		创建
+*******************************************************************************/
+func ColorSupport() COLOR_MODE {
+	colorModeOnce.Do(func() {
+		colorMode = detectColorMode()
+	})
+
+	return colorMode
+}
+
+/******************************************************************************
+ @brief
+ 	手动指定终端的颜色支持能力，覆盖自动检测的结果
+ @author
+ 	agent
+ @param
+	mode				颜色支持能力
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:15 	agent		创建
+*******************************************************************************/
+func SetColorMode(mode COLOR_MODE) {
+	colorModeOnce.Do(func() {})
+	colorMode = mode
+}
+
+/******************************************************************************
+ @brief
+ 	用256色来显示字符串，终端不支持256色时降级为16色，不支持颜色时原样返回
+ @author
+ 	agent
+ @param
+	str					待显示的字符串
+	s					显示样式
+	fc					显示前景色，0~255，小于0表示默认颜色
+	bc					显示背景色，0~255，小于0表示默认颜色
+ @return
+ 	string				返回生成的格式化字符
+ @history
+ 	2026-10-16_14:15 	agent		创建
+*******************************************************************************/
+func SprintColor256(str string, s STYLE, fc, bc int) string {
+
+	switch ColorSupport() {
+	case COLOR_MODE_NONE:
+		return str
+	case COLOR_MODE_16:
+		return SprintColor(str, s, basicColor(fc, color256ToRGB), basicColor(bc, color256ToRGB))
+	}
+
+	return fmt.Sprintf("%c[%dm%s%s%s%c[0m", 0x1B, int(s), sgr256(38, fc), sgr256(48, bc), str, 0x1B)
+}
+
+/******************************************************************************
+ @brief
+ 	用24位真彩色来显示字符串，终端不支持真彩色时降级为256色或16色，不支持颜色时原样返回
+ 		例：
+ 			logger.SprintColorRGB("LoginServer", logger.STYLE_HIGHLIGHT, logger.RGB(0xFF8800), logger.RGB_DEFAULT)
+ @author
+ 	agent
+ @param
+	str					待显示的字符串
+	s					显示样式
+	fc					显示前景色，RGB_DEFAULT表示默认颜色
+	bc					显示背景色，RGB_DEFAULT表示默认颜色
+ @return
+ 	string				返回生成的格式化字符
+ @history
+ 	2026-10-16_14:15 	agent		创建
+*******************************************************************************/
+func SprintColorRGB(str string, s STYLE, fc, bc RGB) string {
+
+	switch ColorSupport() {
+	case COLOR_MODE_NONE:
+		return str
+	case COLOR_MODE_16:
+		return SprintColor(str, s, basicColor(int(fc), func(c int) RGB { return RGB(c) }), basicColor(int(bc), func(c int) RGB { return RGB(c) }))
+	case COLOR_MODE_256:
+		return SprintColor256(str, s, rgbTo256(fc), rgbTo256(bc))
+	}
+
+	return fmt.Sprintf("%c[%dm%s%s%s%c[0m", 0x1B, int(s), sgrRGB(38, fc), sgrRGB(48, bc), str, 0x1B)
+}
+
+/******************************************************************************
+ @brief
+ 	根据环境变量检测终端的颜色支持能力
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	COLOR_MODE			返回颜色支持能力
+ @history
+ 	2026-10-16_14:15 	agent		创建
+*******************************************************************************/
+func detectColorMode() COLOR_MODE {
+
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return COLOR_MODE_NONE
+	}
+
+	colorterm := strings.ToLower(os.Getenv("COLORTERM"))
+	if colorterm == "truecolor" || colorterm == "24bit" {
+		return COLOR_MODE_TRUECOLOR
+	}
+
+	term := strings.ToLower(os.Getenv("TERM"))
+	switch {
+	case term == "dumb":
+		return COLOR_MODE_NONE
+	case strings.Contains(term, "256color"):
+		return COLOR_MODE_256
+	}
+
+	return COLOR_MODE_16
+}
+
+/******************************************************************************
+ @brief
+ 	生成256色的控制序列
+ @author
+ 	agent
+ @param
+	base				38表示前景色，48表示背景色
+	c					颜色，小于0表示默认颜色
+ @return
+ 	string				返回控制序列
+ @history
+ 	2026-10-16_14:15 	agent		创建
+*******************************************************************************/
+func sgr256(base, c int) string {
+	if c < 0 || c > 255 {
+		return ""
+	}
+
+	return fmt.Sprintf("%c[%d;5;%dm", 0x1B, base, c)
+}
+
+/******************************************************************************
+ @brief
+ 	生成24位真彩色的控制序列
+ @author
+ 	agent
+ @param
+	base				38表示前景色，48表示背景色
+	c					颜色，RGB_DEFAULT表示默认颜色
+ @return
+ 	string				返回控制序列
+ @history
+ 	2026-10-16_14:15 	agent		创建
+*******************************************************************************/
+func sgrRGB(base int, c RGB) string {
+	if c < 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%c[%d;2;%d;%d;%dm", 0x1B, base, (c>>16)&0xFF, (c>>8)&0xFF, c&0xFF)
+}
+
+/******************************************************************************
+ @brief
+ 	将24位颜色转换为最接近的256色
+ @author
+ 	agent
+ @param
+	c					24位颜色
+ @return
+ 	int					返回256色，默认颜色返回-1
+ @history
+ 	2026-10-16_14:15 	agent		创建
+*******************************************************************************/
+func rgbTo256(c RGB) int {
+	if c < 0 {
+		return -1
+	}
+
+	r, g, b := int(c>>16)&0xFF, int(c>>8)&0xFF, int(c)&0xFF
+
+	//灰度使用232~255
+	if r == g && g == b {
+		if r < 8 {
+			return 16
+		}
+		if r > 248 {
+			return 231
+		}
+		return 232 + (r-8)*24/247
+	}
+
+	//彩色使用16~231的6x6x6色块
+	return 16 + 36*(r*5/255) + 6*(g*5/255) + b*5/255
+}
+
+/******************************************************************************
+ @brief
+ 	将256色转换为24位颜色
+ @author
+ 	agent
+ @param
+	c					256色
+ @return
+ 	RGB					返回24位颜色，默认颜色返回RGB_DEFAULT
+ @history
+ 	2026-10-16_14:15 	agent		创建
+*******************************************************************************/
+func color256ToRGB(c int) RGB {
+
+	basic := [16]RGB{
+		0x000000, 0x800000, 0x008000, 0x808000, 0x000080, 0x800080, 0x008080, 0xC0C0C0,
+		0x808080, 0xFF0000, 0x00FF00, 0xFFFF00, 0x0000FF, 0xFF00FF, 0x00FFFF, 0xFFFFFF,
+	}
+
+	switch {
+	case c < 0 || c > 255:
+		return RGB_DEFAULT
+	case c < 16:
+		return basic[c]
+	case c < 232:
+		c -= 16
+		level := func(n int) RGB {
+			if n == 0 {
+				return 0
+			}
+			return RGB(55 + n*40)
+		}
+		return level(c/36)<<16 | level(c/6%6)<<8 | level(c%6)
+	}
+
+	gray := RGB(8 + (c-232)*10)
+	return gray<<16 | gray<<8 | gray
+}
+
+/******************************************************************************
+ @brief
+ 	将颜色转换为最接近的基本颜色，用于只支持16色的终端
+ @author
+ 	agent
+ @param
+	c					颜色
+	toRGB				将颜色转换为24位颜色的函数
+ @return
+ 	COLOR				返回基本颜色，默认颜色返回CLR_DEFAULT
+ @history
+ 	2026-10-16_14:15 	agent		创建
+*******************************************************************************/
+func basicColor(c int, toRGB func(c int) RGB) COLOR {
+
+	rgb := toRGB(c)
+	if rgb < 0 {
+		return CLR_DEFAULT
+	}
+
+	//每个通道超过一半认为有该颜色分量，顺序与CLR_BLACK~CLR_WHITE一致
+	n := 0
+	if (rgb>>16)&0xFF >= 0x80 {
+		n |= 1
+	}
+	if (rgb>>8)&0xFF >= 0x80 {
+		n |= 2
+	}
+	if rgb&0xFF >= 0x80 {
+		n |= 4
+	}
+
+	return CLR_BLACK + COLOR(n)
+}
@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sync"
+)
+
+type COMPRESSOR func(w io.Writer) io.WriteCloser //压缩器创建函数
+
+var (
+	compressorLock sync.RWMutex             //压缩算法列表线程锁
+	compressors    = map[string]COMPRESSOR{ //压缩算法列表
+		"gzip": func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		"zlib": func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) },
+	}
+)
+
+/******************************************************************************
+ @brief
+ 	注册压缩算法，内置gzip和zlib，其它算法（例如zstd、snappy）可以由使用者注册
+ 		例：
+ 			logger.RegisterCompressor("zstd", func(w io.Writer) io.WriteCloser {
+ 				enc, _ := zstd.NewWriter(w)
+ 				return enc
+ 			})
+ @author
+ 	agent
+ @param
+	codec				压缩算法名称
+	fn					创建压缩器的函数，每次写入都会创建新的压缩器
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:12 	agent		创建
+*******************************************************************************/
+func RegisterCompressor(codec string, fn COMPRESSOR) {
+	compressorLock.Lock()
+	defer compressorLock.Unlock()
+
+	compressors[codec] = fn
+}
+
+/******************************************************************************
+ @brief
+ 	设置输出目标使用的压缩算法，每次写入（缓冲模式下为每批日志）会被压缩为一个独立的数据块，
+ 	gzip的多个数据块拼接后仍然是合法的gzip数据流
+ 		例：
+ 			logger.AddSink("collector", conn, logger.BUFFER_INTERVAL, 1024*1024, 5*time.Second)
+ 			logger.SetSinkCompression("collector", "gzip")
+ @author
+ 	agent
+ @param
+	name				输出目标名称
+	codec				压缩算法名称，空字符串表示不压缩
+ @return
+ 	error				输出目标或压缩算法不存在时返回错误信息
+ @history
+ 	2026-10-16_14:12 	agent		创建
+*******************************************************************************/
+func SetSinkCompression(name, codec string) error {
+
+	sink := findSink(name)
+	if sink == nil {
+		return fmt.Errorf("logger: unknown sink %q", name)
+	}
+
+	var fn COMPRESSOR
+	if codec != "" {
+		compressorLock.RLock()
+		fn = compressors[codec]
+		compressorLock.RUnlock()
+
+		if fn == nil {
+			return fmt.Errorf("logger: unknown compressor %q", codec)
+		}
+	}
+
+	sink.Lock()
+	defer sink.Unlock()
+	sink.compressor = fn
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	按照输出目标的压缩算法压缩日志，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	b					日志内容
+ @return
+ 	[]byte				返回压缩后的内容，未设置压缩算法时原样返回
+ @history
+ 	2026-10-16_14:12 	agent		创建
+*******************************************************************************/
+func (s *LOG_SINK) compress(b []byte) []byte {
+
+	if s.compressor == nil {
+		return b
+	}
+
+	var buf bytes.Buffer
+	w := s.compressor(&buf)
+	w.Write(b)
+	w.Close()
+
+	return buf.Bytes()
+}
@@ -0,0 +1,263 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+)
+
+type CONSOLE_STYLE int //终端控制台显示风格
+
+type CONSOLE_MODE int //终端控制台开关方式
+
+const (
+	CONSOLE_ALWAYS        CONSOLE_MODE = iota //始终显示，等同于SetConsole(true)
+	CONSOLE_NEVER                             //始终不显示，等同于SetConsole(false)
+	CONSOLE_AUTO_TTY_ONLY                     //只在输出到终端时显示，被重定向到文件或管道时不显示
+)
+
+const (
+	CONSOLE_STYLE_LINE  CONSOLE_STYLE = iota //整行着色，默认风格
+	CONSOLE_STYLE_LEVEL                      //只给级别着色，级别补齐宽度，内容使用终端默认颜色
+)
+
+var (
+	logConsoleStyle CONSOLE_STYLE = CONSOLE_STYLE_LINE //终端控制台显示风格
+	progressLock    sync.Mutex                         //进度条线程锁
+	progressLine    []byte                             //当前显示的进度条内容
+)
+
+/******************************************************************************
+ @brief
+ 	设置终端控制台显示风格，长日志整行着色不易阅读时可以只给级别着色
+ 		例：
+ 			logger.SetConsoleStyle(logger.CONSOLE_STYLE_LEVEL)
+ @author
+ 	agent
+ @param
+	style				显示风格
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:15 	agent		创建
+*******************************************************************************/
+func SetConsoleStyle(style CONSOLE_STYLE) {
+	logConsoleStyle = style
+}
+
+/******************************************************************************
+ @brief
+ 	设置终端控制台的开关方式。在systemd、docker等环境下运行时，标准输出已经被收集，
+ 	再输出到控制台会导致日志重复，此时可以使用CONSOLE_AUTO_TTY_ONLY，
+ 	控制台输出（标准库log的输出，默认为stderr）不是终端时自动关闭控制台显示
+ 		例：
+ 			logger.SetConsoleMode(logger.CONSOLE_AUTO_TTY_ONLY)
+ @author
+ 	agent
+ @param
+	mode				开关方式
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:16 	agent		创建
+*******************************************************************************/
+func SetConsoleMode(mode CONSOLE_MODE) {
+	switch mode {
+	case CONSOLE_NEVER:
+		SetConsole(false)
+	case CONSOLE_AUTO_TTY_ONLY:
+		SetConsole(isTerminal(log.Writer()))
+	default:
+		SetConsole(true)
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	判断输出是否为终端
+ @author
+ 	agent
+ @param
+	w					输出实例
+ @return
+ 	bool				返回true表示是终端，否则表示被重定向到文件、管道或者不是文件
+ @history
+ 	2026-10-16_14:16 	agent		创建
+*******************************************************************************/
+func isTerminal(w interface{}) bool {
+
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	finfo, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return finfo.Mode()&os.ModeCharDevice != 0
+}
+
+/******************************************************************************
+ @brief
+ 	获取日志级别在终端控制台上的显示样式和颜色，DEBUG和INFO使用默认颜色，
+ 	WARN为黄色，ERROR为高亮红色，FATAL为高亮紫红色
+ @author
+ 	agent
+ @param
+	ll					日志等级
+ @return
+ 	STYLE				返回显示样式
+ 	COLOR				返回前景色
+ @history
+ 	2026-10-16_14:15 	agent		创建
+*******************************************************************************/
+func levelColor(ll LEVEL) (STYLE, COLOR) {
+	switch ll {
+	case WARN:
+		return STYLE_DEFAULT, CLR_YELLOW
+	case ERROR:
+		return STYLE_HIGHLIGHT, CLR_RED
+	case FATAL:
+		return STYLE_HIGHLIGHT, CLR_PURPLE
+	}
+
+	return STYLE_DEFAULT, CLR_DEFAULT
+}
+
+/******************************************************************************
+ @brief
+ 	设置终端控制台最后一行显示的进度条内容，输出日志时会先清除进度条，
+ 	日志输出后再重新显示，交互式的命令行工具使用日志时不会破坏进度条
+ 		例：
+ 			for i := 0; i <= 100; i++ {
+ 				logger.SetProgress(fmt.Sprintf("downloading %d%%", i))
+ 				logger.Debugf("chunk %d done", i)
+ 			}
+ 			logger.ClearProgress()
+ @author
+ 	agent
+ @param
+	line				进度条内容，不能包含换行
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:16 	agent		创建
+*******************************************************************************/
+func SetProgress(line string) {
+	progressLock.Lock()
+	defer progressLock.Unlock()
+
+	progressLine = []byte(line)
+	fmt.Fprintf(log.Writer(), "\r%c[2K%s", 0x1B, line)
+}
+
+/******************************************************************************
+ @brief
+ 	清除终端控制台上显示的进度条
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:16 	agent		创建
+*******************************************************************************/
+func ClearProgress() {
+	progressLock.Lock()
+	defer progressLock.Unlock()
+
+	if len(progressLine) > 0 {
+		fmt.Fprintf(log.Writer(), "\r%c[2K", 0x1B)
+		progressLine = nil
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	获取进度条输出接口，第三方进度条库输出到此接口后，日志输出时同样会先清除进度条再重新显示。
+ 	最后一个换行之后的内容会被当作当前进度条，'\r'会清除之前的进度条内容
+ 		例：
+ 			bar := progressbar.NewOptions(100, progressbar.OptionSetWriter(logger.ProgressWriter()))
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	*PROGRESS_WRITER	返回进度条输出接口
+ @history
+ 	2026-10-16_14:16 	agent		创建
+*******************************************************************************/
+func ProgressWriter() *PROGRESS_WRITER {
+	return &PROGRESS_WRITER{}
+}
+
+/******************************************************************************
+ @brief
+ 	进度条输出接口
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:16 	agent		创建
+*******************************************************************************/
+type PROGRESS_WRITER struct{}
+
+/******************************************************************************
+ @brief
+ 	输出进度条内容，并记录当前行用于日志输出后重新显示
+ @author
+ 	agent
+ @param
+	b					进度条内容
+ @return
+ 	int					返回写入的字节数
+ 	error				写入失败时返回错误信息
+ @history
+ 	2026-10-16_14:16 	agent		创建
+*******************************************************************************/
+func (w *PROGRESS_WRITER) Write(b []byte) (int, error) {
+	progressLock.Lock()
+	defer progressLock.Unlock()
+
+	line := append(progressLine, b...)
+	if i := bytes.LastIndexByte(line, '\n'); i >= 0 {
+		line = line[i+1:]
+	}
+	if i := bytes.LastIndexByte(line, '\r'); i >= 0 {
+		line = line[i+1:]
+	}
+	progressLine = append([]byte(nil), line...)
+
+	return log.Writer().Write(b)
+}
+
+/******************************************************************************
+ @brief
+ 	输出一行日志到终端控制台，显示进度条时先清除进度条，输出后再重新显示
+ @author
+ 	agent
+ @param
+	line				要输出的内容
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:16 	agent		创建
+*******************************************************************************/
+func consolePrintln(line string) {
+	progressLock.Lock()
+	defer progressLock.Unlock()
+
+	if len(progressLine) == 0 {
+		log.Println(line)
+		return
+	}
+
+	w := log.Writer()
+	fmt.Fprintf(w, "\r%c[2K", 0x1B)
+	log.Println(line)
+	w.Write(progressLine)
+}
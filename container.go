@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const (
+	k8sNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace" //Kubernetes服务账号命名空间文件
+)
+
+var (
+	containerIDPattern = regexp.MustCompile(`[0-9a-f]{64}`) //容器ID格式
+)
+
+/******************************************************************************
+ @brief
+ 	读取容器和Kubernetes的运行信息，并设置为公共字段：
+ 		k8s.pod				Pod名称，读取POD_NAME环境变量，在Kubernetes中没有设置时使用HOSTNAME
+ 		k8s.namespace		命名空间，读取POD_NAMESPACE环境变量或服务账号的命名空间文件
+ 		k8s.node			节点名称，读取NODE_NAME环境变量
+ 		container.id		容器ID，从/proc/self/cgroup或/proc/self/mountinfo中读取，取前12位
+
+ 	环境变量需要在Pod的配置中通过downward API注入，例如：
+ 		env:
+ 		- name: POD_NAME
+ 		  valueFrom: {fieldRef: {fieldPath: metadata.name}}
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:17 	agent		创建
+*******************************************************************************/
+func EnrichContainer() {
+
+	inK8s := os.Getenv("KUBERNETES_SERVICE_HOST") != ""
+
+	//Pod名称
+	pod := os.Getenv("POD_NAME")
+	if pod == "" && inK8s {
+		pod = os.Getenv("HOSTNAME")
+	}
+	SetField("k8s.pod", pod)
+
+	//命名空间
+	namespace := os.Getenv("POD_NAMESPACE")
+	if namespace == "" {
+		if data, err := ioutil.ReadFile(k8sNamespaceFile); err == nil {
+			namespace = strings.TrimSpace(string(data))
+		}
+	}
+	SetField("k8s.namespace", namespace)
+
+	//节点名称
+	SetField("k8s.node", os.Getenv("NODE_NAME"))
+
+	//容器ID
+	if id := containerID(); len(id) > 0 {
+		SetField("container.id", id[:12])
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	获取当前进程所在的容器ID，cgroup v1从/proc/self/cgroup中读取，
+ 	cgroup v2从/proc/self/mountinfo中读取
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	string				返回64位容器ID，不在容器中时返回空字符串
+ @history
+ 	2026-10-16_14:17 	agent		创建
+*******************************************************************************/
+func containerID() string {
+
+	for _, fn := range []string{"/proc/self/cgroup", "/proc/self/mountinfo"} {
+		data, err := ioutil.ReadFile(fn)
+		if err != nil {
+			continue
+		}
+
+		for _, line := range strings.Split(string(data), "\n") {
+			if !strings.Contains(line, "docker") && !strings.Contains(line, "containerd") &&
+				!strings.Contains(line, "kubepods") && !strings.Contains(line, "containers") &&
+				!strings.Contains(line, "crio") {
+				continue
+			}
+
+			if id := containerIDPattern.FindString(line); len(id) > 0 {
+				return id
+			}
+		}
+	}
+
+	return ""
+}
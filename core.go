@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"os"
+	"runtime/debug"
+)
+
+var (
+	logCoreDump bool //异常和FATAL日志是否生成core文件
+)
+
+/******************************************************************************
+ @brief
+ 	设置CatchException捕获到异常、或者输出FATAL日志退出进程时，是否在写入异常报告
+ 	并写入所有输出目标的缓冲区之后发送SIGABRT终止进程，由操作系统生成core文件，
+ 	可以使用调试器分析现场。开启后同时设置GOTRACEBACK=crash，未捕获的panic也会生成core文件。
+ 	需要系统允许生成core文件，例如ulimit -c unlimited，不支持的平台直接退出进程
+ 		例：
+ 			logger.SetCoreDump(true)
+ @author
+ 	agent
+ @param
+	isCoreDump			是否生成core文件
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:52 	agent		创建
+*******************************************************************************/
+func SetCoreDump(isCoreDump bool) {
+	logCoreDump = isCoreDump
+
+	if isCoreDump {
+		debug.SetTraceback("crash")
+		return
+	}
+
+	//恢复为环境变量的设置
+	level := os.Getenv("GOTRACEBACK")
+	if len(level) == 0 {
+		level = "single"
+	}
+	debug.SetTraceback(level)
+}
+
+/******************************************************************************
+ @brief
+ 	开启了core文件时写入所有输出目标的缓冲区并终止进程，没有开启时直接返回
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:52 	agent		创建
+*******************************************************************************/
+func coreDump() {
+	if !logCoreDump {
+		return
+	}
+
+	Flush()
+	coreAbort()
+}
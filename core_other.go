@@ -0,0 +1,24 @@
+//go:build !linux && !darwin && !freebsd
+// +build !linux,!darwin,!freebsd
+
+package logger
+
+import (
+	"os"
+)
+
+/******************************************************************************
+ @brief
+ 	终止进程，当前平台不支持通过SIGABRT生成core文件，直接退出
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:52 	agent		创建
+*******************************************************************************/
+func coreAbort() {
+	os.Exit(2)
+}
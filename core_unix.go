@@ -0,0 +1,30 @@
+//go:build linux || darwin || freebsd
+// +build linux darwin freebsd
+
+package logger
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	发送SIGABRT终止进程，GOTRACEBACK=crash时Go运行时会输出所有协程的调用栈并生成core文件
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:52 	agent		创建
+*******************************************************************************/
+func coreAbort() {
+	syscall.Kill(os.Getpid(), syscall.SIGABRT)
+
+	//信号被程序自己处理时仍然要退出
+	time.Sleep(5 * time.Second)
+	os.Exit(2)
+}
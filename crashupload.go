@@ -0,0 +1,217 @@
+package logger
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	crashUploadStateFile = "./exceptions/uploaded.state" //已经上传的异常文件列表
+	crashUploadRetries   = 3                             //每个文件每次最多尝试的次数
+)
+
+var (
+	crashUploadLock     sync.Mutex  //异常上传线程锁
+	crashUploadEndpoint string      //接收异常报告的地址
+	crashUploadAEAD     cipher.AEAD //加密异常报告使用的AES-GCM
+	crashUploadConsent  bool        //用户是否同意上传，默认不同意
+	crashUploadClient   = &http.Client{Timeout: 10 * time.Second}
+	crashUploadRunning  sync.Mutex //同一时间只有一个上传任务
+)
+
+/******************************************************************************
+ @brief
+ 	设置异常报告上传地址和加密密钥，捕获到异常后在后台将异常文件用AES-GCM加密后POST到endpoint，
+ 	失败时重试，仍然失败的文件在下次捕获到异常或再次调用SetCrashUpload时重新上传。
+ 	部署在客户机器上的服务需要由客户调用SetCrashConsent(true)同意后才会上传。
+ 	请求内容为12字节随机数加密文，附加数据为X-Crash-File头中的文件名
+ 		例：
+ 			logger.SetCrashUpload("https://crash.example.com/api/v1/report", key)
+ 			logger.SetCrashConsent(cfg.SendCrashReports)
+ @author
+ 	agent
+ @param
+	endpoint			接收异常报告的地址，为空表示关闭
+	key					AES密钥，长度为16、24或32字节
+ @return
+ 	error				密钥长度不正确时返回错误信息
+ @history
+ 	2026-10-16_15:02 	agent		创建
+*******************************************************************************/
+func SetCrashUpload(endpoint string, key []byte) error {
+
+	var aead cipher.AEAD
+	if len(endpoint) > 0 {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return fmt.Errorf("logger: crash upload key: %v", err)
+		}
+
+		aead, err = cipher.NewGCM(block)
+		if err != nil {
+			return fmt.Errorf("logger: crash upload key: %v", err)
+		}
+	}
+
+	crashUploadLock.Lock()
+	crashUploadEndpoint, crashUploadAEAD = endpoint, aead
+	crashUploadLock.Unlock()
+
+	//上传之前没有上传成功的异常报告
+	go crashUpload()
+
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	设置是否同意上传异常报告，默认不同意，不同意时异常报告只保存在本地
+ @author
+ 	agent
+ @param
+	consent				是否同意
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:02 	agent		创建
+*******************************************************************************/
+func SetCrashConsent(consent bool) {
+	crashUploadLock.Lock()
+	crashUploadConsent = consent
+	crashUploadLock.Unlock()
+
+	if consent {
+		go crashUpload()
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	上传所有还没有上传的异常报告
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:02 	agent		创建
+ 	2026-10-16_15:42 	agent		通过日志存储读取异常文件
+ 	2026-10-16_16:25 	agent		文件操作通过日志存储接口完成
+*******************************************************************************/
+func crashUpload() {
+	defer catchError()
+
+	crashUploadLock.Lock()
+	endpoint, aead, consent := crashUploadEndpoint, crashUploadAEAD, crashUploadConsent
+	crashUploadLock.Unlock()
+
+	if len(endpoint) == 0 || aead == nil || !consent {
+		return
+	}
+
+	crashUploadRunning.Lock()
+	defer crashUploadRunning.Unlock()
+
+	files, err := logStorage.Glob(filepath.Join("exceptions", "????-??-??", "exceptions.*.log"))
+	if err != nil {
+		return
+	}
+
+	uploaded := map[string]bool{}
+	if data, err := storageReadFile(crashUploadStateFile); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			uploaded[line] = true
+		}
+	}
+
+	for _, fn := range files {
+		name := filepath.ToSlash(fn)
+		if uploaded[name] {
+			continue
+		}
+
+		data, err := storageReadFile(fn)
+		if err != nil {
+			continue
+		}
+
+		if err := crashPost(endpoint, aead, name, data); err != nil {
+			diag("crash upload %s failed: %v", name, err)
+			continue
+		}
+
+		state, err := logStorage.OpenFile(crashUploadStateFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintln(state, name)
+		state.Close()
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	加密并上传一个异常报告，失败时按1秒、2秒的间隔重试
+ @author
+ 	agent
+ @param
+	endpoint			接收异常报告的地址
+	aead				加密使用的AES-GCM
+	name				异常文件名
+	data				异常文件内容
+ @return
+ 	error				重试后仍然失败时返回最后一次的错误信息
+ @history
+ 	2026-10-16_15:02 	agent		创建
+*******************************************************************************/
+func crashPost(endpoint string, aead cipher.AEAD, name string, data []byte) error {
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	body := aead.Seal(nonce, nonce, data, []byte(name))
+
+	var err error
+	for i := 0; i < crashUploadRetries; i++ {
+		if i > 0 {
+			time.Sleep(time.Duration(1<<uint(i-1)) * time.Second)
+		}
+
+		var req *http.Request
+		req, err = http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/octet-stream")
+		req.Header.Set("X-Crash-File", name)
+		req.Header.Set("X-Crash-Host", hostname())
+
+		var resp *http.Response
+		resp, err = crashUploadClient.Do(req)
+		if err != nil {
+			continue
+		}
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		err = fmt.Errorf("logger: crash upload status %s", resp.Status)
+	}
+
+	return err
+}
@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	Datadog统一服务标签，来自DD_SERVICE、DD_ENV、DD_VERSION环境变量
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:15 	agent		创建
+*******************************************************************************/
+type datadogTags struct {
+	service string //服务名称
+	env     string //环境
+	version string //版本
+}
+
+var (
+	datadogEnv atomic.Value //*datadogTags，SetJSONStyle时读取
+)
+
+/******************************************************************************
+ @brief
+ 	读取Datadog统一服务标签的环境变量，没有设置DD_VERSION时使用编译信息中的版本
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:15 	agent		创建
+*******************************************************************************/
+func datadogLoadEnv() {
+	tags := &datadogTags{
+		service: os.Getenv("DD_SERVICE"),
+		env:     os.Getenv("DD_ENV"),
+		version: os.Getenv("DD_VERSION"),
+	}
+
+	if len(tags.version) == 0 {
+		if v := Build().Version; v != "(devel)" {
+			tags.version = v
+		}
+	}
+
+	datadogEnv.Store(tags)
+}
+
+/******************************************************************************
+ @brief
+ 	按照Datadog的标准属性将日志编码为一行JSON：status、timestamp（Unix毫秒）、message，
+ 	日志内容末尾的key=value字段和公共字段作为属性输出，
+ 	其中trace_id、span_id输出为dd.trace_id、dd.span_id，日志可以与APM调用链关联
+ 		输出：{"timestamp":1792163684799,"status":"info","message":"login",
+ 			"caller":"main.go:12","service":"gameserver","dd.service":"gameserver","dd.env":"prod",
+ 			"dd.trace_id":"6024836421437126146","dd.span_id":"2238474162316046582","player":"10001"}
+ @author
+ 	agent
+ @param
+	buf					输出缓冲
+	t					日志时间
+	ll					日志等级
+	file				调用者文件，为空表示不输出调用者信息
+	line				调用者行号
+	fn					调用者函数名，为空表示不输出
+	msg					日志内容
+ @return
+ 	[]byte				返回追加JSON后的缓冲，以换行结尾
+ @history
+ 	2026-10-16_15:15 	agent		创建
+*******************************************************************************/
+func encodeDatadog(buf []byte, t time.Time, ll LEVEL, file string, line int, fn string, msg string) []byte {
+
+	msg, fields := parseFields(strings.TrimRight(msg, "\n"))
+
+	buf = append(buf, '{')
+	buf = appendJSON(buf, "timestamp", t.UnixNano()/int64(time.Millisecond))
+	buf = append(buf, ',')
+	buf = appendJSON(buf, "status", datadogStatus(ll))
+	buf = append(buf, ',')
+	buf = appendJSON(buf, "message", msg)
+	if len(file) > 0 {
+		buf = append(buf, ',')
+		buf = appendJSON(buf, "caller", fmt.Sprintf("%s:%d", shortFile(file), line))
+	}
+	if len(fn) > 0 {
+		buf = append(buf, ',')
+		buf = appendJSON(buf, "logger.method_name", fn)
+	}
+
+	if tags, _ := datadogEnv.Load().(*datadogTags); tags != nil {
+		for _, kv := range [][2]string{
+			{"service", tags.service},
+			{"dd.service", tags.service},
+			{"dd.env", tags.env},
+			{"dd.version", tags.version},
+		} {
+			if len(kv[1]) > 0 {
+				buf = append(buf, ',')
+				buf = appendJSON(buf, kv[0], kv[1])
+			}
+		}
+	}
+
+	for _, field := range append(fields, fieldsList()...) {
+		key := field[0]
+		switch key {
+		case "trace_id", "span_id":
+			key = "dd." + key
+		}
+		buf = append(buf, ',')
+		buf = appendJSON(buf, key, field[1])
+	}
+	buf = append(buf, '}', '\n')
+
+	return buf
+}
+
+/******************************************************************************
+ @brief
+ 	获取日志级别对应的Datadog状态
+ @author
+ 	agent
+ @param
+	ll					日志等级
+ @return
+ 	string				返回状态
+ @history
+ 	2026-10-16_15:15 	agent		创建
+*******************************************************************************/
+func datadogStatus(ll LEVEL) string {
+	switch ll {
+	case DEBUG, ALL:
+		return "debug"
+	case INFO:
+		return "info"
+	case WARN:
+		return "warn"
+	case ERROR:
+		return "error"
+	}
+
+	return "critical"
+}
@@ -0,0 +1,266 @@
+package logger
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	dedupSaveInterval = time.Second //签名有变化时，状态文件最多每秒保存一次
+	dedupMaxRecords   = 10000       //最多保存的签名数量，超过时丢弃最早写入的签名
+)
+
+/******************************************************************************
+ @brief
+ 	已经写入的日志签名
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:51 	agent		创建
+*******************************************************************************/
+type dedupRecord struct {
+	first int64 //写入的时间戳
+	count int   //之后被丢弃的重复次数
+}
+
+var (
+	dedupLock     sync.Mutex             //日志去重线程锁
+	dedupOn       int32                  //是否开启日志去重，写日志时不加锁判断
+	dedupInterval time.Duration          //相同日志只写一次的间隔
+	dedupFile     string                 //签名状态文件
+	dedupRecords  map[uint64]dedupRecord //间隔内已经写入的日志签名
+	dedupDirty    bool                   //签名是否有尚未保存的变化
+	dedupStop     chan struct{}          //停止定时保存
+	dedupSaveLock sync.Mutex             //保存状态文件线程锁
+)
+
+/******************************************************************************
+ @brief
+ 	设置WARN及以上级别日志的去重，同一个日志文件中级别和内容都相同的日志在间隔内只写一次，
+ 	之后的重复只计数，间隔过后再次出现时先写一条提示重复次数的WARN日志。
+ 	签名保存在状态文件中，崩溃循环反复重启时启动阶段的相同错误也只写一次。
+ 	状态文件由后台定时保存，最多每秒一次，Flush和FATAL日志退出进程前也会保存，
+ 	进程崩溃时可能少记最后一秒的签名和次数；最多保存10000个签名，超过时丢弃最早写入的签名
+ 		例：
+ 			logger.SetLogDedup("./log/dedup.state", 10*time.Minute)
+ @author
+ 	agent
+ @param
+	fn					签名状态文件路径
+	interval			去重间隔，小于等于0表示关闭
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:51 	agent		创建
+ 	2026-10-16_16:45 	agent		由后台定时保存状态文件
+*******************************************************************************/
+func SetLogDedup(fn string, interval time.Duration) {
+
+	//停止定时保存，并保存之前尚未保存的签名
+	dedupLock.Lock()
+	if dedupStop != nil {
+		close(dedupStop)
+		dedupStop = nil
+	}
+	dedupLock.Unlock()
+	dedupFlush()
+
+	dedupLock.Lock()
+	defer dedupLock.Unlock()
+
+	if interval <= 0 || len(fn) == 0 {
+		atomic.StoreInt32(&dedupOn, 0)
+		dedupRecords = nil
+		return
+	}
+
+	dedupInterval = interval
+	dedupFile = fn
+	dedupRecords = readDedupRecords(fn, time.Now().Add(-interval))
+	dedupDirty = false
+	dedupStop = make(chan struct{})
+	go dedupMonitor(dedupStop)
+	atomic.StoreInt32(&dedupOn, 1)
+}
+
+/******************************************************************************
+ @brief
+ 	检查日志是否在间隔内已经写过，是则丢弃并计数
+ @author
+ 	agent
+ @param
+	f					日志文件
+	ll					日志等级
+	arg					日志内容
+ @return
+ 	bool				返回true表示丢弃
+ 	string				间隔过后再次写入时返回上个间隔重复次数的提示，没有重复时为空
+ @history
+ 	2026-10-16_14:51 	agent		创建
+ 	2026-10-16_16:45 	agent		只标记变化，不再直接保存状态文件，限制签名数量
+*******************************************************************************/
+func dedupDrop(f *LOG_FILE, ll LEVEL, arg string) (bool, string) {
+	if atomic.LoadInt32(&dedupOn) == 0 || ll < WARN {
+		return false, ""
+	}
+
+	h := fnv.New64a()
+	if f != nil {
+		h.Write([]byte(f.log_filename))
+	}
+	fmt.Fprintf(h, "\x00%d\x00%s", ll, arg)
+	sig := h.Sum64()
+
+	dedupLock.Lock()
+	defer dedupLock.Unlock()
+
+	if dedupRecords == nil {
+		return false, ""
+	}
+
+	now := time.Now()
+	r, ok := dedupRecords[sig]
+	if ok && now.Sub(time.Unix(r.first, 0)) < dedupInterval {
+		r.count++
+		dedupRecords[sig] = r
+		dedupDirty = true
+		return true, ""
+	}
+
+	notice := ""
+	if ok && r.count > 0 {
+		notice = fmt.Sprintf("logger: %d duplicates of the next entry dropped since %s\n", r.count, time.Unix(r.first, 0).Format("2006/01/02 15:04:05"))
+	}
+
+	//签名过多时先清理过期的签名，仍然过多时丢弃最早写入的签名
+	if len(dedupRecords) >= dedupMaxRecords {
+		oldest, first := uint64(0), int64(0)
+		for s, r := range dedupRecords {
+			if now.Sub(time.Unix(r.first, 0)) >= dedupInterval {
+				delete(dedupRecords, s)
+			} else if first == 0 || r.first < first {
+				oldest, first = s, r.first
+			}
+		}
+		if len(dedupRecords) >= dedupMaxRecords {
+			delete(dedupRecords, oldest)
+		}
+	}
+
+	dedupRecords[sig] = dedupRecord{first: now.Unix()}
+	dedupDirty = true
+
+	return false, notice
+}
+
+/******************************************************************************
+ @brief
+ 	定时保存状态文件，关闭或重新设置日志去重时退出
+ @author
+ 	agent
+ @param
+	stop				停止定时保存
+ @return
+ 	-
+ @history
+ 	2026-10-16_16:45 	agent		创建
+*******************************************************************************/
+func dedupMonitor(stop chan struct{}) {
+	timer := time.NewTicker(dedupSaveInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			dedupFlush()
+		case <-stop:
+			return
+		}
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	签名有变化时保存到状态文件，写文件时不持有日志去重线程锁，不会阻塞写日志
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:51 	agent		创建
+ 	2026-10-16_16:25 	agent		文件操作通过日志存储接口完成
+ 	2026-10-16_16:45 	agent		从dedupSave改名，在锁外保存，没有变化时不保存
+*******************************************************************************/
+func dedupFlush() {
+	dedupSaveLock.Lock()
+	defer dedupSaveLock.Unlock()
+
+	dedupLock.Lock()
+	if !dedupDirty || dedupRecords == nil {
+		dedupLock.Unlock()
+		return
+	}
+
+	fn := dedupFile
+	lines := make([]string, 0, len(dedupRecords))
+	for sig, r := range dedupRecords {
+		lines = append(lines, fmt.Sprintf("%016x %d %d", sig, r.first, r.count))
+	}
+	dedupDirty = false
+	dedupLock.Unlock()
+
+	logStorage.MkdirAll(filepath.Dir(fn), os.ModePerm)
+	if err := storageWriteFile(fn, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		diag("dedup state %s: %v", fn, err)
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	读取状态文件中since之后写入的日志签名
+ @author
+ 	agent
+ @param
+	fn					签名状态文件路径
+	since				起始时间
+ @return
+ 	map[uint64]dedupRecord	返回日志签名
+ @history
+ 	2026-10-16_14:51 	agent		创建
+ 	2026-10-16_16:25 	agent		文件操作通过日志存储接口完成
+*******************************************************************************/
+func readDedupRecords(fn string, since time.Time) map[uint64]dedupRecord {
+	records := map[uint64]dedupRecord{}
+
+	data, err := storageReadFile(fn)
+	if err != nil {
+		return records
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+
+		sig, err1 := strconv.ParseUint(fields[0], 16, 64)
+		first, err2 := strconv.ParseInt(fields[1], 10, 64)
+		count, err3 := strconv.Atoi(fields[2])
+		if err1 != nil || err2 != nil || err3 != nil || first < since.Unix() {
+			continue
+		}
+
+		records[sig] = dedupRecord{first: first, count: count}
+	}
+
+	return records
+}
@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	日志自身的错误记录
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:47 	agent		创建
+*******************************************************************************/
+type DIAG_ENTRY struct {
+	Time time.Time //发生时间
+	Msg  string    //错误信息
+}
+
+const (
+	diagRingSize = 256 //内存中保留的最近错误数量
+)
+
+var (
+	diagLock sync.Mutex               //错误记录线程锁
+	diagRing [diagRingSize]DIAG_ENTRY //最近的错误记录，环形缓冲
+	diagNext int                      //下一条记录的位置
+	diagFull bool                     //环形缓冲是否已经写满
+	diagFile string                   //错误记录文件，为空表示不写文件
+	diagSum  int64                    //错误总次数
+)
+
+/******************************************************************************
+ @brief
+ 	设置日志自身错误的记录文件，日志文件创建失败、输出目标写入失败、增量上传失败等
+ 	错误除了保留在内存中，还会追加到这个文件。错误记录不经过日志本身，
+ 	所以日志模块出现问题时仍然可以查看
+ 		例：
+ 			logger.SetDiagFile("./log/logger.diag")
+ @author
+ 	agent
+ @param
+	fn					记录文件路径，为空表示只保留在内存中
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:47 	agent		创建
+ 	2026-10-16_15:42 	agent		通过日志存储创建目录
+*******************************************************************************/
+func SetDiagFile(fn string) {
+	diagLock.Lock()
+	defer diagLock.Unlock()
+
+	diagFile = fn
+	if len(fn) > 0 {
+		logStorage.MkdirAll(filepath.Dir(fn), os.ModePerm)
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	获取内存中保留的最近的日志自身错误，也可以通过StartPPROF启动的HTTP服务查看：
+ 		curl "http://127.0.0.1:18000/debug/logger/errors"
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	[]DIAG_ENTRY		返回错误记录，按时间从旧到新排序
+ @history
+ 	2026-10-16_14:47 	agent		创建
+*******************************************************************************/
+func Diagnostics() []DIAG_ENTRY {
+	diagLock.Lock()
+	defer diagLock.Unlock()
+
+	if !diagFull {
+		return append([]DIAG_ENTRY(nil), diagRing[:diagNext]...)
+	}
+
+	entries := make([]DIAG_ENTRY, 0, diagRingSize)
+	entries = append(entries, diagRing[diagNext:]...)
+	return append(entries, diagRing[:diagNext]...)
+}
+
+/******************************************************************************
+ @brief
+ 	记录一条日志自身的错误
+ @author
+ 	agent
+ @param
+	format				格式化字符串
+	args				参数
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:47 	agent		创建
+ 	2026-10-16_15:14 	agent		统计错误总次数
+ 	2026-10-16_15:42 	agent		通过日志存储写入
+*******************************************************************************/
+func diag(format string, args ...interface{}) {
+	e := DIAG_ENTRY{Time: time.Now(), Msg: fmt.Sprintf(format, args...)}
+
+	diagLock.Lock()
+	defer diagLock.Unlock()
+
+	diagSum++
+	diagRing[diagNext] = e
+	diagNext = (diagNext + 1) % diagRingSize
+	if diagNext == 0 {
+		diagFull = true
+	}
+
+	if len(diagFile) == 0 {
+		return
+	}
+
+	file, err := logStorage.OpenFile(diagFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	fmt.Fprintf(file, "%s %s\n", e.Time.Format("2006/01/02 15:04:05.000"), e.Msg)
+}
+
+/******************************************************************************
+ @brief
+ 	获取日志自身错误的总次数，包括已经不在环形缓冲中的记录
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	int64				返回错误总次数
+ @history
+ 	2026-10-16_15:14 	agent		创建
+*******************************************************************************/
+func diagTotal() int64 {
+	diagLock.Lock()
+	defer diagLock.Unlock()
+
+	return diagSum
+}
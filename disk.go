@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+)
+
+var (
+	logDiskReserve  uint64 //创建日志文件时要求的最小磁盘剩余空间，0表示不检查
+	logPreallocate  int64  //创建日志文件时预分配的大小，0表示不预分配
+	logDiskDegraded int32  //磁盘空间不足，只输出到终端控制台，1表示降级，通过atomic读写
+)
+
+/******************************************************************************
+ @brief
+ 	设置创建日志文件前的磁盘空间检查，剩余空间低于reserve时输出WARN并进入降级模式，
+ 	降级模式下不再写入日志文件，只输出到终端控制台和扩展输出目标，空间恢复后自动恢复
+ 		例：
+ 			//剩余空间低于1G时降级，每个日志文件预分配64M
+ 			logger.SetDiskReserve(1<<30, 64<<20)
+ @author
+ 	agent
+ @param
+	reserve				要求的最小剩余空间，0表示不检查
+	preallocate			预分配大小，0表示不预分配，仅Linux有效
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:22 	agent		创建
+*******************************************************************************/
+func SetDiskReserve(reserve uint64, preallocate int64) {
+	logDiskReserve = reserve
+	logPreallocate = preallocate
+}
+
+/******************************************************************************
+ @brief
+ 	检查目录所在磁盘的剩余空间是否足够，并切换降级模式
+ @author
+ 	agent
+ @param
+	dir					日志目录
+ @return
+ 	bool				返回true表示空间足够或无法获取剩余空间
+ @history
+ 	2026-10-16_14:22 	agent		创建
+ 	2026-10-16_15:36 	agent		降级标记改为原子读写
+*******************************************************************************/
+func diskCheck(dir string) bool {
+	free, ok := uint64(0), false
+	if logDiskReserve > 0 {
+		free, ok = diskFree(dir)
+	}
+
+	if !ok || free >= logDiskReserve {
+		if diskDegraded() {
+			console(WARN, "", 0, "", fmt.Sprintf("%s logger: disk space recovered, free %d bytes", WARN, free))
+			atomic.StoreInt32(&logDiskDegraded, 0)
+		}
+		return true
+	}
+
+	if atomic.CompareAndSwapInt32(&logDiskDegraded, 0, 1) {
+		console(WARN, "", 0, "", fmt.Sprintf("%s logger: disk space low, free %d bytes < %d bytes, log file disabled", WARN, free, logDiskReserve))
+	}
+
+	return false
+}
+
+/******************************************************************************
+ @brief
+ 	是否处于磁盘空间不足的降级模式，写日志时调用，不需要加锁
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	bool				降级时返回true
+ @history
+ 	2026-10-16_15:36 	agent		创建
+*******************************************************************************/
+func diskDegraded() bool {
+	return atomic.LoadInt32(&logDiskDegraded) != 0
+}
+
+/******************************************************************************
+ @brief
+ 	为新创建的日志文件预分配磁盘空间
+ @author
+ 	agent
+ @param
+	f					日志文件
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:22 	agent		创建
+ 	2026-10-16_14:30 	agent		只对操作系统文件预分配
+*******************************************************************************/
+func diskPreallocate(sf STORAGE_FILE) {
+	f, ok := sf.(*os.File)
+	if !ok || logPreallocate <= 0 {
+		return
+	}
+
+	//只对新文件预分配，避免追加写入已有文件时覆盖
+	if fi, err := f.Stat(); err != nil || fi.Size() > 0 {
+		return
+	}
+
+	preallocate(f, logPreallocate)
+}
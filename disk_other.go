@@ -0,0 +1,21 @@
+//go:build !linux && !darwin && !freebsd
+// +build !linux,!darwin,!freebsd
+
+package logger
+
+/******************************************************************************
+ @brief
+ 	获取目录所在磁盘的剩余可用空间，当前平台不支持
+ @author
+ 	agent
+ @param
+	dir					目录
+ @return
+ 	uint64				返回剩余可用空间
+ 	bool				返回false表示无法获取
+ @history
+ 	2026-10-16_14:22 	agent		创建
+*******************************************************************************/
+func diskFree(dir string) (uint64, bool) {
+	return 0, false
+}
@@ -0,0 +1,30 @@
+//go:build linux || darwin || freebsd
+// +build linux darwin freebsd
+
+package logger
+
+import (
+	"syscall"
+)
+
+/******************************************************************************
+ @brief
+ 	获取目录所在磁盘的剩余可用空间
+ @author
+ 	agent
+ @param
+	dir					目录
+ @return
+ 	uint64				返回剩余可用空间
+ 	bool				返回false表示无法获取
+ @history
+ 	2026-10-16_14:22 	agent		创建
+*******************************************************************************/
+func diskFree(dir string) (uint64, bool) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return 0, false
+	}
+
+	return uint64(st.Bavail) * uint64(st.Bsize), true
+}
@@ -0,0 +1,199 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+)
+
+const (
+	ERROR_TIMEOUT    = "timeout"    //超时
+	ERROR_CANCELED   = "canceled"   //被取消
+	ERROR_IO         = "io"         //文件、网络等输入输出错误
+	ERROR_VALIDATION = "validation" //参数或数据格式不合法
+	ERROR_OTHER      = "other"      //无法分类
+)
+
+/******************************************************************************
+ @brief
+ 	带分类的错误，由ErrorClass生成
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:04 	agent		创建
+*******************************************************************************/
+type classError struct {
+	err   error  //原始错误
+	class string //错误分类
+}
+
+var (
+	classifierLock sync.RWMutex             //自定义分类线程锁
+	classifiers    []func(err error) string //自定义分类函数，按注册顺序检查
+)
+
+/******************************************************************************
+ @brief
+ 	给错误指定分类，返回的错误保留原始错误，errors.Is、errors.As仍然可以使用
+ 		例：
+ 			if len(name) == 0 {
+ 				return logger.ErrorClass(errors.New("empty name"), logger.ERROR_VALIDATION)
+ 			}
+
+ 			logger.Errorf("create role failed %s", logger.ErrorField("err", err))
+
+ 		输出：ERROR create role failed err="empty name" err_class=validation
+ @author
+ 	agent
+ @param
+	err					原始错误
+	class				错误分类，可以使用内置分类或自定义分类
+ @return
+ 	error				返回带分类的错误，err为nil时返回nil
+ @history
+ 	2026-10-16_15:04 	agent		创建
+*******************************************************************************/
+func ErrorClass(err error, class string) error {
+	if err == nil {
+		return nil
+	}
+
+	return &classError{err: err, class: class}
+}
+
+/******************************************************************************
+ @brief
+ 	注册自定义分类函数，返回空字符串表示不能分类，自定义分类优先于内置分类
+ @author
+ 	agent
+ @param
+	fn					分类函数
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:04 	agent		创建
+*******************************************************************************/
+func RegisterErrorClassifier(fn func(err error) string) {
+	classifierLock.Lock()
+	defer classifierLock.Unlock()
+
+	classifiers = append(classifiers, fn)
+}
+
+/******************************************************************************
+ @brief
+ 	获取错误分类，依次检查ErrorClass指定的分类、自定义分类、内置的
+ 	canceled、timeout、io、validation分类，都不匹配时返回other
+ @author
+ 	agent
+ @param
+	err					错误
+ @return
+ 	string				返回错误分类，err为nil时返回空字符串
+ @history
+ 	2026-10-16_15:04 	agent		创建
+*******************************************************************************/
+func Classify(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var ce *classError
+	if errors.As(err, &ce) {
+		return ce.class
+	}
+
+	classifierLock.RLock()
+	for _, fn := range classifiers {
+		if class := fn(err); len(class) > 0 {
+			classifierLock.RUnlock()
+			return class
+		}
+	}
+	classifierLock.RUnlock()
+
+	var ne net.Error
+	var pathErr *fs.PathError
+	var opErr *net.OpError
+	var errno syscall.Errno
+	var numErr *strconv.NumError
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ERROR_CANCELED
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded):
+		return ERROR_TIMEOUT
+	case errors.As(err, &ne) && ne.Timeout():
+		return ERROR_TIMEOUT
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.ErrClosedPipe), errors.Is(err, net.ErrClosed):
+		return ERROR_IO
+	case errors.As(err, &pathErr), errors.As(err, &opErr), errors.As(err, &errno):
+		return ERROR_IO
+	case errors.As(err, &numErr), errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return ERROR_VALIDATION
+	}
+
+	return ERROR_OTHER
+}
+
+/******************************************************************************
+ @brief
+ 	生成错误字段，同时输出错误信息和错误分类，日志分析系统可以按分类统计错误
+ @author
+ 	agent
+ @param
+	key					字段名称
+	err					错误
+ @return
+ 	string				返回key=错误信息 key_class=分类形式的字段文本，err为nil时返回key=<nil>
+ @history
+ 	2026-10-16_15:04 	agent		创建
+*******************************************************************************/
+func ErrorField(key string, err error) string {
+	if err == nil {
+		return configField(key, "<nil>")
+	}
+
+	return configField(key, err.Error()) + " " + configField(key+"_class", Classify(err))
+}
+
+/******************************************************************************
+ @brief
+ 	返回原始错误信息
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	string				返回原始错误信息
+ @history
+ 	2026-10-16_15:04 	agent		创建
+*******************************************************************************/
+func (e *classError) Error() string {
+	return e.err.Error()
+}
+
+/******************************************************************************
+ @brief
+ 	返回原始错误，供errors.Is、errors.As使用
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	error				返回原始错误
+ @history
+ 	2026-10-16_15:04 	agent		创建
+*******************************************************************************/
+func (e *classError) Unwrap() error {
+	return e.err
+}
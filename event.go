@@ -0,0 +1,210 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	事件日志类结构，事件日志绑定一个分类和一个结构体定义，每条事件按照结构体字段
+ 	顺序编码为一行JSON写入分类日志文件，方便数据分析系统解析
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:26 	agent		创建
+*******************************************************************************/
+type EVENT_LOG struct {
+	category *CATEGORY    //事件写入的分类
+	schema   reflect.Type //事件结构体类型
+	fields   []eventField //需要输出的字段
+}
+
+/******************************************************************************
+ @brief
+ 	事件字段定义
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:26 	agent		创建
+*******************************************************************************/
+type eventField struct {
+	index    int    //结构体字段序号
+	name     string //JSON字段名称
+	required bool   //是否必须为非零值
+}
+
+/******************************************************************************
+ @brief
+ 	创建事件日志，字段名称默认使用结构体字段名，可以通过event标签修改：
+ 	event:"name"指定名称，event:"name,required"表示不能为零值，event:"-"表示不输出
+ 		例：
+ 			type ChatEvent struct {
+ 				Channel string `event:"channel,required"`
+ 				From    int64  `event:"from,required"`
+ 				Text    string `event:"text"`
+ 			}
+
+ 			chat, err := logger.EventLog("chat", ChatEvent{})
+ 			chat.Write(ChatEvent{Channel: "world", From: 10001, Text: "hi"})
+
+ 		输出：{"v":1,"time":"2026-10-16T19:10:00.000000+08:00","event":"chat","channel":"world","from":10001,"text":"hi"}
+ @author
+ 	agent
+ @param
+	category			分类名称，事件写入该分类的日志文件
+	schema				事件结构体或结构体指针
+ @return
+ 	*EVENT_LOG			返回事件日志
+ 	error				结构体定义不合法时返回错误信息
+ @history
+ 	2026-10-16_14:26 	agent		创建
+*******************************************************************************/
+func EventLog(category string, schema interface{}) (*EVENT_LOG, error) {
+
+	t := reflect.TypeOf(schema)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("logger: event schema must be a struct, got %v", t)
+	}
+
+	fields := []eventField{}
+	names := map[string]bool{"v": true, "time": true, "event": true}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue //未导出的字段
+		}
+
+		tag := field.Tag.Get("event")
+		if tag == "-" {
+			continue
+		}
+
+		ef := eventField{index: i, name: field.Name}
+		opts := strings.Split(tag, ",")
+		if len(opts[0]) > 0 {
+			ef.name = opts[0]
+		}
+		for _, opt := range opts[1:] {
+			switch opt {
+			case "required":
+				ef.required = true
+			default:
+				return nil, fmt.Errorf("logger: event field %s has unknown option %q", field.Name, opt)
+			}
+		}
+
+		if !eventKind(field.Type, map[reflect.Type]bool{}) {
+			return nil, fmt.Errorf("logger: event field %s has unsupported type %v", field.Name, field.Type)
+		}
+
+		if names[ef.name] {
+			return nil, fmt.Errorf("logger: event field name %q is duplicated or reserved", ef.name)
+		}
+		names[ef.name] = true
+
+		fields = append(fields, ef)
+	}
+
+	return &EVENT_LOG{category: Category(category), schema: t, fields: fields}, nil
+}
+
+/******************************************************************************
+ @brief
+ 	写入一条事件，事件类型必须与创建时的结构体一致，必填字段不能为零值
+ @author
+ 	agent
+ @param
+	v					事件结构体或结构体指针
+ @return
+ 	error				校验失败或日志文件不可用时返回错误信息
+ @history
+ 	2026-10-16_14:26 	agent		创建
+ 	2026-10-16_14:37 	agent		输出格式版本
+ 	2026-10-16_14:43 	agent		写入不再加锁
+*******************************************************************************/
+func (e *EVENT_LOG) Write(v interface{}) error {
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	if !rv.IsValid() || rv.Type() != e.schema {
+		return fmt.Errorf("logger: event %s expects %v, got %T", e.category.name, e.schema, v)
+	}
+
+	buf := []byte{'{'}
+	buf = appendJSON(buf, "v", FORMAT_VERSION)
+	buf = append(buf, ',')
+	buf = appendJSON(buf, "time", time.Now().Format("2006-01-02T15:04:05.000000Z07:00"))
+	buf = append(buf, ',')
+	buf = appendJSON(buf, "event", e.category.name)
+	for _, ef := range e.fields {
+		fv := rv.Field(ef.index)
+		if ef.required && fv.IsZero() {
+			return fmt.Errorf("logger: event %s field %s is required", e.category.name, ef.name)
+		}
+
+		buf = append(buf, ',')
+		buf = appendJSON(buf, ef.name, fv.Interface())
+	}
+	buf = append(buf, '}', '\n')
+
+	f := e.category.file
+	if f.current() == nil {
+		return fmt.Errorf("logger: event %s has no log file, call Initialize first", e.category.name)
+	}
+
+	f.write(buf)
+
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	判断字段类型是否可以编码为JSON
+ @author
+ 	agent
+ @param
+	t					字段类型
+	seen				已经检查过的结构体类型，避免递归定义死循环
+ @return
+ 	bool				返回true表示支持
+ @history
+ 	2026-10-16_14:26 	agent		创建
+*******************************************************************************/
+func eventKind(t reflect.Type, seen map[reflect.Type]bool) bool {
+
+	if t == reflect.TypeOf(time.Time{}) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+		return false
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return eventKind(t.Elem(), seen)
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && eventKind(t.Elem(), seen)
+	case reflect.Struct:
+		if seen[t] {
+			return true
+		}
+		seen[t] = true
+
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).PkgPath == "" && !eventKind(t.Field(i).Type, seen) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,405 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	crashLoopStateFile = "./exceptions/crashloop.state" //崩溃记录状态文件
+)
+
+var (
+	crashTailLock     sync.Mutex      //最近日志线程锁
+	crashTailOn       int32           //是否记录最近日志
+	crashTail         [][]byte        //最近的主日志，环形缓冲
+	crashTailNext     int             //下一条日志的位置
+	crashTailFull     bool            //环形缓冲是否已经写满
+	crashLoopLock     sync.Mutex      //崩溃记录线程锁
+	crashLoopCount    int             //崩溃次数阈值，0表示不检测
+	crashLoopWindow   time.Duration   //崩溃统计时间窗口
+	crashLoopCallback func(count int) //检测到崩溃循环后的回调
+)
+
+/******************************************************************************
+ @brief
+ 	设置崩溃循环检测，CatchException每次捕获到异常都会记录到状态文件中，
+ 	如果在window时间内崩溃次数达到count次，那么会输出FATAL日志并调用callback
+ 		例：
+ 			logger.SetCrashLoop(5, 10*time.Minute, func(count int) {
+ 				feature.Disable("new_battle")
+ 			})
+ @author
+ 	agent
+ @param
+	count				崩溃次数阈值，小于等于0表示关闭检测
+	window				统计时间窗口
+	callback			检测到崩溃循环时的回调，可以为nil
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:09 	agent		创建
+*******************************************************************************/
+func SetCrashLoop(count int, window time.Duration, callback func(count int)) {
+	crashLoopLock.Lock()
+	defer crashLoopLock.Unlock()
+
+	crashLoopCount = count
+	crashLoopWindow = window
+	crashLoopCallback = callback
+}
+
+/******************************************************************************
+ @brief
+ 	设置异常报告中附带的最近日志行数，开启后在内存中保留最近n行主日志，
+ 	CatchException写入异常报告时一并写入，panic之前的过程和调用栈在同一个文件中
+ 		例：
+ 			logger.SetCrashTail(200)
+ @author
+ 	agent
+ @param
+	n					保留的日志行数，小于等于0表示关闭
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:52 	agent		创建
+*******************************************************************************/
+func SetCrashTail(n int) {
+	crashTailLock.Lock()
+	defer crashTailLock.Unlock()
+
+	if n <= 0 {
+		atomic.StoreInt32(&crashTailOn, 0)
+		crashTail = nil
+	} else {
+		crashTail = make([][]byte, n)
+		atomic.StoreInt32(&crashTailOn, 1)
+	}
+	crashTailNext = 0
+	crashTailFull = false
+}
+
+/******************************************************************************
+ @brief
+ 	记录一行主日志到最近日志，没有开启时直接返回
+ @author
+ 	agent
+ @param
+	b					日志行，调用者之后不能修改
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:52 	agent		创建
+*******************************************************************************/
+func crashTailAppend(b []byte) {
+	if atomic.LoadInt32(&crashTailOn) == 0 {
+		return
+	}
+
+	crashTailLock.Lock()
+	defer crashTailLock.Unlock()
+
+	if len(crashTail) == 0 {
+		return
+	}
+
+	crashTail[crashTailNext] = b
+	crashTailNext = (crashTailNext + 1) % len(crashTail)
+	if crashTailNext == 0 {
+		crashTailFull = true
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	获取最近的主日志，按时间从旧到新排序
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	[][]byte			返回日志行列表
+ @history
+ 	2026-10-16_14:52 	agent		创建
+*******************************************************************************/
+func crashTailLines() [][]byte {
+	crashTailLock.Lock()
+	defer crashTailLock.Unlock()
+
+	if !crashTailFull {
+		return append([][]byte(nil), crashTail[:crashTailNext]...)
+	}
+
+	lines := make([][]byte, 0, len(crashTail))
+	lines = append(lines, crashTail[crashTailNext:]...)
+	return append(lines, crashTail[:crashTailNext]...)
+}
+
+/******************************************************************************
+ @brief
+ 	记录一次崩溃，并检查是否已经进入崩溃循环
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:09 	agent		创建
+*******************************************************************************/
+func recordCrash() {
+	crashLoopLock.Lock()
+	count, window, callback := crashLoopCount, crashLoopWindow, crashLoopCallback
+	if count <= 0 {
+		crashLoopLock.Unlock()
+		return
+	}
+
+	//读取窗口内的崩溃记录，并追加本次崩溃
+	now := time.Now()
+	stamps := readCrashStamps(now.Add(-window))
+	stamps = append(stamps, now.Unix())
+	writeCrashStamps(stamps)
+	crashLoopLock.Unlock()
+
+	if len(stamps) < count {
+		return
+	}
+
+	//先回调，FATAL日志可能会退出进程
+	if callback != nil {
+		callback(len(stamps))
+	}
+
+	Fatalf(`
+===============================================================================
+CRASH LOOP: %d crashes within %v
+===============================================================================`,
+		len(stamps),
+		window)
+}
+
+/******************************************************************************
+ @brief
+ 	读取状态文件中since之后的崩溃时间戳
+ @author
+ 	agent
+ @param
+	since				起始时间
+ @return
+ 	[]int64				返回崩溃时间戳列表
+ @history
+ 	2026-10-16_14:09 	agent		创建
+ 	2026-10-16_16:25 	agent		文件操作通过日志存储接口完成
+*******************************************************************************/
+func readCrashStamps(since time.Time) []int64 {
+
+	data, err := storageReadFile(crashLoopStateFile)
+	if err != nil {
+		return nil
+	}
+
+	stamps := []int64{}
+	for _, s := range strings.Split(string(data), "\n") {
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		if n >= since.Unix() {
+			stamps = append(stamps, n)
+		}
+	}
+
+	return stamps
+}
+
+/******************************************************************************
+ @brief
+ 	将崩溃时间戳写入状态文件
+ @author
+ 	agent
+ @param
+	stamps				崩溃时间戳列表
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:09 	agent		创建
+ 	2026-10-16_16:25 	agent		文件操作通过日志存储接口完成
+*******************************************************************************/
+func writeCrashStamps(stamps []int64) {
+
+	logStorage.MkdirAll("./exceptions/", os.ModePerm)
+
+	lines := make([]string, 0, len(stamps))
+	for _, n := range stamps {
+		lines = append(lines, fmt.Sprintf("%d", n))
+	}
+
+	storageWriteFile(crashLoopStateFile, []byte(strings.Join(lines, "\n")+"\n"), os.ModePerm)
+}
+
+/******************************************************************************
+ @brief
+ 	解析后的异常报告
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:51 	agent		创建
+ 	2026-10-16_14:52 	agent		增加最近日志
+ 	2026-10-16_15:03 	agent		增加编译信息
+ 	2026-10-16_15:03 	agent		增加重复次数
+*******************************************************************************/
+type CRASH_REPORT struct {
+	Path    string    //异常文件路径
+	Time    time.Time //异常发生时间，精确到秒
+	Error   string    //panic的值
+	Build   string    //编译信息，没有开启SetBuildFields时为空
+	Stack   string    //调用栈
+	Tail    []string  //异常发生前的最近日志，没有开启SetCrashTail时为空
+	Repeats int       //开启SetPanicDedup时，之后相同异常重复发生的次数
+}
+
+/******************************************************************************
+ @brief
+ 	列出异常目录中since之后的异常报告，按时间从旧到新排序，
+ 	管理后台可以直接展示最近的panic，不需要登录机器查找文件
+ 		例：
+ 			crashes, err := logger.ListCrashes(time.Now().Add(-24 * time.Hour))
+ @author
+ 	agent
+ @param
+	since				起始时间
+ @return
+ 	[]CRASH_REPORT		返回异常报告列表
+ 	error				读取异常目录失败时返回错误信息
+ @history
+ 	2026-10-16_14:51 	agent		创建
+ 	2026-10-16_15:42 	agent		通过日志存储读取
+*******************************************************************************/
+func ListCrashes(since time.Time) ([]CRASH_REPORT, error) {
+
+	files, err := logStorage.Glob(filepath.Join("exceptions", "????-??-??", "exceptions.*.log"))
+	if err != nil {
+		return nil, err
+	}
+
+	reports := []CRASH_REPORT{}
+	for _, fn := range files {
+		if t, ok := crashTime(fn); ok && t.Before(since) {
+			continue
+		}
+
+		report, err := ReadCrash(fn)
+		if err != nil || report.Time.Before(since) {
+			continue
+		}
+		reports = append(reports, report)
+	}
+
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].Time.Before(reports[j].Time)
+	})
+
+	return reports, nil
+}
+
+/******************************************************************************
+ @brief
+ 	读取并解析一个异常报告
+ @author
+ 	agent
+ @param
+	path				异常文件路径
+ @return
+ 	CRASH_REPORT		返回异常报告
+ 	error				读取失败或不是异常报告时返回错误信息
+ @history
+ 	2026-10-16_14:51 	agent		创建
+ 	2026-10-16_14:52 	agent		解析最近日志
+ 	2026-10-16_15:03 	agent		读取编译信息
+ 	2026-10-16_15:03 	agent		读取重复次数
+ 	2026-10-16_15:42 	agent		通过日志存储读取
+*******************************************************************************/
+func ReadCrash(path string) (CRASH_REPORT, error) {
+
+	data, err := storageReadFile(path)
+	if err != nil {
+		return CRASH_REPORT{}, err
+	}
+
+	report := CRASH_REPORT{Path: path}
+	if t, ok := crashTime(path); ok {
+		report.Time = t
+	} else if fi, err := logStorage.Stat(path); err == nil {
+		report.Time = fi.ModTime()
+	}
+
+	//报告格式为分隔线、EXCEPTION行、可选的BUILD行、分隔线、调用栈，之后可能有分隔线、RECENT LOG行、分隔线、最近日志，
+	//末尾可能有重复发生时追加的REPEATED行
+	lines := strings.Split(string(data), "\n")
+	separators := 0
+	stack := []string{}
+	found := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "====="):
+			separators += 1
+		case strings.HasPrefix(trimmed, "REPEATED: "):
+			report.Repeats += 1
+		case separators == 1 && strings.HasPrefix(trimmed, "EXCEPTION: "):
+			report.Error = strings.TrimPrefix(trimmed, "EXCEPTION: ")
+			found = true
+		case separators == 1 && strings.HasPrefix(trimmed, "BUILD: "):
+			report.Build = strings.TrimPrefix(trimmed, "BUILD: ")
+		case separators == 2:
+			stack = append(stack, line)
+		case separators >= 4 && len(trimmed) > 0:
+			report.Tail = append(report.Tail, line)
+		}
+	}
+
+	if !found {
+		return CRASH_REPORT{}, fmt.Errorf("logger: %s is not a crash report", path)
+	}
+
+	report.Stack = strings.TrimSpace(strings.Join(stack, "\n"))
+	return report, nil
+}
+
+/******************************************************************************
+ @brief
+ 	根据异常文件路径中的日期目录和文件名获取异常发生时间
+ @author
+ 	agent
+ @param
+	path				异常文件路径，例如exceptions/2026-10-17/exceptions.04_00_00_1.log
+ @return
+ 	time.Time			返回异常发生时间
+ 	bool				路径格式不正确时返回false
+ @history
+ 	2026-10-16_14:51 	agent		创建
+*******************************************************************************/
+func crashTime(path string) (time.Time, bool) {
+
+	name := strings.TrimPrefix(filepath.Base(path), "exceptions.")
+	if len(name) < len("15_04_05") {
+		return time.Time{}, false
+	}
+
+	day := filepath.Base(filepath.Dir(path))
+	t, err := time.ParseInLocation("2006-01-02 15_04_05", day+" "+name[:len("15_04_05")], time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
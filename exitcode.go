@@ -0,0 +1,226 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	EXIT_FATAL = "fatal" //FATAL日志没有错误分类字段时的分类
+	EXIT_PANIC = "panic" //异常值不是error或无法分类时的分类
+)
+
+/******************************************************************************
+ @brief
+ 	进程退出后给守护进程的重启建议
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:25 	agent		创建
+*******************************************************************************/
+type RESTART_HINT int
+
+const (
+	RESTART_NOW     RESTART_HINT = iota //立即重启
+	RESTART_BACKOFF                     //等待Backoff后重启，例如依赖的服务不可用
+	RESTART_NEVER                       //不要重启，例如配置错误，重启也会失败
+)
+
+/******************************************************************************
+ @brief
+ 	错误分类对应的退出码和重启建议
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:25 	agent		创建
+*******************************************************************************/
+type EXIT_RULE struct {
+	Code    int           //退出码，1到125
+	Restart RESTART_HINT  //重启建议
+	Backoff time.Duration //RESTART_BACKOFF时建议的等待时间
+}
+
+var (
+	exitLock  sync.Mutex           //退出规则线程锁
+	exitRules map[string]EXIT_RULE //错误分类对应的退出规则
+)
+
+/******************************************************************************
+ @brief
+ 	获取重启建议名称
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	string				返回now、backoff或never
+ @history
+ 	2026-10-16_15:25 	agent		创建
+*******************************************************************************/
+func (h RESTART_HINT) String() string {
+	switch h {
+	case RESTART_BACKOFF:
+		return "backoff"
+	case RESTART_NEVER:
+		return "never"
+	default:
+		return "now"
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	设置错误分类对应的退出码和重启建议，守护进程可以根据退出码决定是否重启、等待多久。
+ 	SetFatalExit(true)时FATAL日志按日志中第一个xxx_class字段（ErrorField生成）分类，没有时为EXIT_FATAL；
+ 	CatchExit捕获的异常按Classify分类，异常值不是error或分类为ERROR_OTHER时为EXIT_PANIC；
+ 	具体分类没有设置时再查找EXIT_FATAL或EXIT_PANIC，都没有设置时FATAL日志退出码为1，异常为2。
+ 	设置了任何规则后，退出前会写入一条restart hint日志
+ 		例：
+ 			logger.SetExitCode(logger.ERROR_VALIDATION, logger.EXIT_RULE{Code: 78, Restart: logger.RESTART_NEVER})
+ 			logger.SetExitCode(logger.ERROR_IO, logger.EXIT_RULE{Code: 74, Restart: logger.RESTART_BACKOFF, Backoff: 30 * time.Second})
+
+ 			logger.Fatalf("load config failed %s", logger.ErrorField("err", err))
+
+ 		输出：ERROR restart hint class=validation exit_code=78 restart=never reason="load config failed err=..."
+ @author
+ 	agent
+ @param
+	class				错误分类
+	rule				退出规则，Code为0表示删除该分类的规则
+ @return
+ 	error				退出码超出范围时返回错误信息
+ @history
+ 	2026-10-16_15:25 	agent		创建
+*******************************************************************************/
+func SetExitCode(class string, rule EXIT_RULE) error {
+	if rule.Code < 0 || rule.Code > 125 {
+		return fmt.Errorf("logger: exit code %d for %s out of range 1-125", rule.Code, class)
+	}
+
+	exitLock.Lock()
+	defer exitLock.Unlock()
+
+	if rule.Code == 0 {
+		delete(exitRules, class)
+		return nil
+	}
+
+	if exitRules == nil {
+		exitRules = map[string]EXIT_RULE{}
+	}
+	exitRules[class] = rule
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	捕获异常，记录到异常目录后按SetExitCode设置的退出码退出进程，
+ 	用于异常后不应继续运行的协程，例如主协程
+ 		例：
+ 			func main() {
+ 				defer logger.CatchExit()
+ 				...
+ 			}
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:25 	agent		创建
+*******************************************************************************/
+func CatchExit() {
+	if err := recover(); err != nil {
+		dumpException(err)
+
+		class := EXIT_PANIC
+		if e, ok := err.(error); ok {
+			if c := Classify(e); c != ERROR_OTHER {
+				class = c
+			}
+		}
+
+		code := exitHint(class, EXIT_PANIC, 2, fmt.Sprint(err))
+		Flush()
+		os.Exit(code)
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	获取FATAL日志内容中的错误分类
+ @author
+ 	agent
+ @param
+	arg					日志内容
+ @return
+ 	string				返回第一个xxx_class字段的值，没有时返回EXIT_FATAL
+ @history
+ 	2026-10-16_15:25 	agent		创建
+*******************************************************************************/
+func fatalClass(arg string) string {
+	_, fields := parseFields(strings.TrimRight(arg, "\n"))
+	for _, field := range fields {
+		if strings.HasSuffix(field[0], "_class") && len(field[1]) > 0 {
+			return field[1]
+		}
+	}
+
+	return EXIT_FATAL
+}
+
+/******************************************************************************
+ @brief
+ 	查找退出规则，设置了规则时写入restart hint日志，直接写入不经过级别、过滤和采样检查
+ @author
+ 	agent
+ @param
+	class				错误分类
+	kind				具体分类没有规则时查找的分类，EXIT_FATAL或EXIT_PANIC
+	code				都没有规则时的退出码
+	reason				退出原因
+ @return
+ 	int					返回退出码
+ @history
+ 	2026-10-16_15:25 	agent		创建
+ 	2026-10-16_15:38 	agent		直接写入，不经过级别、过滤和采样检查
+ 	2026-10-16_16:55 	agent		不输出调用者信息
+*******************************************************************************/
+func exitHint(class, kind string, code int, reason string) int {
+	exitLock.Lock()
+	if len(exitRules) == 0 {
+		exitLock.Unlock()
+		return code
+	}
+	rule, ok := exitRules[class]
+	if !ok {
+		rule, ok = exitRules[kind]
+	}
+	exitLock.Unlock()
+
+	if !ok {
+		rule = EXIT_RULE{Code: code, Restart: RESTART_NOW}
+	}
+
+	fields := []string{
+		"restart hint",
+		configField("class", class),
+		configField("exit_code", strconv.Itoa(rule.Code)),
+		configField("restart", rule.Restart.String()),
+	}
+	if rule.Restart == RESTART_BACKOFF {
+		fields = append(fields, configField("backoff_ms", strconv.FormatInt(rule.Backoff.Milliseconds(), 10)))
+	}
+	fields = append(fields, configField("reason", reason))
+
+	//不受日志级别、过滤规则和风暴采样影响，守护进程总能看到退出原因，
+	//不是经过outputFile调用，获取的调用者信息不正确，不输出
+	outputEntry(logFile, ERROR, strings.Join(fields, " ")+"\n", nil, false)
+
+	return rule.Code
+}
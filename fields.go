@@ -0,0 +1,138 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+/******************************************************************************
+ @brief
+ 	公共字段快照，修改时整体替换，写入日志时不需要加锁
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:43 	agent		创建
+*******************************************************************************/
+type fieldsSnapshot struct {
+	list [][2]string //公共字段列表，按添加顺序输出
+	text string      //公共字段的文本格式缓存
+}
+
+var (
+	fieldLock sync.Mutex   //公共字段修改线程锁
+	logFields atomic.Value //公共字段快照*fieldsSnapshot
+)
+
+/******************************************************************************
+ @brief
+ 	设置公共字段，所有日志都会带上公共字段，文本格式以key=value的形式追加到日志内容后面，
+ 	JSON格式作为独立的字段输出。同名字段会被替换，值为空字符串时删除字段
+ 		例：
+ 			logger.SetField("server", "login01")
+ 			logger.Info("started")
+
+ 		输出：INFO started server=login01
+ @author
+ 	agent
+ @param
+	key					字段名称，不能包含空白、等号和引号
+	value				字段值
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:17 	agent		创建
+ 	2026-10-16_14:43 	agent		公共字段改为整体替换，读取不需要加锁
+*******************************************************************************/
+func SetField(key, value string) {
+	if !validFieldKey(key) {
+		return
+	}
+
+	fieldLock.Lock()
+	defer fieldLock.Unlock()
+
+	current := fieldsList()
+	fields := make([][2]string, 0, len(current)+1)
+	found := false
+	for _, field := range current {
+		if field[0] == key {
+			found = true
+			if value == "" {
+				continue
+			}
+			field[1] = value
+		}
+		fields = append(fields, field)
+	}
+
+	if !found && value != "" {
+		fields = append(fields, [2]string{key, value})
+	}
+
+	//生成文本格式缓存
+	text := ""
+	for _, field := range fields {
+		text += " " + configField(field[0], field[1])
+	}
+
+	logFields.Store(&fieldsSnapshot{list: fields, text: text})
+}
+
+/******************************************************************************
+ @brief
+ 	获取公共字段的文本格式，以空格开头，没有公共字段时返回空字符串
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	string				返回文本格式的公共字段
+ @history
+ 	2026-10-16_14:17 	agent		创建
+ 	2026-10-16_14:43 	agent		公共字段改为整体替换，读取不需要加锁
+*******************************************************************************/
+func fieldsText() string {
+	if s, _ := logFields.Load().(*fieldsSnapshot); s != nil {
+		return s.text
+	}
+
+	return ""
+}
+
+/******************************************************************************
+ @brief
+ 	获取公共字段列表
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	[][2]string			返回公共字段列表，调用者不能修改
+ @history
+ 	2026-10-16_14:17 	agent		创建
+ 	2026-10-16_14:43 	agent		公共字段改为整体替换，读取不需要加锁
+*******************************************************************************/
+func fieldsList() [][2]string {
+	if s, _ := logFields.Load().(*fieldsSnapshot); s != nil {
+		return s.list
+	}
+
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	判断字段名称是否有效
+ @author
+ 	agent
+ @param
+	key					字段名称
+ @return
+ 	bool				返回true表示有效
+ @history
+ 	2026-10-16_14:17 	agent		创建
+*******************************************************************************/
+func validFieldKey(key string) bool {
+	return len(key) > 0 && !strings.ContainsAny(key, " \t\r\n=\"")
+}
@@ -0,0 +1,158 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	fifoRetry = time.Second //没有读取方时重新打开命名管道的间隔
+)
+
+/******************************************************************************
+ @brief
+ 	命名管道输出目标类结构，以非阻塞方式写入，没有读取方或管道已满时直接丢弃日志，
+ 	采集程序没有运行也不会阻塞写日志
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:21 	agent		创建
+*******************************************************************************/
+type FIFO_WRITER struct {
+	sync.Mutex           //线程锁
+	path       string    //命名管道路径
+	fd         int       //打开的文件描述符，-1表示没有打开
+	nextOpen   time.Time //下次尝试打开的时间
+	dropped    int64     //丢弃的字节数
+}
+
+/******************************************************************************
+ @brief
+ 	创建命名管道输出目标，管道不存在时自动创建，用于对接从命名管道读取日志的旧采集程序。
+ 	每次写入时如果还没有读取方，最多每秒重试打开一次，期间的日志被丢弃；
+ 	读取方退出后自动关闭，等待下一个读取方
+ 		例：
+ 			w, err := logger.NewFIFOWriter("/var/run/gameserver.log.fifo")
+ 			if err == nil {
+ 				logger.AddSink("fifo", w, logger.BUFFER_NONE, 0, 0)
+ 			}
+ @author
+ 	agent
+ @param
+	path				命名管道路径
+ @return
+ 	*FIFO_WRITER		返回输出目标
+ 	error				路径已存在但不是命名管道、创建失败或当前平台不支持时返回错误信息
+ @history
+ 	2026-10-16_15:21 	agent		创建
+*******************************************************************************/
+func NewFIFOWriter(path string) (*FIFO_WRITER, error) {
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		if err := fifoMake(path); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	case info.Mode()&os.ModeNamedPipe == 0:
+		return nil, fmt.Errorf("logger: %s is not a named pipe", path)
+	}
+
+	return &FIFO_WRITER{path: path, fd: -1}, nil
+}
+
+/******************************************************************************
+ @brief
+ 	非阻塞写入日志行，没有读取方或管道已满时丢弃
+ @author
+ 	agent
+ @param
+	b					要写入的日志行
+ @return
+ 	int					返回写入的字节数，丢弃的日志也计算在内
+ 	error				总是返回nil，丢弃不会触发输出目标的重试
+ @history
+ 	2026-10-16_15:21 	agent		创建
+*******************************************************************************/
+func (w *FIFO_WRITER) Write(b []byte) (int, error) {
+	w.Lock()
+	defer w.Unlock()
+
+	if w.fd < 0 {
+		now := time.Now()
+		if now.Before(w.nextOpen) {
+			atomic.AddInt64(&w.dropped, int64(len(b)))
+			return len(b), nil
+		}
+
+		fd, err := fifoOpen(w.path)
+		if err != nil {
+			w.nextOpen = now.Add(fifoRetry)
+			atomic.AddInt64(&w.dropped, int64(len(b)))
+			return len(b), nil
+		}
+		w.fd = fd
+	}
+
+	//管道剩余空间不足时可能只写入一部分，剩下的丢弃
+	n, err := fifoWrite(w.fd, b)
+	if n < 0 {
+		n = 0
+	}
+	if n < len(b) {
+		atomic.AddInt64(&w.dropped, int64(len(b)-n))
+	}
+
+	//读取方退出等其它错误关闭管道，等待下一个读取方
+	if err != nil && !fifoFull(err) {
+		fifoClose(w.fd)
+		w.fd = -1
+	}
+
+	return len(b), nil
+}
+
+/******************************************************************************
+ @brief
+ 	关闭命名管道，不删除管道文件
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	error				关闭失败时返回错误信息
+ @history
+ 	2026-10-16_15:21 	agent		创建
+*******************************************************************************/
+func (w *FIFO_WRITER) Close() error {
+	w.Lock()
+	defer w.Unlock()
+
+	if w.fd < 0 {
+		return nil
+	}
+
+	err := fifoClose(w.fd)
+	w.fd = -1
+	return err
+}
+
+/******************************************************************************
+ @brief
+ 	获取因为没有读取方或管道已满而丢弃的字节数
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	int64				返回丢弃的字节数
+ @history
+ 	2026-10-16_15:21 	agent		创建
+*******************************************************************************/
+func (w *FIFO_WRITER) Dropped() int64 {
+	return atomic.LoadInt64(&w.dropped)
+}
@@ -0,0 +1,91 @@
+//go:build !linux && !darwin && !freebsd
+// +build !linux,!darwin,!freebsd
+
+package logger
+
+import (
+	"fmt"
+)
+
+/******************************************************************************
+ @brief
+ 	创建命名管道，当前平台不支持
+ @author
+ 	agent
+ @param
+	path				命名管道路径
+ @return
+ 	error				返回错误信息
+ @history
+ 	2026-10-16_15:21 	agent		创建
+*******************************************************************************/
+func fifoMake(path string) error {
+	return fmt.Errorf("logger: named pipes are not supported on this platform")
+}
+
+/******************************************************************************
+ @brief
+ 	打开命名管道，当前平台不支持
+ @author
+ 	agent
+ @param
+	path				命名管道路径
+ @return
+ 	int					返回-1
+ 	error				返回错误信息
+ @history
+ 	2026-10-16_15:21 	agent		创建
+*******************************************************************************/
+func fifoOpen(path string) (int, error) {
+	return -1, fmt.Errorf("logger: named pipes are not supported on this platform")
+}
+
+/******************************************************************************
+ @brief
+ 	写入命名管道，当前平台不支持
+ @author
+ 	agent
+ @param
+	fd					文件描述符
+	b					要写入的内容
+ @return
+ 	int					返回0
+ 	error				返回错误信息
+ @history
+ 	2026-10-16_15:21 	agent		创建
+*******************************************************************************/
+func fifoWrite(fd int, b []byte) (int, error) {
+	return 0, fmt.Errorf("logger: named pipes are not supported on this platform")
+}
+
+/******************************************************************************
+ @brief
+ 	关闭命名管道，当前平台不支持
+ @author
+ 	agent
+ @param
+	fd					文件描述符
+ @return
+ 	error				返回nil
+ @history
+ 	2026-10-16_15:21 	agent		创建
+*******************************************************************************/
+func fifoClose(fd int) error {
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	判断写入失败是否只是管道已满，当前平台不支持
+ @author
+ 	agent
+ @param
+	err					写入错误
+ @return
+ 	bool				返回false
+ @history
+ 	2026-10-16_15:21 	agent		创建
+*******************************************************************************/
+func fifoFull(err error) bool {
+	return false
+}
@@ -0,0 +1,97 @@
+//go:build linux || darwin || freebsd
+// +build linux darwin freebsd
+
+package logger
+
+import (
+	"syscall"
+)
+
+/******************************************************************************
+ @brief
+ 	创建命名管道
+ @author
+ 	agent
+ @param
+	path				命名管道路径
+ @return
+ 	error				创建失败时返回错误信息
+ @history
+ 	2026-10-16_15:21 	agent		创建
+*******************************************************************************/
+func fifoMake(path string) error {
+	return syscall.Mkfifo(path, 0600)
+}
+
+/******************************************************************************
+ @brief
+ 	以非阻塞方式打开命名管道，没有读取方时返回ENXIO。
+ 	不使用os.File，否则写入时会进入运行时的轮询器等待，失去非阻塞的效果
+ @author
+ 	agent
+ @param
+	path				命名管道路径
+ @return
+ 	int					返回文件描述符
+ 	error				打开失败时返回错误信息
+ @history
+ 	2026-10-16_15:21 	agent		创建
+*******************************************************************************/
+func fifoOpen(path string) (int, error) {
+	return syscall.Open(path, syscall.O_WRONLY|syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0)
+}
+
+/******************************************************************************
+ @brief
+ 	写入命名管道，读取方已经退出时返回EPIPE，进程不会因为SIGPIPE退出
+ @author
+ 	agent
+ @param
+	fd					文件描述符
+	b					要写入的内容
+ @return
+ 	int					返回写入的字节数
+ 	error				写入失败时返回错误信息
+ @history
+ 	2026-10-16_15:21 	agent		创建
+*******************************************************************************/
+func fifoWrite(fd int, b []byte) (int, error) {
+	for {
+		n, err := syscall.Write(fd, b)
+		if err != syscall.EINTR {
+			return n, err
+		}
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	关闭命名管道
+ @author
+ 	agent
+ @param
+	fd					文件描述符
+ @return
+ 	error				关闭失败时返回错误信息
+ @history
+ 	2026-10-16_15:21 	agent		创建
+*******************************************************************************/
+func fifoClose(fd int) error {
+	return syscall.Close(fd)
+}
+
+/******************************************************************************
+ @brief
+ 	判断写入失败是否只是管道已满
+ @author
+ 	agent
+ @param
+	err					写入错误
+ @return
+ 	bool				管道已满返回true
+ @history
+ 	2026-10-16_15:21 	agent		创建
+*******************************************************************************/
+func fifoFull(err error) bool {
+	return err == syscall.EAGAIN || err == syscall.EWOULDBLOCK
+}
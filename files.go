@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	获取主日志当前写入的文件路径
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	string				返回文件路径，Initialize之前返回空字符串
+ @history
+ 	2026-10-16_15:09 	agent		创建
+*******************************************************************************/
+func CurrentFile() string {
+	if logFile == nil {
+		return ""
+	}
+
+	if h := logFile.current(); h != nil {
+		return filepath.Clean(h.path)
+	}
+
+	return ""
+}
+
+/******************************************************************************
+ @brief
+ 	获取主日志、分类日志和租户日志在since之后修改过的全部文件路径，包括正在写入的文件，
+ 	备份脚本和问题收集不需要自己按照命名规则查找文件
+ 		例：
+ 			//最近一天的日志文件
+ 			files := logger.Files(time.Now().Add(-24 * time.Hour))
+ @author
+ 	agent
+ @param
+	since				起始时间，零值表示全部文件
+ @return
+ 	[]string			返回按路径排序的文件列表
+ @history
+ 	2026-10-16_15:09 	agent		创建
+*******************************************************************************/
+func Files(since time.Time) []string {
+
+	logs := append(categoryFiles(), tenantFiles()...)
+	if logFile != nil && logFile.current() != nil {
+		logs = append(logs, logFile)
+	}
+
+	seen := map[string]bool{}
+	files := []string{}
+	for _, f := range logs {
+		matches, _ := logStorage.Glob(filepath.Join(f.log_dir, "*", f.log_filename+".*.log"))
+		for _, fn := range matches {
+			if seen[fn] {
+				continue
+			}
+
+			fi, err := logStorage.Stat(fn)
+			if err != nil || fi.IsDir() || fi.ModTime().Before(since) {
+				continue
+			}
+
+			seen[fn] = true
+			files = append(files, fn)
+		}
+	}
+
+	sort.Strings(files)
+	return files
+}
@@ -0,0 +1,224 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	日志过滤规则，用于事故期间临时压制日志风暴
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:58 	agent		创建
+*******************************************************************************/
+type FILTER_RULE struct {
+	Name    string    //规则名称，同名规则会被替换
+	Match   string    //日志内容包含此字符串时匹配，为空时匹配所有日志
+	Level   LEVEL     //只处理不高于此级别的日志，FATAL日志总是保留
+	Sample  int       //匹配的日志每Sample条保留1条，小于等于1时全部丢弃
+	Expires time.Time //到期时间，零值表示一直有效
+	Dropped int64     //已经丢弃的条数，只在Filters返回时有效
+}
+
+/******************************************************************************
+ @brief
+ 	生效中的过滤规则
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:58 	agent		创建
+*******************************************************************************/
+type filterRule struct {
+	FILTER_RULE
+	seen    int64 //匹配的条数
+	dropped int64 //丢弃的条数
+}
+
+var (
+	filterLock  sync.Mutex   //过滤规则列表修改线程锁
+	filterValue atomic.Value //过滤规则列表[]*filterRule，修改时整体替换，写入日志不需要加锁
+)
+
+/******************************************************************************
+ @brief
+ 	添加过滤规则，立即生效，也可以通过AdminHandler挂载的管理接口提交：
+ 		curl -d '{"name":"reset","match":"connection reset","level":"WARN","duration":"10m"}' \
+ 			"http://127.0.0.1:18000/debug/logger/filters"
+ 		例：
+ 			//10分钟内丢弃包含connection reset的WARN及以下级别日志
+ 			logger.AddFilter(logger.FILTER_RULE{
+ 				Name:    "reset",
+ 				Match:   "connection reset",
+ 				Level:   logger.WARN,
+ 				Expires: time.Now().Add(10 * time.Minute),
+ 			})
+ @author
+ 	agent
+ @param
+	rule				过滤规则
+ @return
+ 	error				规则名称为空时返回错误信息
+ @history
+ 	2026-10-16_14:58 	agent		创建
+*******************************************************************************/
+func AddFilter(rule FILTER_RULE) error {
+
+	if len(rule.Name) == 0 {
+		return fmt.Errorf("logger: filter rule needs a name")
+	}
+
+	rule.Dropped = 0
+
+	filterLock.Lock()
+	defer filterLock.Unlock()
+
+	now := time.Now()
+	rules := []*filterRule{}
+	for _, fr := range filterList() {
+		if fr.Name != rule.Name && !fr.expired(now) {
+			rules = append(rules, fr)
+		}
+	}
+	filterValue.Store(append(rules, &filterRule{FILTER_RULE: rule}))
+
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	删除过滤规则
+ @author
+ 	agent
+ @param
+	name				规则名称
+ @return
+ 	bool				规则不存在时返回false
+ @history
+ 	2026-10-16_14:58 	agent		创建
+*******************************************************************************/
+func RemoveFilter(name string) bool {
+	filterLock.Lock()
+	defer filterLock.Unlock()
+
+	rules := filterList()
+	for i, fr := range rules {
+		if fr.Name == name {
+			filterValue.Store(append(rules[:i:i], rules[i+1:]...))
+			return true
+		}
+	}
+
+	return false
+}
+
+/******************************************************************************
+ @brief
+ 	获取生效中的过滤规则，已经到期的规则不会返回
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	[]FILTER_RULE		返回过滤规则列表
+ @history
+ 	2026-10-16_14:58 	agent		创建
+*******************************************************************************/
+func Filters() []FILTER_RULE {
+	now := time.Now()
+	rules := []FILTER_RULE{}
+	for _, fr := range filterList() {
+		if fr.expired(now) {
+			continue
+		}
+
+		rule := fr.FILTER_RULE
+		rule.Dropped = atomic.LoadInt64(&fr.dropped)
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+/******************************************************************************
+ @brief
+ 	获取当前的过滤规则列表，返回的列表不能修改
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	[]*filterRule		返回过滤规则列表
+ @history
+ 	2026-10-16_14:58 	agent		创建
+*******************************************************************************/
+func filterList() []*filterRule {
+	rules, _ := filterValue.Load().([]*filterRule)
+	return rules
+}
+
+/******************************************************************************
+ @brief
+ 	判断日志是否需要丢弃
+ @author
+ 	agent
+ @param
+	ll					日志等级
+	arg					日志内容
+ @return
+ 	bool				需要丢弃时返回true
+ @history
+ 	2026-10-16_14:58 	agent		创建
+*******************************************************************************/
+func filterDrop(ll LEVEL, arg string) bool {
+	rules := filterList()
+	if len(rules) == 0 || ll >= FATAL {
+		return false
+	}
+
+	var now time.Time
+	for _, fr := range rules {
+		if ll > fr.Level || !strings.Contains(arg, fr.Match) {
+			continue
+		}
+
+		if !fr.Expires.IsZero() {
+			if now.IsZero() {
+				now = time.Now()
+			}
+			if fr.expired(now) {
+				continue
+			}
+		}
+
+		seen := atomic.AddInt64(&fr.seen, 1)
+		if fr.Sample > 1 && seen%int64(fr.Sample) == 1 {
+			continue
+		}
+
+		atomic.AddInt64(&fr.dropped, 1)
+		return true
+	}
+
+	return false
+}
+
+/******************************************************************************
+ @brief
+ 	规则是否已经到期
+ @author
+ 	agent
+ @param
+	now					当前时间
+ @return
+ 	bool				到期时返回true
+ @history
+ 	2026-10-16_14:58 	agent		创建
+*******************************************************************************/
+func (fr *filterRule) expired(now time.Time) bool {
+	return !fr.Expires.IsZero() && !now.Before(fr.Expires)
+}
@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var (
+	logCheckFlag bool //是否设置了-check-logging参数
+)
+
+/******************************************************************************
+ @brief
+ 	在命令行参数集合中注册统一的日志参数，命令行工具解析参数后日志即按参数配置好：
+ 		-log.level		日志级别，例如debug、INFO，默认ALL
+ 		-log.dir		日志目录，设置后以程序名作为文件名写入日志文件
+ 		-log.console	是否在终端控制台显示日志，-log.console=false为安静模式
+ 		-log.format		终端控制台输出格式，text或json
+ 		例：
+ 			logger.BindFlags(flag.CommandLine)
+ 			flag.Parse()
+
+ 			./tool -log.level=warn -log.dir=./log
+ @author
+ 	agent
+ @param
+	fs					命令行参数集合
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:47 	agent		创建
+*******************************************************************************/
+func BindFlags(fs *flag.FlagSet) {
+
+	fs.Func("log.level", "log level: all, debug, info, warn, error, fatal", func(s string) error {
+		level, err := ParseLevel(s)
+		if err != nil {
+			return err
+		}
+		SetLevel(level)
+		return nil
+	})
+
+	fs.Func("log.dir", "write log files to this directory, named after the program", func(s string) error {
+		if len(s) == 0 {
+			return fmt.Errorf("logger: empty log directory")
+		}
+		Initialize(s, strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe"))
+		return nil
+	})
+
+	fs.BoolFunc("log.console", "show log lines on the console (default true)", func(s string) error {
+		isConsole, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		SetConsole(isConsole)
+		return nil
+	})
+
+	fs.Func("log.format", "console output format: text or json", func(s string) error {
+		switch strings.ToLower(s) {
+		case "text":
+			SetConsoleFormat(FORMAT_TEXT)
+		case "json":
+			SetConsoleFormat(FORMAT_JSON)
+		default:
+			return fmt.Errorf("logger: unknown format %q", s)
+		}
+		return nil
+	})
+}
+
+/******************************************************************************
+ @brief
+ 	在命令行参数集合中注册-check-logging参数，解析参数后调用CheckConfig，
+ 	设置了该参数时只检查日志配置并退出，不启动服务
+ 		例：
+ 			logger.BindCheckFlag(flag.CommandLine)
+ 			flag.Parse()
+
+ 			cfg := logger.Production(*logDir, "gameserver")
+ 			logger.CheckConfig(cfg)
+ 			cfg.Apply()
+
+ 			./gameserver -check-logging
+ @author
+ 	agent
+ @param
+	fs					命令行参数集合
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:08 	agent		创建
+*******************************************************************************/
+func BindCheckFlag(fs *flag.FlagSet) {
+	fs.BoolVar(&logCheckFlag, "check-logging", false, "validate the logging configuration and exit")
+}
+
+/******************************************************************************
+ @brief
+ 	设置了-check-logging参数时检查日志配置，将结果输出到标准错误后退出进程，
+ 	配置正确时退出码为0，否则为1；没有设置该参数时直接返回
+ @author
+ 	agent
+ @param
+	cfg					日志配置
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:08 	agent		创建
+*******************************************************************************/
+func CheckConfig(cfg CONFIG) {
+	if !logCheckFlag {
+		return
+	}
+
+	errs := ValidateConfig(cfg)
+	for _, err := range errs {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	if len(errs) > 0 {
+		os.Exit(1)
+	}
+
+	fmt.Fprintln(os.Stderr, "logger: configuration ok")
+	os.Exit(0)
+}
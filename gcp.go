@@ -0,0 +1,134 @@
+package logger
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	gcpSourceLocation = "logging.googleapis.com/sourceLocation" //调用者信息字段
+	gcpTrace          = "logging.googleapis.com/trace"          //调用链字段
+	gcpSpanID         = "logging.googleapis.com/spanId"         //调用链片段字段
+)
+
+var (
+	gcpProject atomic.Value //项目ID，SetJSONStyle时读取
+)
+
+/******************************************************************************
+ @brief
+ 	读取Google Cloud的项目ID，用于生成trace字段
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:15 	agent		创建
+*******************************************************************************/
+func gcpLoadEnv() {
+	project := os.Getenv("GOOGLE_CLOUD_PROJECT")
+	if len(project) == 0 {
+		project = os.Getenv("GCP_PROJECT")
+	}
+
+	gcpProject.Store(project)
+}
+
+/******************************************************************************
+ @brief
+ 	按照Google Cloud Logging从GKE、Cloud Run标准输出采集的结构化格式将日志编码为一行JSON：
+ 	severity、time、message和logging.googleapis.com/sourceLocation，
+ 	日志内容末尾的trace_id、span_id输出为logging.googleapis.com/trace、spanId，
+ 	其它key=value字段和公共字段输出到jsonPayload中。
+ 	trace字段需要项目ID，从GOOGLE_CLOUD_PROJECT或GCP_PROJECT环境变量读取
+ 		输出：{"severity":"INFO","time":"2026-10-17T21:30:00.000001Z","message":"login",
+ 			"logging.googleapis.com/sourceLocation":{"file":"main.go","function":"main.main","line":"12"},
+ 			"logging.googleapis.com/trace":"projects/my-project/traces/4bf92f3577b34da6a3ce929d0e0e4736",
+ 			"player":"10001"}
+ @author
+ 	agent
+ @param
+	buf					输出缓冲
+	t					日志时间
+	ll					日志等级
+	file				调用者文件，为空表示不输出调用者信息
+	line				调用者行号
+	fn					调用者函数名，为空表示不输出
+	msg					日志内容
+ @return
+ 	[]byte				返回追加JSON后的缓冲，以换行结尾
+ @history
+ 	2026-10-16_15:15 	agent		创建
+*******************************************************************************/
+func encodeGCP(buf []byte, t time.Time, ll LEVEL, file string, line int, fn string, msg string) []byte {
+
+	msg, fields := parseFields(strings.TrimRight(msg, "\n"))
+
+	buf = append(buf, '{')
+	buf = appendJSON(buf, "severity", gcpSeverity(ll))
+	buf = append(buf, ',')
+	buf = appendJSON(buf, "time", t.UTC().Format(time.RFC3339Nano))
+	buf = append(buf, ',')
+	buf = appendJSON(buf, "message", msg)
+
+	//行号在LogEntrySourceLocation中是int64，JSON中编码为字符串
+	if len(file) > 0 {
+		loc := map[string]string{"file": shortFile(file), "line": strconv.Itoa(line)}
+		if len(fn) > 0 {
+			loc["function"] = fn
+		}
+		buf = append(buf, ',')
+		buf = appendJSON(buf, gcpSourceLocation, loc)
+	}
+
+	project, _ := gcpProject.Load().(string)
+	for _, field := range append(fields, fieldsList()...) {
+		key, value := field[0], field[1]
+		switch key {
+		case "trace_id":
+			if len(project) == 0 {
+				break
+			}
+			key, value = gcpTrace, "projects/"+project+"/traces/"+value
+		case "span_id":
+			key = gcpSpanID
+		}
+		buf = append(buf, ',')
+		buf = appendJSON(buf, key, value)
+	}
+	buf = append(buf, '}', '\n')
+
+	return buf
+}
+
+/******************************************************************************
+ @brief
+ 	获取日志级别对应的Cloud Logging严重程度
+ @author
+ 	agent
+ @param
+	ll					日志等级
+ @return
+ 	string				返回严重程度
+ @history
+ 	2026-10-16_15:15 	agent		创建
+*******************************************************************************/
+func gcpSeverity(ll LEVEL) string {
+	switch ll {
+	case DEBUG, ALL:
+		return "DEBUG"
+	case INFO:
+		return "INFO"
+	case WARN:
+		return "WARNING"
+	case ERROR:
+		return "ERROR"
+	}
+
+	return "CRITICAL"
+}
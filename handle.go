@@ -0,0 +1,171 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	日志文件句柄，切分时整体替换，写入日志时不需要加锁：
+ 	写入方先增加引用计数再确认句柄仍然有效，切分方替换句柄后等待引用计数归零再关闭文件
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:43 	agent		创建
+ 	2026-10-16_14:49 	agent		支持文件头
+*******************************************************************************/
+type fileHandle struct {
+	path   string        //日志路径，为空表示不写入文件
+	file   STORAGE_FILE  //日志文件实例，第一次写入时创建
+	create sync.Once     //第一次写入时创建日志文件
+	opened int32         //是否已经创建日志文件
+	refs   int64         //正在写入的协程数量
+	header *atomic.Value //所属日志文件的文件头
+}
+
+/******************************************************************************
+ @brief
+ 	获取当前日志文件句柄
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	*fileHandle			返回当前句柄，Initialize之前返回nil
+ @history
+ 	2026-10-16_14:43 	agent		创建
+*******************************************************************************/
+func (f *LOG_FILE) current() *fileHandle {
+	h, _ := f.handle.Load().(*fileHandle)
+	return h
+}
+
+/******************************************************************************
+ @brief
+ 	替换当前日志文件句柄，调用者需要持有锁，并负责关闭返回的旧句柄
+ @author
+ 	agent
+ @param
+	h					新的句柄
+ @return
+ 	*fileHandle			返回旧的句柄，可能为nil
+ @history
+ 	2026-10-16_14:43 	agent		创建
+*******************************************************************************/
+func (f *LOG_FILE) swap(h *fileHandle) *fileHandle {
+	old := f.current()
+	f.handle.Store(h)
+	return old
+}
+
+/******************************************************************************
+ @brief
+ 	创建日志文件，由第一次写入触发
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:43 	agent		创建
+ 	2026-10-16_14:46 	agent		发布文件创建事件
+ 	2026-10-16_14:47 	agent		记录创建失败
+ 	2026-10-16_14:49 	agent		写入文件头
+*******************************************************************************/
+func (h *fileHandle) open() {
+	if len(h.path) == 0 {
+		return
+	}
+
+	logStorage.MkdirAll(filepath.Dir(h.path), os.ModePerm)
+
+	var err error
+	h.file, err = logStorage.OpenFile(h.path, os.O_RDWR|os.O_APPEND|os.O_CREATE, os.ModePerm)
+	if err != nil {
+		diag("open %s: %v", h.path, err)
+	}
+	diskPreallocate(h.file)
+	atomic.StoreInt32(&h.opened, 1)
+
+	if h.file != nil {
+		h.writeHeader()
+		lifecycle(LIFECYCLE_EVENT{Kind: LIFECYCLE_FILE_OPENED, File: h.path})
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	新创建的日志文件写入文件头，追加到已有文件时不写
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:49 	agent		创建
+*******************************************************************************/
+func (h *fileHandle) writeHeader() {
+	if h.header == nil {
+		return
+	}
+
+	header, ok := h.header.Load().(func() []byte)
+	if !ok {
+		return
+	}
+
+	if fi, err := h.file.Stat(); err == nil && fi.Size() == 0 {
+		h.file.Write(header())
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	判断日志文件是否已经创建
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	bool				返回true表示已经创建
+ @history
+ 	2026-10-16_14:43 	agent		创建
+*******************************************************************************/
+func (h *fileHandle) isOpened() bool {
+	return atomic.LoadInt32(&h.opened) != 0
+}
+
+/******************************************************************************
+ @brief
+ 	关闭已经被替换的句柄，等待正在进行的写入完成后关闭文件
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	bool				返回true表示文件曾经创建并已关闭
+ @history
+ 	2026-10-16_14:43 	agent		创建
+*******************************************************************************/
+func (h *fileHandle) close() bool {
+
+	//之后不会再创建文件
+	h.create.Do(func() {})
+
+	for atomic.LoadInt64(&h.refs) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if h.file == nil {
+		return false
+	}
+
+	h.file.Close()
+	return true
+}
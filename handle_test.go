@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 切分时先替换句柄，新的写入进入新文件；正在写入旧句柄时等待写入完成后才关闭旧文件
+func TestRotateWaitsForWriters(t *testing.T) {
+	setupStress(t)
+	Info("before")
+
+	old := logFile.current()
+	if old == nil || old.file == nil {
+		t.Fatal("log file not opened by the first write")
+	}
+
+	//模拟一次正在进行的写入
+	atomic.AddInt64(&old.refs, 1)
+
+	done := make(chan struct{})
+	go func() {
+		Rotate()
+		close(done)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for logFile.current() == old {
+		if time.Now().After(deadline) {
+			t.Fatal("handle not swapped by Rotate")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	//切分等待旧句柄期间，新的写入不会被阻塞
+	Info("during")
+
+	select {
+	case <-done:
+		t.Fatal("Rotate returned while a write was still in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if _, err := old.file.Write([]byte("inflight\n")); err != nil {
+		t.Fatalf("old file closed before the in-flight write finished: %v", err)
+	}
+	atomic.AddInt64(&old.refs, -1)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Rotate did not return after the write finished")
+	}
+
+	if _, err := old.file.Write([]byte("late\n")); err == nil {
+		t.Fatal("old file still open after Rotate")
+	}
+
+	cur := logFile.current()
+	if cur.path == old.path {
+		t.Fatalf("rotated into the same file %s", cur.path)
+	}
+
+	data, err := storageReadFile(old.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(data); !strings.Contains(s, "before") || !strings.Contains(s, "inflight") || strings.Contains(s, "during") {
+		t.Fatalf("old file content %q", s)
+	}
+
+	data, err = storageReadFile(cur.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(data); !strings.Contains(s, "during") || strings.Contains(s, "before") {
+		t.Fatalf("new file content %q", s)
+	}
+}
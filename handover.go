@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	handoverEnv = "LOGGER_HANDOVER" //交接日志文件的环境变量
+)
+
+var (
+	handoverFrozen int32             //已经交接给新进程，不再切分日志文件
+	handoverOnce   sync.Once         //环境变量只读取一次
+	handoverPaths  map[string]string //旧进程交接过来的日志文件，键为日志目录/基础名字
+)
+
+/******************************************************************************
+ @brief
+ 	平滑升级时将日志文件交接给新进程，返回需要加入新进程环境变量的内容。
+ 	调用后本进程不再切分日志文件，新进程Initialize时继续追加写入同一批文件，
+ 	日志文件都以追加方式打开，交接期间新旧进程可以同时安全写入，之后由新进程负责切分和清理
+ 		例：
+ 			cmd := exec.Command(os.Args[0], os.Args[1:]...)
+ 			cmd.Env = append(os.Environ(), logger.Handover())
+ 			cmd.ExtraFiles = []*os.File{listenerFile}
+ 			cmd.Start()
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	string				返回LOGGER_HANDOVER=...形式的环境变量
+ @history
+ 	2026-10-16_15:05 	agent		创建
+*******************************************************************************/
+func Handover() string {
+	atomic.StoreInt32(&handoverFrozen, 1)
+	Flush()
+
+	files := append(categoryFiles(), tenantFiles()...)
+	if logFile != nil {
+		files = append(files, logFile)
+	}
+
+	paths := map[string]string{}
+	for _, f := range files {
+		f.RLock()
+		if h := f.current(); h != nil && len(h.path) > 0 {
+			paths[handoverKey(f)] = h.path
+		}
+		f.RUnlock()
+	}
+
+	data, _ := json.Marshal(paths)
+	return handoverEnv + "=" + string(data)
+}
+
+/******************************************************************************
+ @brief
+ 	是否已经交接给新进程
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	bool				已经交接时返回true
+ @history
+ 	2026-10-16_15:05 	agent		创建
+*******************************************************************************/
+func handedOver() bool {
+	return atomic.LoadInt32(&handoverFrozen) != 0
+}
+
+/******************************************************************************
+ @brief
+ 	获取旧进程交接过来的日志文件路径，文件已经不存在时返回空字符串
+ @author
+ 	agent
+ @param
+	f					日志文件
+ @return
+ 	string				返回日志文件路径
+ @history
+ 	2026-10-16_15:05 	agent		创建
+*******************************************************************************/
+func handoverPath(f *LOG_FILE) string {
+	handoverOnce.Do(func() {
+		data := os.Getenv(handoverEnv)
+		if len(data) == 0 {
+			return
+		}
+
+		//只在本进程使用，不再传给子进程
+		os.Unsetenv(handoverEnv)
+		if err := json.Unmarshal([]byte(data), &handoverPaths); err != nil {
+			diag("handover %s: %v", handoverEnv, err)
+		}
+	})
+
+	path, ok := handoverPaths[handoverKey(f)]
+	if !ok || !storageExist(path) {
+		return ""
+	}
+
+	return path
+}
+
+/******************************************************************************
+ @brief
+ 	生成日志文件的交接键
+ @author
+ 	agent
+ @param
+	f					日志文件
+ @return
+ 	string				返回日志目录/基础名字
+ @history
+ 	2026-10-16_15:05 	agent		创建
+*******************************************************************************/
+func handoverKey(f *LOG_FILE) string {
+	return filepath.ToSlash(filepath.Join(f.log_dir, f.log_filename))
+}
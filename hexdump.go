@@ -0,0 +1,129 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+var (
+	hexDumpMax    int64 = 4096 //每次最多输出的字节数
+	hexDumpSample int64 = 1    //每sample次调用输出一次
+	hexDumpCount  int64        //调用次数，用于采样
+)
+
+/******************************************************************************
+ @brief
+ 	设置HexDump的输出限制
+ 		例：
+ 			logger.SetHexDump(256, 100)	//每100个包输出一次，每次最多256字节
+ @author
+ 	agent
+ @param
+	maxBytes			每次最多输出的字节数，超过的部分省略，小于等于0表示不限制
+	sample				每sample次调用输出一次，小于等于1表示每次都输出
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:54 	agent		创建
+*******************************************************************************/
+func SetHexDump(maxBytes int, sample int) {
+	if sample < 1 {
+		sample = 1
+	}
+
+	atomic.StoreInt64(&hexDumpMax, int64(maxBytes))
+	atomic.StoreInt64(&hexDumpSample, int64(sample))
+}
+
+/******************************************************************************
+ @brief
+ 	以偏移+十六进制+ASCII的经典格式输出二进制数据，用于协议调试。
+ 	整个输出作为一条日志一次写入，不会和其它日志交错，除第一行外每行以制表符开头
+ 		例：
+ 			logger.HexDump(logger.DEBUG, "recv", packet)
+
+ 		输出：
+ 			DEBUG hexdump recv len=20
+ 				00000000  48 65 6c 6c 6f 2c 20 77  6f 72 6c 64 21 0a 00 01  |Hello, world!...|
+ 				00000010  02 03 04 05                                       |....|
+ @author
+ 	agent
+ @param
+	level				日志级别
+	label				标签，例如协议名称或者收发方向
+	b					二进制数据
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:54 	agent		创建
+*******************************************************************************/
+func HexDump(level LEVEL, label string, b []byte) {
+	defer catchError()
+	if currentLevel() > level {
+		return
+	}
+
+	if sample := atomic.LoadInt64(&hexDumpSample); sample > 1 && (atomic.AddInt64(&hexDumpCount, 1)-1)%sample != 0 {
+		return
+	}
+
+	output(level, hexDump(label, b, int(atomic.LoadInt64(&hexDumpMax))))
+}
+
+/******************************************************************************
+ @brief
+ 	生成十六进制输出内容
+ @author
+ 	agent
+ @param
+	label				标签
+	b					二进制数据
+	max					最多输出的字节数，小于等于0表示不限制
+ @return
+ 	string				返回输出内容，以换行结尾
+ @history
+ 	2026-10-16_14:54 	agent		创建
+*******************************************************************************/
+func hexDump(label string, b []byte, max int) string {
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "hexdump %s len=%d", label, len(b))
+
+	data := b
+	if max > 0 && len(data) > max {
+		data = data[:max]
+		fmt.Fprintf(&sb, " truncated=%d", len(b)-max)
+	}
+
+	for offset := 0; offset < len(data); offset += 16 {
+		line := data[offset:]
+		if len(line) > 16 {
+			line = line[:16]
+		}
+
+		fmt.Fprintf(&sb, "\n\t%08x ", offset)
+		for i := 0; i < 16; i++ {
+			if i == 8 {
+				sb.WriteByte(' ')
+			}
+			if i < len(line) {
+				fmt.Fprintf(&sb, " %02x", line[i])
+			} else {
+				sb.WriteString("   ")
+			}
+		}
+
+		sb.WriteString("  |")
+		for _, c := range line {
+			if c < 0x20 || c > 0x7e {
+				c = '.'
+			}
+			sb.WriteByte(c)
+		}
+		sb.WriteByte('|')
+	}
+	sb.WriteByte('\n')
+
+	return sb.String()
+}
@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+/******************************************************************************
+ @brief
+ 	日志级别回调函数，name为主日志时为空字符串，分类日志时为分类名称
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:07 	agent		创建
+*******************************************************************************/
+type LEVEL_HOOK func(name string, ll LEVEL)
+
+/******************************************************************************
+ @brief
+ 	日志级别回调
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:07 	agent		创建
+*******************************************************************************/
+type levelHook struct {
+	min   LEVEL      //最低触发级别
+	fn    LEVEL_HOOK //回调函数
+	count int64      //触发次数
+}
+
+var (
+	hookLock  sync.Mutex   //日志级别回调修改线程锁
+	hookValue atomic.Value //日志级别回调map[string]*levelHook，修改时整体替换
+)
+
+/******************************************************************************
+ @brief
+ 	设置日志级别回调，name对应的日志每输出一条不低于min级别的日志就调用一次fn并计数，
+ 	可以接入SLO工具统计错误预算的消耗。回调在写日志的协程中同步执行，不能阻塞，也不能再写日志
+ 		例：
+ 			logger.SetLevelHook("*", logger.ERROR, func(name string, ll logger.LEVEL) {
+ 				errorBudgetBurn.WithLabelValues(name, ll.String()).Inc()
+ 			})
+ @author
+ 	agent
+ @param
+	name				日志名称，空字符串为主日志，分类名称为分类日志，*为所有日志
+	min					最低触发级别，例如WARN
+	fn					回调函数，nil表示只计数
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:07 	agent		创建
+*******************************************************************************/
+func SetLevelHook(name string, min LEVEL, fn LEVEL_HOOK) {
+	setLevelHook(name, &levelHook{min: min, fn: fn})
+}
+
+/******************************************************************************
+ @brief
+ 	删除日志级别回调
+ @author
+ 	agent
+ @param
+	name				日志名称
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:07 	agent		创建
+*******************************************************************************/
+func RemoveLevelHook(name string) {
+	setLevelHook(name, nil)
+}
+
+/******************************************************************************
+ @brief
+ 	获取日志级别回调的触发次数
+ @author
+ 	agent
+ @param
+	name				日志名称
+ @return
+ 	int64				返回触发次数，没有设置回调时返回0
+ @history
+ 	2026-10-16_15:07 	agent		创建
+*******************************************************************************/
+func LevelHookCount(name string) int64 {
+	hooks, _ := hookValue.Load().(map[string]*levelHook)
+	if h, ok := hooks[name]; ok {
+		return atomic.LoadInt64(&h.count)
+	}
+
+	return 0
+}
+
+/******************************************************************************
+ @brief
+ 	复制回调列表并修改
+ @author
+ 	agent
+ @param
+	name				日志名称
+	h					日志级别回调，nil表示删除
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:07 	agent		创建
+*******************************************************************************/
+func setLevelHook(name string, h *levelHook) {
+	hookLock.Lock()
+	defer hookLock.Unlock()
+
+	old, _ := hookValue.Load().(map[string]*levelHook)
+	hooks := make(map[string]*levelHook, len(old)+1)
+	for k, v := range old {
+		hooks[k] = v
+	}
+
+	if h == nil {
+		delete(hooks, name)
+	} else {
+		hooks[name] = h
+	}
+
+	hookValue.Store(hooks)
+}
+
+/******************************************************************************
+ @brief
+ 	触发日志级别回调
+ @author
+ 	agent
+ @param
+	f					日志文件
+	ll					日志等级
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:07 	agent		创建
+ 	2026-10-16_15:14 	agent		使用logName获取日志名称
+*******************************************************************************/
+func levelHooks(f *LOG_FILE, ll LEVEL) {
+	hooks, _ := hookValue.Load().(map[string]*levelHook)
+	if len(hooks) == 0 {
+		return
+	}
+
+	name := logName(f)
+	for _, key := range [2]string{name, "*"} {
+		h, ok := hooks[key]
+		if !ok || ll < h.min {
+			continue
+		}
+
+		atomic.AddInt64(&h.count, 1)
+		if h.fn != nil {
+			h.fn(name, ll)
+		}
+	}
+}
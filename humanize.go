@@ -0,0 +1,271 @@
+package logger
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	humanMark = '\x1f' //易读字段的边界标记，输出时根据设置保留或删除标记之间的内容
+)
+
+var (
+	logFileHumanize    bool = false //日志文件是否输出易读字段
+	logConsoleHumanize bool = true  //终端控制台是否输出易读字段
+)
+
+/******************************************************************************
+ @brief
+ 	生成时间长度字段，总是输出毫秒数，开启易读输出的目标还会输出易读的时间长度
+ 		例：
+ 			logger.Infof("query done %s", logger.Duration("dur", cost))
+
+ 		日志文件：INFO query done dur_ms=1532
+ 		终端控制台：INFO query done dur_ms=1532 dur="1.53s"
+ @author
+ 	agent
+ @param
+	key					字段名称
+	d					时间长度
+ @return
+ 	string				返回字段文本
+ @history
+ 	2026-10-16_14:50 	agent		创建
+*******************************************************************************/
+func Duration(key string, d time.Duration) string {
+	raw := configField(key+"_ms", strconv.FormatInt(d.Milliseconds(), 10))
+	return raw + humanField(key, humanDuration(d))
+}
+
+/******************************************************************************
+ @brief
+ 	生成数据大小字段，总是输出字节数，开启易读输出的目标还会输出易读的大小
+ 		例：
+ 			logger.Infof("upload done %s", logger.Size("size", n))
+
+ 		日志文件：INFO upload done size=1048576
+ 		终端控制台：INFO upload done size=1048576 size_h="1.0MiB"
+ @author
+ 	agent
+ @param
+	key					字段名称
+	n					字节数
+ @return
+ 	string				返回字段文本
+ @history
+ 	2026-10-16_14:50 	agent		创建
+*******************************************************************************/
+func Size(key string, n int64) string {
+	raw := configField(key, strconv.FormatInt(n, 10))
+	return raw + humanField(key+"_h", humanSize(n))
+}
+
+/******************************************************************************
+ @brief
+ 	设置日志文件是否输出Duration、Size生成的易读字段，默认不输出
+ @author
+ 	agent
+ @param
+	isHumanize			是否输出
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:50 	agent		创建
+*******************************************************************************/
+func SetFileHumanize(isHumanize bool) {
+	logFileHumanize = isHumanize
+}
+
+/******************************************************************************
+ @brief
+ 	设置终端控制台是否输出Duration、Size生成的易读字段，默认输出
+ @author
+ 	agent
+ @param
+	isHumanize			是否输出
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:50 	agent		创建
+*******************************************************************************/
+func SetConsoleHumanize(isHumanize bool) {
+	logConsoleHumanize = isHumanize
+}
+
+/******************************************************************************
+ @brief
+ 	设置输出目标是否输出Duration、Size生成的易读字段，默认不输出
+ @author
+ 	agent
+ @param
+	name				输出目标名称
+	isHumanize			是否输出
+ @return
+ 	bool				输出目标不存在时返回false
+ @history
+ 	2026-10-16_14:50 	agent		创建
+*******************************************************************************/
+func SetSinkHumanize(name string, isHumanize bool) bool {
+	sink := findSink(name)
+	if sink == nil {
+		return false
+	}
+
+	sink.Lock()
+	defer sink.Unlock()
+
+	sink.humanize = isHumanize
+	return true
+}
+
+/******************************************************************************
+ @brief
+ 	生成带边界标记的易读字段
+ @author
+ 	agent
+ @param
+	key					字段名称
+	value				易读的值
+ @return
+ 	string				返回带标记的字段文本
+ @history
+ 	2026-10-16_14:50 	agent		创建
+*******************************************************************************/
+func humanField(key, value string) string {
+	return string(humanMark) + " " + key + "=" + strconv.Quote(value) + string(humanMark)
+}
+
+/******************************************************************************
+ @brief
+ 	根据设置保留或删除日志内容中的易读字段，并去掉边界标记
+ @author
+ 	agent
+ @param
+	s					日志内容
+	isHumanize			是否保留易读字段
+ @return
+ 	string				返回处理后的日志内容
+ @history
+ 	2026-10-16_14:50 	agent		创建
+*******************************************************************************/
+func humanText(s string, isHumanize bool) string {
+	if strings.IndexByte(s, humanMark) < 0 {
+		return s
+	}
+
+	if isHumanize {
+		return strings.Replace(s, string(humanMark), "", -1)
+	}
+
+	var b strings.Builder
+	for {
+		begin := strings.IndexByte(s, humanMark)
+		if begin < 0 {
+			break
+		}
+
+		end := strings.IndexByte(s[begin+1:], humanMark)
+		if end < 0 {
+			s = s[:begin] + s[begin+1:]
+			continue
+		}
+
+		b.WriteString(s[:begin])
+		s = s[begin+1+end+1:]
+	}
+	b.WriteString(s)
+
+	return b.String()
+}
+
+/******************************************************************************
+ @brief
+ 	生成易读的时间长度，保留三位有效数字，例如1.53s、250ms，超过一分钟精确到秒
+ @author
+ 	agent
+ @param
+	d					时间长度
+ @return
+ 	string				返回易读的时间长度
+ @history
+ 	2026-10-16_14:50 	agent		创建
+*******************************************************************************/
+func humanDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+
+	if d >= time.Minute {
+		return sign + d.Round(time.Second).String()
+	}
+
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Second, "s"},
+		{time.Millisecond, "ms"},
+		{time.Microsecond, "µs"},
+	}
+
+	for _, u := range units {
+		if d >= u.unit {
+			return sign + humanFloat(float64(d)/float64(u.unit)) + u.name
+		}
+	}
+
+	return sign + strconv.FormatInt(int64(d), 10) + "ns"
+}
+
+/******************************************************************************
+ @brief
+ 	生成易读的数据大小，使用1024进制单位，例如1.0MiB
+ @author
+ 	agent
+ @param
+	n					字节数
+ @return
+ 	string				返回易读的数据大小
+ @history
+ 	2026-10-16_14:50 	agent		创建
+*******************************************************************************/
+func humanSize(n int64) string {
+	if n < 1024 && n > -1024 {
+		return strconv.FormatInt(n, 10) + "B"
+	}
+
+	v := float64(n)
+	for _, unit := range []string{"KiB", "MiB", "GiB", "TiB", "PiB"} {
+		v /= 1024
+		if v < 1024 && v > -1024 {
+			return strconv.FormatFloat(v, 'f', 1, 64) + unit
+		}
+	}
+
+	return strconv.FormatFloat(v/1024, 'f', 1, 64) + "EiB"
+}
+
+/******************************************************************************
+ @brief
+ 	保留三位有效数字格式化小数
+ @author
+ 	agent
+ @param
+	v					数值，不小于1
+ @return
+ 	string				返回格式化后的数值
+ @history
+ 	2026-10-16_14:50 	agent		创建
+*******************************************************************************/
+func humanFloat(v float64) string {
+	switch {
+	case v < 10:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	case v < 100:
+		return strconv.FormatFloat(v, 'f', 1, 64)
+	default:
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+}
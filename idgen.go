@@ -0,0 +1,277 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	ID生成器接口，用于请求ID和日志ID，生成的ID按时间排序，下游存储可以按ID范围查询
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:13 	agent		创建
+*******************************************************************************/
+type ID_GENERATOR interface {
+	NewID() string //生成一个新的ID，需要支持多个协程同时调用
+}
+
+/******************************************************************************
+ @brief
+ 	UUIDv7生成器，同一毫秒内使用12位计数器保证顺序
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:13 	agent		创建
+*******************************************************************************/
+type uuidV7 struct {
+	sync.Mutex
+	ms  int64  //上一个ID的毫秒时间戳
+	seq uint16 //同一毫秒内的计数
+}
+
+/******************************************************************************
+ @brief
+ 	雪花ID生成器，41位毫秒时间戳、10位节点编号、12位序号，输出为十进制字符串
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:13 	agent		创建
+*******************************************************************************/
+type snowflake struct {
+	sync.Mutex
+	node int64 //节点编号
+	ms   int64 //上一个ID的毫秒时间戳，从snowflakeEpoch开始
+	seq  int64 //同一毫秒内的序号
+}
+
+/******************************************************************************
+ @brief
+ 	KSUID生成器，4字节秒级时间戳加16字节随机数，输出为27位base62字符串
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:13 	agent		创建
+*******************************************************************************/
+type ksuid struct{}
+
+const (
+	snowflakeEpoch = 1577836800000 //雪花ID的时间起点，2020-01-01 00:00:00 UTC的毫秒时间戳
+	ksuidEpoch     = 1400000000    //KSUID的时间起点，与segmentio/ksuid一致
+	base62Digits   = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+var (
+	idGenerator atomic.Value //当前的ID生成器idGeneratorBox，没有设置时使用UUIDv7
+	idDefault   = NewUUIDv7()
+)
+
+/******************************************************************************
+ @brief
+ 	ID生成器的包装，atomic.Value要求每次存入相同的类型
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:13 	agent		创建
+*******************************************************************************/
+type idGeneratorBox struct {
+	g ID_GENERATOR
+}
+
+/******************************************************************************
+ @brief
+ 	创建UUIDv7生成器，输出为标准的36位UUID字符串
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	ID_GENERATOR		返回ID生成器
+ @history
+ 	2026-10-16_15:13 	agent		创建
+*******************************************************************************/
+func NewUUIDv7() ID_GENERATOR {
+	return &uuidV7{}
+}
+
+/******************************************************************************
+ @brief
+ 	创建雪花ID生成器，同一时刻的多个进程需要使用不同的节点编号
+ @author
+ 	agent
+ @param
+	node				节点编号，0到1023
+ @return
+ 	ID_GENERATOR		返回ID生成器
+ 	error				节点编号超出范围时返回错误信息
+ @history
+ 	2026-10-16_15:13 	agent		创建
+*******************************************************************************/
+func NewSnowflake(node int) (ID_GENERATOR, error) {
+	if node < 0 || node > 1023 {
+		return nil, fmt.Errorf("logger: snowflake node %d out of range 0-1023", node)
+	}
+
+	return &snowflake{node: int64(node)}, nil
+}
+
+/******************************************************************************
+ @brief
+ 	创建KSUID生成器
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	ID_GENERATOR		返回ID生成器
+ @history
+ 	2026-10-16_15:13 	agent		创建
+*******************************************************************************/
+func NewKSUID() ID_GENERATOR {
+	return ksuid{}
+}
+
+/******************************************************************************
+ @brief
+ 	设置请求ID和日志ID使用的ID生成器
+ 		例：
+ 			gen, _ := logger.NewSnowflake(serverID)
+ 			logger.SetIDGenerator(gen)
+ @author
+ 	agent
+ @param
+	g					ID生成器，nil表示恢复为UUIDv7
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:13 	agent		创建
+*******************************************************************************/
+func SetIDGenerator(g ID_GENERATOR) {
+	idGenerator.Store(idGeneratorBox{g: g})
+}
+
+/******************************************************************************
+ @brief
+ 	使用当前的ID生成器生成一个ID
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	string				返回ID
+ @history
+ 	2026-10-16_15:13 	agent		创建
+*******************************************************************************/
+func NewID() string {
+	if box, _ := idGenerator.Load().(idGeneratorBox); box.g != nil {
+		return box.g.NewID()
+	}
+
+	return idDefault.NewID()
+}
+
+/******************************************************************************
+ @brief
+ 	生成UUIDv7
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	string				返回UUID字符串
+ @history
+ 	2026-10-16_15:13 	agent		创建
+*******************************************************************************/
+func (u *uuidV7) NewID() string {
+	var b [16]byte
+	rand.Read(b[:])
+
+	u.Lock()
+	ms := time.Now().UnixNano() / int64(time.Millisecond)
+	if ms > u.ms {
+		u.ms, u.seq = ms, 0
+	} else {
+		//时钟回拨或同一毫秒，沿用上一个时间戳并增加计数，计数用完时借用下一毫秒
+		u.seq++
+		if u.seq > 0x0fff {
+			u.ms, u.seq = u.ms+1, 0
+		}
+	}
+	ms, seq := u.ms, u.seq
+	u.Unlock()
+
+	b[0], b[1], b[2] = byte(ms>>40), byte(ms>>32), byte(ms>>24)
+	b[3], b[4], b[5] = byte(ms>>16), byte(ms>>8), byte(ms)
+	b[6] = 0x70 | byte(seq>>8)
+	b[7] = byte(seq)
+	b[8] = 0x80 | b[8]&0x3f
+
+	s := hex.EncodeToString(b[:])
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:32]
+}
+
+/******************************************************************************
+ @brief
+ 	生成雪花ID
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	string				返回十进制ID
+ @history
+ 	2026-10-16_15:13 	agent		创建
+*******************************************************************************/
+func (s *snowflake) NewID() string {
+	s.Lock()
+	ms := time.Now().UnixNano()/int64(time.Millisecond) - snowflakeEpoch
+	if ms > s.ms {
+		s.ms, s.seq = ms, 0
+	} else {
+		s.seq++
+		if s.seq > 0x0fff {
+			s.ms, s.seq = s.ms+1, 0
+		}
+	}
+	id := s.ms<<22 | s.node<<12 | s.seq
+	s.Unlock()
+
+	return strconv.FormatInt(id, 10)
+}
+
+/******************************************************************************
+ @brief
+ 	生成KSUID
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	string				返回27位base62字符串
+ @history
+ 	2026-10-16_15:13 	agent		创建
+*******************************************************************************/
+func (ksuid) NewID() string {
+	var b [20]byte
+	binary.BigEndian.PutUint32(b[:4], uint32(time.Now().Unix()-ksuidEpoch))
+	rand.Read(b[4:])
+
+	//base62编码，不足27位时前面补0
+	n := new(big.Int).SetBytes(b[:])
+	base, mod := big.NewInt(62), new(big.Int)
+	out := make([]byte, 27)
+	for i := len(out) - 1; i >= 0; i-- {
+		n.DivMod(n, base, mod)
+		out[i] = base62Digits[mod.Int64()]
+	}
+
+	return string(out)
+}
@@ -0,0 +1,260 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+type FORMAT int //终端控制台输出格式
+
+const (
+	FORMAT_TEXT FORMAT = iota //文本格式，默认格式
+	FORMAT_JSON               //每行一个JSON对象
+)
+
+type JSON_STYLE int //JSON格式的字段风格
+
+const (
+	JSON_DEFAULT JSON_STYLE = iota //本模块的格式，带有格式版本
+	JSON_DATADOG                   //Datadog的标准属性，可以与APM关联
+	JSON_GCP                       //Google Cloud Logging从标准输出采集的结构化格式
+)
+
+/******************************************************************************
+ @brief
+ 	结构化输出（JSON日志、事件日志）的格式版本，每条记录的第一个字段为"v":版本号。
+ 	兼容策略：
+ 		1.新增字段不改变版本，解析方需要忽略不认识的字段
+ 		2.删除、重命名字段或者修改字段类型、含义时版本加1
+ 		3.同一个版本内字段的顺序不作保证
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:37 	agent		创建
+*******************************************************************************/
+const (
+	FORMAT_VERSION = 1 //结构化输出格式版本
+)
+
+var (
+	logConsoleFormat FORMAT     = FORMAT_TEXT //终端控制台输出格式
+	logFileOff       bool                     //是否关闭日志文件输出
+	logFatalExit     bool                     //输出FATAL日志后是否退出进程
+	jsonLock         sync.Mutex               //JSON输出线程锁
+	jsonStyle        JSON_STYLE               //JSON格式的字段风格
+)
+
+/******************************************************************************
+ @brief
+ 	使用标准输出JSON模式，适用于Kubernetes等由平台收集标准输出的环境：
+ 		1.关闭日志文件输出，Initialize不再创建日志文件
+ 		2.终端控制台每行输出一个JSON对象到标准输出
+ 		3.输出FATAL日志后以非0退出码退出进程
+ 		例：
+ 			logger.UseStdoutJSON()
+ 			logger.Info("server started")
+
+ 		输出：{"v":1,"time":"2026-10-16T16:50:00.000000+08:00","level":"INFO","caller":"main.go:12","msg":"server started"}
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:17 	agent		创建
+ 	2026-10-16_14:43 	agent		使用文件句柄
+*******************************************************************************/
+func UseStdoutJSON() {
+
+	//关闭日志文件
+	logFileOff = true
+	if f := logFile; f != nil {
+		f.Lock()
+		old := f.swap(&fileHandle{})
+		f.Unlock()
+
+		if old != nil {
+			old.close()
+		}
+		logFile = nil
+	}
+
+	SetConsole(true)
+	SetConsoleFormat(FORMAT_JSON)
+	SetFatalExit(true)
+}
+
+/******************************************************************************
+ @brief
+ 	设置终端控制台输出格式，JSON格式输出到标准输出，文本格式输出到标准库log的输出
+ @author
+ 	agent
+ @param
+	format				输出格式
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:17 	agent		创建
+*******************************************************************************/
+func SetConsoleFormat(format FORMAT) {
+	logConsoleFormat = format
+}
+
+/******************************************************************************
+ @brief
+ 	设置JSON格式的字段风格，各平台的日志服务可以直接识别级别、时间和调用链字段
+ 		例：
+ 			logger.UseStdoutJSON()
+ 			logger.SetJSONStyle(logger.JSON_DATADOG)
+ @author
+ 	agent
+ @param
+	style				字段风格
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:15 	agent		创建
+ 	2026-10-16_15:15 	agent		支持Google Cloud Logging字段风格
+*******************************************************************************/
+func SetJSONStyle(style JSON_STYLE) {
+	switch style {
+	case JSON_DATADOG:
+		datadogLoadEnv()
+	case JSON_GCP:
+		gcpLoadEnv()
+	}
+	jsonStyle = style
+}
+
+/******************************************************************************
+ @brief
+ 	设置输出FATAL日志后是否退出进程，退出前会写入所有输出目标的缓冲区，退出码为1
+ @author
+ 	agent
+ @param
+	isExit				是否退出进程
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:17 	agent		创建
+*******************************************************************************/
+func SetFatalExit(isExit bool) {
+	logFatalExit = isExit
+}
+
+/******************************************************************************
+ @brief
+ 	以JSON格式输出日志到标准输出
+ @author
+ 	agent
+ @param
+	t					日志时间
+	ll					日志等级
+	file				调用者文件，为空表示不输出调用者信息
+	line				调用者行号
+	fn					调用者函数名，为空表示不输出
+	msg					日志内容
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:17 	agent		创建
+ 	2026-10-16_14:22 	agent		磁盘空间不足时始终显示
+*******************************************************************************/
+func consoleJSON(t time.Time, ll LEVEL, file string, line int, fn string, msg string) {
+	if !logConsole && !diskDegraded() {
+		return
+	}
+
+	buf := encodeJSON(nil, t, ll, file, line, fn, msg)
+
+	jsonLock.Lock()
+	defer jsonLock.Unlock()
+	os.Stdout.Write(buf)
+}
+
+/******************************************************************************
+ @brief
+ 	将日志编码为一行JSON
+ @author
+ 	agent
+ @param
+	buf					输出缓冲
+	t					日志时间
+	ll					日志等级
+	file				调用者文件，为空表示不输出调用者信息
+	line				调用者行号
+	fn					调用者函数名，为空表示不输出
+	msg					日志内容
+ @return
+ 	[]byte				返回追加JSON后的缓冲，以换行结尾
+ @history
+ 	2026-10-16_14:17 	agent		创建
+ 	2026-10-16_14:37 	agent		输出格式版本
+ 	2026-10-16_15:15 	agent		支持Datadog字段风格
+ 	2026-10-16_15:15 	agent		支持Google Cloud Logging字段风格
+*******************************************************************************/
+func encodeJSON(buf []byte, t time.Time, ll LEVEL, file string, line int, fn string, msg string) []byte {
+
+	switch jsonStyle {
+	case JSON_DATADOG:
+		return encodeDatadog(buf, t, ll, file, line, fn, msg)
+	case JSON_GCP:
+		return encodeGCP(buf, t, ll, file, line, fn, msg)
+	}
+
+	buf = append(buf, '{')
+	buf = appendJSON(buf, "v", FORMAT_VERSION)
+	buf = append(buf, ',')
+	buf = appendJSON(buf, "time", t.Format("2006-01-02T15:04:05.000000Z07:00"))
+	buf = append(buf, ',')
+	buf = appendJSON(buf, "level", ll.String())
+	if len(file) > 0 {
+		buf = append(buf, ',')
+		buf = appendJSON(buf, "caller", fmt.Sprintf("%s:%d", shortFile(file), line))
+	}
+	if len(fn) > 0 {
+		buf = append(buf, ',')
+		buf = appendJSON(buf, "func", fn)
+	}
+	buf = append(buf, ',')
+	buf = appendJSON(buf, "msg", strings.TrimRight(msg, "\n"))
+	for _, field := range fieldsList() {
+		buf = append(buf, ',')
+		buf = appendJSON(buf, field[0], field[1])
+	}
+	buf = append(buf, '}', '\n')
+
+	return buf
+}
+
+/******************************************************************************
+ @brief
+ 	追加一个JSON键值对
+ @author
+ 	agent
+ @param
+	buf					输出缓冲
+	key					键
+	value				值，会被编码为JSON
+ @return
+ 	[]byte				返回追加后的缓冲
+ @history
+ 	2026-10-16_14:17 	agent		创建
+*******************************************************************************/
+func appendJSON(buf []byte, key string, value interface{}) []byte {
+
+	k, _ := json.Marshal(key)
+	v, err := json.Marshal(value)
+	if err != nil {
+		v, _ = json.Marshal(fmt.Sprint(value))
+	}
+
+	buf = append(buf, k...)
+	buf = append(buf, ':')
+	return append(buf, v...)
+}
@@ -0,0 +1,118 @@
+package logger
+
+/******************************************************************************
+ @brief
+ 	创建带路由标签的主日志，路由标签与With的标签不同，不会写入日志内容，
+ 	只用于选择输出目标，例如Loki的标签、Kafka的topic，避免高基数的字段变成标签
+ 		例：
+ 			logger.AddSink("kafka_battle", battleWriter, logger.BUFFER_NONE, 0, 0)
+ 			logger.SetSinkLabels("kafka_battle", map[string]string{"topic": "battle"})
+
+ 			log := logger.WithLabels(map[string]string{"topic": "battle"})
+ 			log.Infof("room %d start", roomID)	//写入主日志和kafka_battle
+ @author
+ 	agent
+ @param
+	labels				路由标签
+ @return
+ 	*TAG_LOG			返回带路由标签的日志
+ @history
+ 	2026-10-16_15:06 	agent		创建
+*******************************************************************************/
+func WithLabels(labels map[string]string) *TAG_LOG {
+	return (&TAG_LOG{}).WithLabels(labels)
+}
+
+/******************************************************************************
+ @brief
+ 	创建带路由标签的分类日志
+ @author
+ 	agent
+ @param
+	labels				路由标签
+ @return
+ 	*TAG_LOG			返回带路由标签的日志
+ @history
+ 	2026-10-16_15:06 	agent		创建
+*******************************************************************************/
+func (c *CATEGORY) WithLabels(labels map[string]string) *TAG_LOG {
+	return (&TAG_LOG{category: c}).WithLabels(labels)
+}
+
+/******************************************************************************
+ @brief
+ 	在已有路由标签的基础上增加路由标签，同名的标签会被替换，返回新的日志，原来的日志不受影响
+ @author
+ 	agent
+ @param
+	labels				路由标签
+ @return
+ 	*TAG_LOG			返回带路由标签的日志
+ @history
+ 	2026-10-16_15:06 	agent		创建
+*******************************************************************************/
+func (t *TAG_LOG) WithLabels(labels map[string]string) *TAG_LOG {
+	merged := make(map[string]string, len(t.labels)+len(labels))
+	for k, v := range t.labels {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+
+	return &TAG_LOG{category: t.category, tags: t.tags, text: t.text, labels: merged}
+}
+
+/******************************************************************************
+ @brief
+ 	设置输出目标只接收带有指定路由标签的日志，标签的值都相同才接收
+ @author
+ 	agent
+ @param
+	name				输出目标名称
+	labels				路由标签，为空时接收所有日志
+ @return
+ 	bool				输出目标不存在时返回false
+ @history
+ 	2026-10-16_15:06 	agent		创建
+*******************************************************************************/
+func SetSinkLabels(name string, labels map[string]string) bool {
+	sink := findSink(name)
+	if sink == nil {
+		return false
+	}
+
+	copied := make(map[string]string, len(labels))
+	for k, v := range labels {
+		copied[k] = v
+	}
+
+	sink.Lock()
+	defer sink.Unlock()
+
+	sink.labels = copied
+	return true
+}
+
+/******************************************************************************
+ @brief
+ 	判断日志的路由标签是否满足输出目标的要求
+ @author
+ 	agent
+ @param
+	want				输出目标要求的路由标签
+	labels				日志的路由标签
+ @return
+ 	bool				满足时返回true
+ @history
+ 	2026-10-16_15:06 	agent		创建
+*******************************************************************************/
+func labelsMatch(want, labels map[string]string) bool {
+	for k, v := range want {
+		if value, ok := labels[k]; !ok || value != v {
+			return false
+		}
+	}
+
+	return true
+}
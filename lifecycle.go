@@ -0,0 +1,207 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	日志内部的生命周期事件类型
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:46 	agent		创建
+ 	2026-10-16_15:01 	agent		增加超过写入配额事件
+*******************************************************************************/
+type LIFECYCLE int
+
+const (
+	LIFECYCLE_FILE_OPENED      LIFECYCLE = iota //日志文件已创建
+	LIFECYCLE_FILE_ROTATED                      //日志文件已切分，File为切分前的文件
+	LIFECYCLE_FILE_PRUNED                       //日志文件被保留策略删除
+	LIFECYCLE_SINK_RECONNECTED                  //输出目标写入失败后恢复
+	LIFECYCLE_QUOTA_EXCEEDED                    //租户或分类超过写入配额，之后的日志被丢弃
+)
+
+var lifecycleNames = []string{
+	"FILE_OPENED",
+	"FILE_ROTATED",
+	"FILE_PRUNED",
+	"SINK_RECONNECTED",
+	"QUOTA_EXCEEDED",
+}
+
+/******************************************************************************
+ @brief
+ 	日志内部的生命周期事件
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:46 	agent		创建
+ 	2026-10-16_15:01 	agent		增加超过写入配额事件
+*******************************************************************************/
+type LIFECYCLE_EVENT struct {
+	Kind  LIFECYCLE //事件类型
+	Time  time.Time //事件发生时间
+	File  string    //相关的日志文件路径，输出目标事件为空
+	Sink  string    //相关的输出目标名称，文件事件为空
+	Quota string    //超过配额的租户或分类，例如tenant:world2、category:battle
+}
+
+const (
+	lifecycleQueue = 1024 //等待通知的事件数量上限，超过时丢弃新的事件
+)
+
+var (
+	lifecycleLock   sync.Mutex                           //事件订阅线程锁
+	lifecycleSubs   = map[string]func(LIFECYCLE_EVENT){} //事件订阅者
+	lifecycleEvents chan LIFECYCLE_EVENT                 //等待通知的事件
+	lifecycleOnce   sync.Once                            //事件通知只启动一次
+)
+
+/******************************************************************************
+ @brief
+ 	获取事件类型名称
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	string				返回事件类型名称
+ @history
+ 	2026-10-16_14:46 	agent		创建
+*******************************************************************************/
+func (k LIFECYCLE) String() string {
+	if k < 0 || int(k) >= len(lifecycleNames) {
+		return "UNKNOWN"
+	}
+
+	return lifecycleNames[k]
+}
+
+/******************************************************************************
+ @brief
+ 	订阅日志内部的生命周期事件，可以接入程序自己的健康检查和告警。
+ 	事件在独立的协程中按顺序通知，回调中可以正常记录日志，
+ 	但是回调不应该长时间阻塞，否则后续的事件会被丢弃
+ 		例：
+ 			logger.Subscribe("health", func(ev logger.LIFECYCLE_EVENT) {
+ 				if ev.Kind == logger.LIFECYCLE_SINK_RECONNECTED {
+ 					health.Clear("log_sink_" + ev.Sink)
+ 				}
+ 			})
+ @author
+ 	agent
+ @param
+	name				订阅者名称，重复时替换之前的订阅
+	fn					事件回调
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:46 	agent		创建
+*******************************************************************************/
+func Subscribe(name string, fn func(LIFECYCLE_EVENT)) {
+	lifecycleLock.Lock()
+	defer lifecycleLock.Unlock()
+
+	lifecycleSubs[name] = fn
+
+	//启动事件通知模块
+	lifecycleOnce.Do(func() {
+		lifecycleEvents = make(chan LIFECYCLE_EVENT, lifecycleQueue)
+		go lifecycleMonitor()
+	})
+}
+
+/******************************************************************************
+ @brief
+ 	取消订阅生命周期事件
+ @author
+ 	agent
+ @param
+	name				订阅者名称
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:46 	agent		创建
+*******************************************************************************/
+func Unsubscribe(name string) {
+	lifecycleLock.Lock()
+	defer lifecycleLock.Unlock()
+
+	delete(lifecycleSubs, name)
+}
+
+/******************************************************************************
+ @brief
+ 	发布生命周期事件，不会阻塞调用者，没有订阅者时直接返回
+ @author
+ 	agent
+ @param
+	ev					生命周期事件
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:46 	agent		创建
+*******************************************************************************/
+func lifecycle(ev LIFECYCLE_EVENT) {
+	lifecycleLock.Lock()
+	events := lifecycleEvents
+	lifecycleLock.Unlock()
+
+	if events == nil {
+		return
+	}
+
+	ev.Time = time.Now()
+	select {
+	case events <- ev:
+	default:
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	事件通知函数，按顺序将事件通知给所有订阅者
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:46 	agent		创建
+*******************************************************************************/
+func lifecycleMonitor() {
+	for ev := range lifecycleEvents {
+		lifecycleLock.Lock()
+		subs := make([]func(LIFECYCLE_EVENT), 0, len(lifecycleSubs))
+		for _, fn := range lifecycleSubs {
+			subs = append(subs, fn)
+		}
+		lifecycleLock.Unlock()
+
+		for _, fn := range subs {
+			lifecycleNotify(fn, ev)
+		}
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	通知一个订阅者，回调中的异常不影响其它订阅者
+ @author
+ 	agent
+ @param
+	fn					事件回调
+	ev					生命周期事件
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:46 	agent		创建
+*******************************************************************************/
+func lifecycleNotify(fn func(LIFECYCLE_EVENT), ev LIFECYCLE_EVENT) {
+	defer catchError()
+	fn(ev)
+}
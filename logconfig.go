@@ -0,0 +1,301 @@
+package logger
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/******************************************************************************
+ @brief
+ 	以key=value的形式输出配置结构体，一般用于启动时打印实际生效的配置。
+ 	嵌套的结构体会展开为a.b=value，切片、数组和映射中的结构体展开为a[0].b=value、a[key].b=value，
+ 	带有log:"secret"标签的字段会被屏蔽，带有log:"-"标签的字段不输出，
+ 	time.Time等实现了fmt.Stringer且不包含log:"secret"字段的值直接输出String的结果，
+ 	url.URL中的密码会被屏蔽
+ 		例：
+ 			type DBConfig struct {
+ 				Host     string
+ 				Password string `log:"secret"`
+ 			}
+ 			logger.LogConfig(cfg)
+
+ 		输出：INFO config Host=127.0.0.1 Password=******
+ @author
+ 	agent
+ @param
+	v					配置结构体或结构体指针
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:13 	agent		创建
+ 	2026-10-16_15:36 	agent		展开切片、数组和映射中的结构体，支持fmt.Stringer
+ 	2026-10-16_16:30 	agent		包含敏感字段的fmt.Stringer逐个字段展开，屏蔽url.URL中的密码
+*******************************************************************************/
+func LogConfig(v interface{}) {
+	defer catchError()
+	if currentLevel() <= INFO {
+		fields := configFields("", reflect.ValueOf(v), nil)
+		output(INFO, fmt.Sprintf("config %s", strings.Join(fields, " ")))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	将配置展开为key=value列表
+ @author
+ 	agent
+ @param
+	prefix				字段前缀
+	v					字段值
+	fields				已经展开的字段列表
+ @return
+ 	[]string			返回展开后的字段列表
+ @history
+ 	2026-10-16_14:13 	agent		创建
+ 	2026-10-16_15:36 	agent		展开改由configWalk完成，增加循环引用检查
+*******************************************************************************/
+func configFields(prefix string, v reflect.Value, fields []string) []string {
+	return configWalk(prefix, v, fields, map[uintptr]bool{})
+}
+
+/******************************************************************************
+ @brief
+ 	递归展开配置，切片、数组和映射的元素可能包含需要屏蔽的字段时逐个展开
+ @author
+ 	agent
+ @param
+	prefix				字段前缀
+	v					字段值
+	fields				已经展开的字段列表
+	visiting			当前路径上的指针，再次遇到时为循环引用
+ @return
+ 	[]string			返回展开后的字段列表
+ @history
+ 	2026-10-16_15:36 	agent		创建，从configFields拆分
+*******************************************************************************/
+func configWalk(prefix string, v reflect.Value, fields []string, visiting map[uintptr]bool) []string {
+
+	if !v.IsValid() {
+		return append(fields, configField(prefix, "<nil>"))
+	}
+
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return append(fields, configField(prefix, "<nil>"))
+		}
+
+		if s, ok := configStringer(v); ok {
+			return append(fields, configField(prefix, s))
+		}
+
+		if v.Kind() == reflect.Ptr {
+			p := v.Pointer()
+			if visiting[p] {
+				return append(fields, configField(prefix, "<cycle>"))
+			}
+			visiting[p] = true
+			defer delete(visiting, p)
+		}
+		v = v.Elem()
+	}
+
+	if s, ok := configStringer(v); ok {
+		return append(fields, configField(prefix, s))
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if !configNested(v.Type().Elem()) {
+			break
+		}
+		for i := 0; i < v.Len(); i++ {
+			fields = configWalk(prefix+"["+strconv.Itoa(i)+"]", v.Index(i), fields, visiting)
+		}
+		return fields
+	case reflect.Map:
+		if !configNested(v.Type().Elem()) {
+			break
+		}
+		//按键名排序，每次输出的顺序一致
+		keys := v.MapKeys()
+		names := map[reflect.Value]string{}
+		for _, k := range keys {
+			names[k] = fmt.Sprint(k.Interface())
+		}
+		sort.Slice(keys, func(i, j int) bool { return names[keys[i]] < names[keys[j]] })
+		for _, k := range keys {
+			fields = configWalk(prefix+"["+names[k]+"]", v.MapIndex(k), fields, visiting)
+		}
+		return fields
+	}
+
+	if v.Kind() != reflect.Struct {
+		return append(fields, configField(prefix, fmt.Sprintf("%v", v.Interface())))
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue //未导出的字段
+		}
+
+		tag := field.Tag.Get("log")
+		if tag == "-" {
+			continue
+		}
+
+		name := field.Name
+		if prefix != "" {
+			name = prefix + "." + field.Name
+		}
+
+		if tag == "secret" {
+			fields = append(fields, configField(name, maskSecret(v.Field(i))))
+			continue
+		}
+
+		fields = configWalk(name, v.Field(i), fields, visiting)
+	}
+
+	return fields
+}
+
+/******************************************************************************
+ @brief
+ 	实现了fmt.Stringer的值使用String的结果，例如time.Time的字段都未导出，展开后没有内容。
+ 	包含log:"secret"字段的类型不使用String，避免输出敏感字段，url.URL使用屏蔽了密码的结果
+ @author
+ 	agent
+ @param
+	v					字段值
+ @return
+ 	string				返回String的结果
+ 	bool				没有实现fmt.Stringer或包含敏感字段时返回false
+ @history
+ 	2026-10-16_15:36 	agent		创建
+ 	2026-10-16_16:30 	agent		包含敏感字段时不使用String，屏蔽url.URL中的密码
+*******************************************************************************/
+func configStringer(v reflect.Value) (string, bool) {
+	if !v.CanInterface() {
+		return "", false
+	}
+
+	switch u := v.Interface().(type) {
+	case *url.URL:
+		return u.Redacted(), true
+	case url.URL:
+		return u.Redacted(), true
+	}
+
+	s, ok := v.Interface().(fmt.Stringer)
+	if !ok || configSecret(v.Type(), map[reflect.Type]bool{}) {
+		return "", false
+	}
+
+	return s.String(), true
+}
+
+/******************************************************************************
+ @brief
+ 	类型中是否包含带有log:"secret"标签的字段，包括嵌套结构体和容器元素中的字段
+ @author
+ 	agent
+ @param
+	t					字段类型
+	seen				已经检查过的类型，避免递归类型无限循环
+ @return
+ 	bool				包含敏感字段时返回true
+ @history
+ 	2026-10-16_16:30 	agent		创建
+*******************************************************************************/
+func configSecret(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return configSecret(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.Tag.Get("log") == "secret" || configSecret(field.Type, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+/******************************************************************************
+ @brief
+ 	元素类型是否需要逐个展开，元素为结构体、指针、接口或容器时可能包含需要屏蔽的字段
+ @author
+ 	agent
+ @param
+	t					元素类型
+ @return
+ 	bool				需要展开时返回true
+ @history
+ 	2026-10-16_15:36 	agent		创建
+*******************************************************************************/
+func configNested(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+
+	return false
+}
+
+/******************************************************************************
+ @brief
+ 	生成单个key=value字段，值中包含空白或引号时会加上引号
+ @author
+ 	agent
+ @param
+	key					字段名称
+	value				字段值
+ @return
+ 	string				返回key=value字符串
+ @history
+ 	2026-10-16_14:13 	agent		创建
+*******************************************************************************/
+func configField(key, value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\r\n\"=") {
+		value = fmt.Sprintf("%q", value)
+	}
+
+	if key == "" {
+		return value
+	}
+
+	return key + "=" + value
+}
+
+/******************************************************************************
+ @brief
+ 	屏蔽敏感字段的值，空值保持为空，方便确认是否已经配置
+ @author
+ 	agent
+ @param
+	v					字段值
+ @return
+ 	string				返回屏蔽后的值
+ @history
+ 	2026-10-16_14:13 	agent		创建
+*******************************************************************************/
+func maskSecret(v reflect.Value) string {
+	if v.IsZero() {
+		return ""
+	}
+
+	return "******"
+}
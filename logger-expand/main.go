@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/baickl/logger"
+)
+
+/******************************************************************************
+ @brief
+ 	还原日志文件中SetFileCompressLong压缩的超长内容，输出到标准输出，
+ 	没有指定文件时读取标准输入
+ 		例：
+ 			logger-expand ./logs/2026-10-17/LoginServer.18_00_00.log | less
+ 			tail -f ./logs/2026-10-17/LoginServer.18_00_00.log | logger-expand
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:11 	agent		创建
+*******************************************************************************/
+func main() {
+
+	if len(os.Args) < 2 {
+		if _, err := io.Copy(os.Stdout, logger.ExpandReader(os.Stdin)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	code := 0
+	for _, fn := range os.Args[1:] {
+		f, err := os.Open(fn)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			code = 1
+			continue
+		}
+
+		if _, err := io.Copy(os.Stdout, logger.ExpandReader(f)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			code = 1
+		}
+		f.Close()
+	}
+
+	os.Exit(code)
+}
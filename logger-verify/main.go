@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/baickl/logger"
+)
+
+/******************************************************************************
+ @brief
+ 	检查SetFileChecksum输出的日志文件，打印校验失败的日志所在的行号，
+ 	有文件校验失败或无法读取时退出码为1
+ 		例：
+ 			logger-verify ./logs/2026-10-17/*.log
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:12 	agent		创建
+*******************************************************************************/
+func main() {
+
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: logger-verify file...")
+		os.Exit(2)
+	}
+
+	code := 0
+	for _, fn := range os.Args[1:] {
+		bad, err := logger.VerifyChecksum(fn)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			code = 1
+			continue
+		}
+
+		for _, n := range bad {
+			fmt.Printf("%s:%d: checksum mismatch\n", fn, n)
+		}
+		if len(bad) > 0 {
+			code = 1
+		}
+	}
+
+	os.Exit(code)
+}
@@ -1,15 +1,18 @@
 package logger
 
 import (
+	"bytes"
 	"fmt"
 	"log"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
+	"path/filepath"
 	"runtime"
 	"runtime/debug"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -61,24 +64,44 @@ const (
  	chenzhiguo
  @history
  	2015-05-16_10:22 	chenzhiguo		创建
+ 	2026-10-16_14:25 	agent		切分后第一次写入时才创建日志文件
+ 	2026-10-16_14:27 	agent		支持只读保留模式
+ 	2026-10-16_14:30 	agent		文件操作通过日志存储接口完成
+ 	2026-10-16_14:43 	agent		写入日志不再加锁，切分时替换文件句柄
+ 	2026-10-16_14:49 	agent		支持文件头
+ 	2026-10-16_15:00 	agent		支持单独设置保留时间和总大小上限
+ 	2026-10-16_15:34 	agent		记录本进程切分过的文件
 *******************************************************************************/
 type LOG_FILE struct {
-	sync.RWMutex             //线程锁
-	log_dir      string      //日志存放目录
-	log_filename string      //日志基础名字
-	timestamp    time.Time   //日志创建时的时间戳
-	logfilepath  string      //当前日志路径
-	logfile      *os.File    //当前日志文件实例
-	logger       *log.Logger //当前日志操作实例
+	sync.RWMutex                 //切分线程锁，写入日志不需要加锁
+	log_dir      string          //日志存放目录
+	log_filename string          //日志基础名字
+	timestamp    time.Time       //日志创建时的时间戳
+	handle       atomic.Value    //当前日志文件句柄*fileHandle，切分时整体替换
+	worm         time.Duration   //切分后文件设为只读，保留期内不允许删除，0表示关闭
+	header       atomic.Value    //新文件开头写入的文件头func() []byte，没有设置时不写
+	retention    time.Duration   //保留时间，0表示使用SetRetention的设置
+	quota        int64           //历史文件总大小上限，0表示不限制
+	rotated      map[string]bool //本进程切分过的文件，只清理其中的空文件，切分线程锁保护
 }
 
 var (
-	logLevel         LEVEL     = ALL  //日志级别
-	logConsole       bool      = true //终端控制台显示控制，默认为true
-	logConsolePrefix string           //终端控制台显示前缀
-	logFile          *LOG_FILE        //日志文件实例
+	logLevel         int32     = int32(ALL) //日志级别，写日志时无锁读取，通过currentLevel、storeLevel原子读写
+	logConsole       bool      = true       //终端控制台显示控制，默认为true
+	logConsolePrefix string                 //终端控制台显示前缀
+	logFile          *LOG_FILE              //日志文件实例
+	logCallerFunc    bool                   //调用者信息中是否包含函数名
+	logCallerTrim    []string               //调用者文件路径需要去掉的前缀
+	logFileHostPID   bool                   //日志文件名中是否包含主机名和进程ID
+
+	monitorLock sync.Mutex    //文件监控启动停止线程锁
+	monitorStop chan struct{} //停止当前的文件监控，为nil表示没有启动
 )
 
+var logLevelFlags = [FATAL + 1]int{ //各级别日志输出flag
+	logFlags, logFlags, logFlags, logFlags, logFlags, logFlags,
+}
+
 /******************************************************************************
  @brief
  	设置终端控制台是否显示日志
@@ -122,9 +145,202 @@ func SetConsolePrefix(prefix string) {
  	-
  @history
  	2015-05-16_10:22 	chenzhiguo		创建
+ 	2026-10-16_15:35 	agent		在时间段规则线程锁内原子写入日志级别
 *******************************************************************************/
 func SetLevel(_level LEVEL) {
-	logLevel = _level
+	scheduleLock.Lock()
+	defer scheduleLock.Unlock()
+
+	//如果设置了时间段规则，以时间段规则为准
+	logLevelBase = _level
+	storeLevel(scheduledLevel(time.Now()))
+}
+
+/******************************************************************************
+ @brief
+ 	获取当前生效的日志级别，写日志时调用，不需要加锁
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	LEVEL				返回日志级别
+ @history
+ 	2026-10-16_15:35 	agent		创建
+*******************************************************************************/
+func currentLevel() LEVEL {
+	return LEVEL(atomic.LoadInt32(&logLevel))
+}
+
+/******************************************************************************
+ @brief
+ 	设置当前生效的日志级别，调用者需要持有时间段规则线程锁
+ @author
+ 	agent
+ @param
+	level				日志级别
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:35 	agent		创建
+*******************************************************************************/
+func storeLevel(level LEVEL) {
+	atomic.StoreInt32(&logLevel, int32(level))
+}
+
+/******************************************************************************
+ @brief
+ 	设置指定级别日志的输出flag，使用标准库log的flag，默认为log.Ldate|log.Lmicroseconds|log.Lshortfile，
+ 	没有设置log.Lshortfile或log.Llongfile时不会获取调用者信息，可以降低高频日志的开销
+ 		例：
+ 			//DEBUG和INFO日志不输出调用者信息
+ 			logger.SetLevelFlags(logger.DEBUG, log.Ldate|log.Lmicroseconds)
+ 			logger.SetLevelFlags(logger.INFO, log.Ldate|log.Lmicroseconds)
+ @author
+ 	agent
+ @param
+	_level				日志级别，ALL表示设置所有级别
+	flags				标准库log的flag
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:14 	agent		创建
+*******************************************************************************/
+func SetLevelFlags(_level LEVEL, flags int) {
+	if _level == ALL {
+		for i := range logLevelFlags {
+			logLevelFlags[i] = flags
+		}
+		return
+	}
+
+	if _level > ALL && _level <= FATAL {
+		logLevelFlags[_level] = flags
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	设置调用者信息中是否包含函数名，文件和终端控制台都会生效
+ 		例如：main.go:12 main.(*Server).handleLogin: INFO login ok
+ @author
+ 	agent
+ @param
+	isFunc				是否包含函数名
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:14 	agent		创建
+*******************************************************************************/
+func SetCallerFunc(isFunc bool) {
+	logCallerFunc = isFunc
+}
+
+/******************************************************************************
+ @brief
+ 	设置调用者文件路径需要去掉的前缀，设置后调用者信息显示为相对路径而不只是文件名，
+ 	不同包中的同名文件可以区分开。没有匹配的前缀时仍然只显示文件名
+ 		例：
+ 			logger.SetCallerTrimPrefix("/home/build/src/", "github.com/baickl/")
+
+ 		/home/build/src/server/login/handler.go 显示为 server/login/handler.go
+ 		使用-trimpath编译时路径以模块名开头，可以把模块名的上级路径作为前缀
+ @author
+ 	agent
+ @param
+	prefixes			前缀列表，不传参数表示恢复只显示文件名
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:14 	agent		创建
+*******************************************************************************/
+func SetCallerTrimPrefix(prefixes ...string) {
+	trim := make([]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		prefix = strings.Replace(prefix, "\\", "/", -1)
+		if len(prefix) > 0 && !strings.HasSuffix(prefix, "/") {
+			prefix += "/"
+		}
+		trim = append(trim, prefix)
+	}
+
+	logCallerTrim = trim
+}
+
+/******************************************************************************
+ @brief
+ 	设置日志文件名中是否包含主机名和进程ID，多台机器的日志收集到同一个目录时不会冲突，
+ 	需要在Initialize之前调用
+ 		例：
+ 			logger.SetFileNameHostPID(true)
+ 			logger.Initialize("./log/","login_server")
+
+ 		那么日志系统会创建 ./log/2015-05-16/login_server.host01.1234.10_22_00.log 日志文件
+ @author
+ 	agent
+ @param
+	isHostPID			是否包含主机名和进程ID
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:18 	agent		创建
+*******************************************************************************/
+func SetFileNameHostPID(isHostPID bool) {
+	logFileHostPID = isHostPID
+}
+
+/******************************************************************************
+ @brief
+ 	获取日志级别的名称
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	string				返回级别名称，例如DEBUG
+ @history
+ 	2026-10-16_14:09 	agent		创建
+*******************************************************************************/
+func (l LEVEL) String() string {
+	switch l {
+	case ALL:
+		return "ALL"
+	case DEBUG:
+		return "DEBUG"
+	case INFO:
+		return "INFO"
+	case WARN:
+		return "WARN"
+	case ERROR:
+		return "ERROR"
+	case FATAL:
+		return "FATAL"
+	}
+
+	return fmt.Sprintf("LEVEL(%d)", int(l))
+}
+
+/******************************************************************************
+ @brief
+ 	根据名称解析日志级别，不区分大小写
+ @author
+ 	agent
+ @param
+	name				级别名称，例如debug、INFO
+ @return
+ 	LEVEL				返回日志级别
+ 	error				名称无效时返回错误信息
+ @history
+ 	2026-10-16_14:09 	agent		创建
+*******************************************************************************/
+func ParseLevel(name string) (LEVEL, error) {
+	for l := ALL; l <= FATAL; l++ {
+		if strings.EqualFold(name, l.String()) {
+			return l, nil
+		}
+	}
+
+	return ALL, fmt.Errorf("logger: unknown level %q", name)
 }
 
 /******************************************************************************
@@ -166,9 +382,26 @@ func SprintColor(str string, s STYLE, fc, bc COLOR) string {
  	int					返回结果
  @history
  	2015-05-16_10:22 	chenzhiguo		创建
+ 	2026-10-16_14:17 	agent		关闭日志文件输出时不创建日志文件
+ 	2026-10-16_14:19 	agent		文件时间按照切分时间段对齐
+ 	2026-10-16_14:22 	agent		创建前检查磁盘空间并预分配
+ 	2026-10-16_14:25 	agent		创建分类日志文件
+ 	2026-10-16_14:27 	agent		清理过期文件
+ 	2026-10-16_14:30 	agent		文件操作通过日志存储接口完成
+ 	2026-10-16_14:43 	agent		使用文件句柄
+ 	2026-10-16_14:46 	agent		发布文件创建事件
+ 	2026-10-16_15:03 	agent		启动日志带上编译信息
+ 	2026-10-16_15:05 	agent		平滑升级时继续写入旧进程的文件
+ 	2026-10-16_15:48 	agent		第一个文件也写入文件头
+ 	2026-10-16_15:49 	agent		重复初始化时停止之前的文件监控
 *******************************************************************************/
 func Initialize(fileDir, fileName string) {
 
+	//已经关闭日志文件输出
+	if logFileOff {
+		return
+	}
+
 	//目录修正
 	dir := fileDir
 	if fileDir[len(fileDir)-1] == '\\' || fileDir[len(fileDir)-1] == '/' {
@@ -176,25 +409,45 @@ func Initialize(fileDir, fileName string) {
 	}
 
 	//初始化结构体
-	logFile = &LOG_FILE{log_dir: dir, log_filename: fileName, timestamp: time.Now()}
+	logFile = &LOG_FILE{log_dir: dir, log_filename: fileName, timestamp: rotateTimestamp()}
 	logFile.Lock()
 	defer logFile.Unlock()
 
-	//创建文件
-	fn := logFile.newlogfile()
-	var err error
-	logFile.logfile, err = os.OpenFile(fn, os.O_RDWR|os.O_APPEND|os.O_CREATE, os.ModePerm)
-	if err != nil {
-		panic(err)
+	//创建文件，磁盘空间不足时由文件监控模块在空间恢复后创建，平滑升级时继续写入旧进程的文件
+	fn := handoverPath(logFile)
+	if len(fn) == 0 {
+		fn = logFile.newlogfile()
+	}
+	h := &fileHandle{header: &logFile.header}
+	if diskCheck(dir) {
+		logStorage.MkdirAll(filepath.Dir(fn), os.ModePerm)
+
+		var err error
+		h.file, err = logStorage.OpenFile(fn, os.O_RDWR|os.O_APPEND|os.O_CREATE, os.ModePerm)
+		if err != nil {
+			panic(err)
+		}
+		h.path = fn
+		h.create.Do(func() {})
+		h.opened = 1
+		diskPreallocate(h.file)
+		h.writeHeader()
+		lifecycle(LIFECYCLE_EVENT{Kind: LIFECYCLE_FILE_OPENED, File: fn})
 	}
+	logFile.swap(h)
 
 	//初始化日志
-	logFile.logger = log.New(logFile.logfile, "", logFlags)
 	log.SetFlags(logConsoleFlag)
-	logFile.logfilepath = fn
+	logFile.sweep()
+	initCategories(dir)
 
-	//启动文件监控模块
-	go fileMonitor()
+	//启动文件监控模块，重复初始化时停止之前的监控
+	startFileMonitor()
+
+	//启动日志带上编译信息
+	if logBuildFields && currentLevel() <= INFO {
+		output(INFO, "startup "+buildFields()+"\n")
+	}
 }
 
 /******************************************************************************
@@ -231,30 +484,101 @@ func StartPPROF(port int) {
  	-
  @history
  	2015-05-16_10:22 	chenzhiguo		创建
+ 	2026-10-16_14:52 	agent		附带异常发生前的最近日志
+ 	2026-10-16_14:52 	agent		支持生成core文件
+ 	2026-10-16_14:53 	agent		生成运行时状态快照
+ 	2026-10-16_14:53 	agent		异常处理拆分到dumpException
 *******************************************************************************/
 func CatchException() {
 
 	if err := recover(); err != nil {
-		logfile, err2 := os.OpenFile(newDumpFile(), os.O_RDWR|os.O_APPEND|os.O_CREATE, os.ModePerm)
-		if err2 != nil {
-			return
+		dumpException(err)
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	将异常信息写入异常目录，需要在recover所在的defer函数中调用，调用栈才包含发生异常的位置
+ @author
+ 	agent
+ @param
+	err					recover返回的异常
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:53 	agent		从CatchException中拆分
+ 	2026-10-16_15:02 	agent		上传异常报告
+ 	2026-10-16_15:03 	agent		异常报告带上编译信息
+ 	2026-10-16_15:03 	agent		相同异常去重
+ 	2026-10-16_15:42 	agent		通过日志存储写入
+*******************************************************************************/
+func dumpException(err interface{}) {
+
+	//间隔内相同调用栈的异常只追加重复记录
+	sig := panicSignature()
+	if path, count, ok := panicRepeat(sig); ok {
+		repeated := fmt.Sprintf("REPEATED: %s #%d %#v", time.Now().Format("2006/01/02 15:04:05"), count, err)
+		if f, err2 := logStorage.OpenFile(path, os.O_WRONLY|os.O_APPEND, os.ModePerm); err2 == nil {
+			fmt.Fprintln(f, repeated)
+			f.Close()
 		}
+		fmt.Println(repeated, "see", path)
 
-		defer logfile.Close()
-		logger := log.New(logfile, "", logFlags)
-		logger.SetFlags(logDumpExceptionFlag)
+		recordCrash()
+		coreDump()
+		return
+	}
+
+	dumpFile := newDumpFile()
+	logfile, err2 := logStorage.OpenFile(dumpFile, os.O_RDWR|os.O_APPEND|os.O_CREATE, os.ModePerm)
+	if err2 != nil {
+		return
+	}
+	panicRemember(sig, dumpFile)
+
+	defer logfile.Close()
+	logger := log.New(logfile, "", logFlags)
+	logger.SetFlags(logDumpExceptionFlag)
+
+	build := ""
+	if logBuildFields {
+		build = "\nBUILD: " + buildFields()
+	}
 
-		strLog := fmt.Sprintf(`
+	strLog := fmt.Sprintf(`
 ===============================================================================
-EXCEPTION: %#v																			
+EXCEPTION: %#v%s																			
 ===============================================================================		
 %s`,
-			err,
-			string(debug.Stack()))
+		err,
+		build,
+		string(debug.Stack()))
 
-		logger.Println(strLog)
-		fmt.Println(strLog)
+	//附带异常发生前的最近日志
+	if tail := crashTailLines(); len(tail) > 0 {
+		strLog += fmt.Sprintf(`
+===============================================================================
+RECENT LOG: last %d lines
+===============================================================================
+%s`,
+			len(tail),
+			bytes.Join(tail, nil))
 	}
+
+	logger.Println(strLog)
+	fmt.Println(strLog)
+
+	//运行时状态快照
+	writeMinidump(fmt.Sprintf("%v", err))
+
+	//后台上传异常报告
+	go crashUpload()
+
+	//崩溃循环检测
+	recordCrash()
+
+	//开启了core文件时终止进程
+	coreDump()
 }
 
 /******************************************************************************
@@ -268,6 +592,7 @@ EXCEPTION: %#v
  	string				返回dump文件路径
  @history
  	2015-05-16_10:22 	chenzhiguo		创建
+ 	2026-10-16_15:42 	agent		通过日志存储创建
 *******************************************************************************/
 func newDumpFile() string {
 
@@ -275,16 +600,16 @@ func newDumpFile() string {
 
 	filename := fmt.Sprintf("exceptions.%02d_%02d_%02d", now.Hour(), now.Minute(), now.Second())
 	dir := fmt.Sprintf("./exceptions/%04d-%02d-%02d/", now.Year(), int(now.Month()), now.Day())
-	os.MkdirAll(dir, os.ModePerm)
+	logStorage.MkdirAll(dir, os.ModePerm)
 	fn := fmt.Sprintf("%s%s.log", dir, filename)
-	if !isFileExist(fn) {
+	if !storageExist(fn) {
 		return fn
 	}
 
 	n := 1
 	for {
 		fn = fmt.Sprintf("%s%s_%d.log", dir, filename, n)
-		if !isFileExist(fn) {
+		if !storageExist(fn) {
 			break
 		}
 
@@ -299,7 +624,7 @@ type Verbose bool
 
 func V(level LEVEL) Verbose {
 
-	if logLevel >= level {
+	if currentLevel() >= level {
 		return Verbose(true)
 	}
 
@@ -322,16 +647,7 @@ func (v Verbose) Debug(arg interface{}) {
 	}
 
 	defer catchError()
-
-	context := fmt.Sprintf("DEBUG %s", fmt.Sprintln(arg))
-	context = strings.TrimRight(context, "\n")
-	if logFile != nil {
-		logFile.RLock()
-		defer logFile.RUnlock()
-		logFile.logger.Output(2, context)
-	}
-	console(DEBUG, context)
-
+	output(DEBUG, fmt.Sprintln(arg))
 }
 
 /******************************************************************************
@@ -350,16 +666,7 @@ func (v Verbose) Info(arg interface{}) {
 	}
 
 	defer catchError()
-
-	context := fmt.Sprintf("INFO %s", fmt.Sprintln(arg))
-	context = strings.TrimRight(context, "\n")
-	if logFile != nil {
-		logFile.RLock()
-		defer logFile.RUnlock()
-		logFile.logger.Output(2, context)
-	}
-	console(INFO, context)
-
+	output(INFO, fmt.Sprintln(arg))
 }
 
 /******************************************************************************
@@ -378,16 +685,7 @@ func (v Verbose) Warn(arg interface{}) {
 	}
 
 	defer catchError()
-
-	context := fmt.Sprintf("WARN %s", fmt.Sprintln(arg))
-	context = strings.TrimRight(context, "\n")
-	if logFile != nil {
-		logFile.RLock()
-		defer logFile.RUnlock()
-		logFile.logger.Output(2, context)
-	}
-	console(WARN, context)
-
+	output(WARN, fmt.Sprintln(arg))
 }
 
 /******************************************************************************
@@ -406,15 +704,7 @@ func (v Verbose) Error(arg interface{}) {
 	}
 
 	defer catchError()
-
-	context := fmt.Sprintf("ERROR %s", fmt.Sprintln(arg))
-	context = strings.TrimRight(context, "\n")
-	if logFile != nil {
-		logFile.RLock()
-		defer logFile.RUnlock()
-		logFile.logger.Output(2, context)
-	}
-	console(ERROR, context)
+	output(ERROR, fmt.Sprintln(arg))
 }
 
 /******************************************************************************
@@ -433,15 +723,7 @@ func (v Verbose) Fatal(arg interface{}) {
 	}
 
 	defer catchError()
-
-	context := fmt.Sprintf("FATAL %s", fmt.Sprintln(arg))
-	context = strings.TrimRight(context, "\n")
-	if logFile != nil {
-		logFile.RLock()
-		defer logFile.RUnlock()
-		logFile.logger.Output(2, context)
-	}
-	console(FATAL, context)
+	output(FATAL, fmt.Sprintln(arg))
 }
 
 /******************************************************************************
@@ -460,15 +742,7 @@ func (v Verbose) Debugf(format string, args ...interface{}) {
 	}
 
 	defer catchError()
-
-	context := fmt.Sprintf("DEBUG %s", fmt.Sprintf(format, args...))
-	context = strings.TrimRight(context, "\n")
-	if logFile != nil {
-		logFile.RLock()
-		defer logFile.RUnlock()
-		logFile.logger.Output(2, context)
-	}
-	console(DEBUG, context)
+	output(DEBUG, fmt.Sprintf(format, args...))
 }
 
 /******************************************************************************
@@ -487,15 +761,7 @@ func (v Verbose) Infof(format string, args ...interface{}) {
 	}
 
 	defer catchError()
-
-	context := fmt.Sprintf("INFO %s", fmt.Sprintf(format, args...))
-	context = strings.TrimRight(context, "\n")
-	if logFile != nil {
-		logFile.RLock()
-		defer logFile.RUnlock()
-		logFile.logger.Output(2, context)
-	}
-	console(INFO, context)
+	output(INFO, fmt.Sprintf(format, args...))
 }
 
 /******************************************************************************
@@ -514,15 +780,7 @@ func (v Verbose) Warnf(format string, args ...interface{}) {
 	}
 
 	defer catchError()
-
-	context := fmt.Sprintf("WARN %s", fmt.Sprintf(format, args...))
-	context = strings.TrimRight(context, "\n")
-	if logFile != nil {
-		logFile.RLock()
-		defer logFile.RUnlock()
-		logFile.logger.Output(2, context)
-	}
-	console(WARN, context)
+	output(WARN, fmt.Sprintf(format, args...))
 }
 
 /******************************************************************************
@@ -541,15 +799,7 @@ func (v Verbose) Errorf(format string, args ...interface{}) {
 	}
 
 	defer catchError()
-
-	context := fmt.Sprintf("ERROR %s", fmt.Sprintf(format, args...))
-	context = strings.TrimRight(context, "\n")
-	if logFile != nil {
-		logFile.RLock()
-		defer logFile.RUnlock()
-		logFile.logger.Output(2, context)
-	}
-	console(ERROR, context)
+	output(ERROR, fmt.Sprintf(format, args...))
 }
 
 /******************************************************************************
@@ -568,15 +818,7 @@ func (v Verbose) Fatalf(format string, args ...interface{}) {
 	}
 
 	defer catchError()
-
-	context := fmt.Sprintf("FATAL %s", fmt.Sprintf(format, args...))
-	context = strings.TrimRight(context, "\n")
-	if logFile != nil {
-		logFile.RLock()
-		defer logFile.RUnlock()
-		logFile.logger.Output(2, context)
-	}
-	console(FATAL, context)
+	output(FATAL, fmt.Sprintf(format, args...))
 }
 
 /******************************************************************************
@@ -595,15 +837,7 @@ func (v Verbose) Debugln(args ...interface{}) {
 	}
 
 	defer catchError()
-
-	context := fmt.Sprintf("DEBUG %s", fmt.Sprintln(args...))
-	context = strings.TrimRight(context, "\n")
-	if logFile != nil {
-		logFile.RLock()
-		defer logFile.RUnlock()
-		logFile.logger.Output(2, context)
-	}
-	console(DEBUG, context)
+	output(DEBUG, fmt.Sprintln(args...))
 }
 
 /******************************************************************************
@@ -622,15 +856,7 @@ func (v Verbose) Infoln(args ...interface{}) {
 	}
 
 	defer catchError()
-
-	context := fmt.Sprintf("INFO %s", fmt.Sprintln(args...))
-	context = strings.TrimRight(context, "\n")
-	if logFile != nil {
-		logFile.RLock()
-		defer logFile.RUnlock()
-		logFile.logger.Output(2, context)
-	}
-	console(INFO, context)
+	output(INFO, fmt.Sprintln(args...))
 }
 
 /******************************************************************************
@@ -649,15 +875,7 @@ func (v Verbose) Warnln(args ...interface{}) {
 	}
 
 	defer catchError()
-
-	context := fmt.Sprintf("WARN %s", fmt.Sprintln(args...))
-	context = strings.TrimRight(context, "\n")
-	if logFile != nil {
-		logFile.RLock()
-		defer logFile.RUnlock()
-		logFile.logger.Output(2, context)
-	}
-	console(WARN, context)
+	output(WARN, fmt.Sprintln(args...))
 }
 
 /******************************************************************************
@@ -676,15 +894,7 @@ func (v Verbose) Errorln(args ...interface{}) {
 	}
 
 	defer catchError()
-
-	context := fmt.Sprintf("ERROR %s", fmt.Sprintln(args...))
-	context = strings.TrimRight(context, "\n")
-	if logFile != nil {
-		logFile.RLock()
-		defer logFile.RUnlock()
-		logFile.logger.Output(2, context)
-	}
-	console(ERROR, context)
+	output(ERROR, fmt.Sprintln(args...))
 }
 
 /******************************************************************************
@@ -703,15 +913,7 @@ func (v Verbose) Fatalln(args ...interface{}) {
 	}
 
 	defer catchError()
-
-	context := fmt.Sprintf("FATAL %s", fmt.Sprintln(args...))
-	context = strings.TrimRight(context, "\n")
-	if logFile != nil {
-		logFile.RLock()
-		defer logFile.RUnlock()
-		logFile.logger.Output(2, context)
-	}
-	console(FATAL, context)
+	output(FATAL, fmt.Sprintln(args...))
 }
 
 /******************************************************************************
@@ -728,15 +930,8 @@ func (v Verbose) Fatalln(args ...interface{}) {
 *******************************************************************************/
 func Debug(arg interface{}) {
 	defer catchError()
-	if logLevel <= DEBUG {
-		context := fmt.Sprintf("DEBUG %s", fmt.Sprintln(arg))
-		context = strings.TrimRight(context, "\n")
-		if logFile != nil {
-			logFile.RLock()
-			defer logFile.RUnlock()
-			logFile.logger.Output(2, context)
-		}
-		console(DEBUG, context)
+	if currentLevel() <= DEBUG {
+		output(DEBUG, fmt.Sprintln(arg))
 	}
 }
 
@@ -754,15 +949,8 @@ func Debug(arg interface{}) {
 *******************************************************************************/
 func Info(arg interface{}) {
 	defer catchError()
-	if logLevel <= INFO {
-		context := fmt.Sprintf("INFO %s", fmt.Sprintln(arg))
-		context = strings.TrimRight(context, "\n")
-		if logFile != nil {
-			logFile.RLock()
-			defer logFile.RUnlock()
-			logFile.logger.Output(2, context)
-		}
-		console(INFO, context)
+	if currentLevel() <= INFO {
+		output(INFO, fmt.Sprintln(arg))
 	}
 }
 
@@ -780,15 +968,8 @@ func Info(arg interface{}) {
 *******************************************************************************/
 func Warn(arg interface{}) {
 	defer catchError()
-	if logLevel <= WARN {
-		context := fmt.Sprintf("WARN %s", fmt.Sprintln(arg))
-		context = strings.TrimRight(context, "\n")
-		if logFile != nil {
-			logFile.RLock()
-			defer logFile.RUnlock()
-			logFile.logger.Output(2, context)
-		}
-		console(WARN, context)
+	if currentLevel() <= WARN {
+		output(WARN, fmt.Sprintln(arg))
 	}
 }
 
@@ -806,15 +987,8 @@ func Warn(arg interface{}) {
 *******************************************************************************/
 func Error(arg interface{}) {
 	defer catchError()
-	if logLevel <= ERROR {
-		context := fmt.Sprintf("ERROR %s", fmt.Sprintln(arg))
-		context = strings.TrimRight(context, "\n")
-		if logFile != nil {
-			logFile.RLock()
-			defer logFile.RUnlock()
-			logFile.logger.Output(2, context)
-		}
-		console(ERROR, context)
+	if currentLevel() <= ERROR {
+		output(ERROR, fmt.Sprintln(arg))
 	}
 }
 
@@ -832,15 +1006,8 @@ func Error(arg interface{}) {
 *******************************************************************************/
 func Fatal(arg interface{}) {
 	defer catchError()
-	if logLevel <= FATAL {
-		context := fmt.Sprintf("FATAL %s", fmt.Sprintln(arg))
-		context = strings.TrimRight(context, "\n")
-		if logFile != nil {
-			logFile.RLock()
-			defer logFile.RUnlock()
-			logFile.logger.Output(2, context)
-		}
-		console(FATAL, context)
+	if currentLevel() <= FATAL {
+		output(FATAL, fmt.Sprintln(arg))
 	}
 }
 
@@ -859,15 +1026,8 @@ func Fatal(arg interface{}) {
 *******************************************************************************/
 func Debugf(format string, args ...interface{}) {
 	defer catchError()
-	if logLevel <= DEBUG {
-		context := fmt.Sprintf("DEBUG %s", fmt.Sprintf(format, args...))
-		context = strings.TrimRight(context, "\n")
-		if logFile != nil {
-			logFile.RLock()
-			defer logFile.RUnlock()
-			logFile.logger.Output(2, context)
-		}
-		console(DEBUG, context)
+	if currentLevel() <= DEBUG {
+		output(DEBUG, fmt.Sprintf(format, args...))
 	}
 }
 
@@ -886,15 +1046,8 @@ func Debugf(format string, args ...interface{}) {
 *******************************************************************************/
 func Infof(format string, args ...interface{}) {
 	defer catchError()
-	if logLevel <= INFO {
-		context := fmt.Sprintf("INFO %s", fmt.Sprintf(format, args...))
-		context = strings.TrimRight(context, "\n")
-		if logFile != nil {
-			logFile.RLock()
-			defer logFile.RUnlock()
-			logFile.logger.Output(2, context)
-		}
-		console(INFO, context)
+	if currentLevel() <= INFO {
+		output(INFO, fmt.Sprintf(format, args...))
 	}
 }
 
@@ -913,15 +1066,8 @@ func Infof(format string, args ...interface{}) {
 *******************************************************************************/
 func Warnf(format string, args ...interface{}) {
 	defer catchError()
-	if logLevel <= WARN {
-		context := fmt.Sprintf("WARN %s", fmt.Sprintf(format, args...))
-		context = strings.TrimRight(context, "\n")
-		if logFile != nil {
-			logFile.RLock()
-			defer logFile.RUnlock()
-			logFile.logger.Output(2, context)
-		}
-		console(WARN, context)
+	if currentLevel() <= WARN {
+		output(WARN, fmt.Sprintf(format, args...))
 	}
 }
 
@@ -940,15 +1086,8 @@ func Warnf(format string, args ...interface{}) {
 *******************************************************************************/
 func Errorf(format string, args ...interface{}) {
 	defer catchError()
-	if logLevel <= ERROR {
-		context := fmt.Sprintf("ERROR %s", fmt.Sprintf(format, args...))
-		context = strings.TrimRight(context, "\n")
-		if logFile != nil {
-			logFile.RLock()
-			defer logFile.RUnlock()
-			logFile.logger.Output(2, context)
-		}
-		console(ERROR, context)
+	if currentLevel() <= ERROR {
+		output(ERROR, fmt.Sprintf(format, args...))
 	}
 }
 
@@ -967,15 +1106,8 @@ func Errorf(format string, args ...interface{}) {
 *******************************************************************************/
 func Fatalf(format string, args ...interface{}) {
 	defer catchError()
-	if logLevel <= FATAL {
-		context := fmt.Sprintf("FATAL %s", fmt.Sprintf(format, args...))
-		context = strings.TrimRight(context, "\n")
-		if logFile != nil {
-			logFile.RLock()
-			defer logFile.RUnlock()
-			logFile.logger.Output(2, context)
-		}
-		console(FATAL, context)
+	if currentLevel() <= FATAL {
+		output(FATAL, fmt.Sprintf(format, args...))
 	}
 }
 
@@ -994,15 +1126,8 @@ func Fatalf(format string, args ...interface{}) {
 *******************************************************************************/
 func Debugln(args ...interface{}) {
 	defer catchError()
-	if logLevel <= DEBUG {
-		context := fmt.Sprintf("DEBUG %s", fmt.Sprintln(args...))
-		context = strings.TrimRight(context, "\n")
-		if logFile != nil {
-			logFile.RLock()
-			defer logFile.RUnlock()
-			logFile.logger.Output(2, context)
-		}
-		console(DEBUG, context)
+	if currentLevel() <= DEBUG {
+		output(DEBUG, fmt.Sprintln(args...))
 	}
 }
 
@@ -1021,15 +1146,8 @@ func Debugln(args ...interface{}) {
 *******************************************************************************/
 func Infoln(args ...interface{}) {
 	defer catchError()
-	if logLevel <= INFO {
-		context := fmt.Sprintf("INFO %s", fmt.Sprintln(args...))
-		context = strings.TrimRight(context, "\n")
-		if logFile != nil {
-			logFile.RLock()
-			defer logFile.RUnlock()
-			logFile.logger.Output(2, context)
-		}
-		console(INFO, context)
+	if currentLevel() <= INFO {
+		output(INFO, fmt.Sprintln(args...))
 	}
 }
 
@@ -1048,15 +1166,8 @@ func Infoln(args ...interface{}) {
 *******************************************************************************/
 func Warnln(args ...interface{}) {
 	defer catchError()
-	if logLevel <= WARN {
-		context := fmt.Sprintf("WARN %s", fmt.Sprintln(args...))
-		context = strings.TrimRight(context, "\n")
-		if logFile != nil {
-			logFile.RLock()
-			defer logFile.RUnlock()
-			logFile.logger.Output(2, context)
-		}
-		console(WARN, context)
+	if currentLevel() <= WARN {
+		output(WARN, fmt.Sprintln(args...))
 	}
 }
 
@@ -1075,15 +1186,8 @@ func Warnln(args ...interface{}) {
 *******************************************************************************/
 func Errorln(args ...interface{}) {
 	defer catchError()
-	if logLevel <= ERROR {
-		context := fmt.Sprintf("ERROR %s", fmt.Sprintln(args...))
-		context = strings.TrimRight(context, "\n")
-		if logFile != nil {
-			logFile.RLock()
-			defer logFile.RUnlock()
-			logFile.logger.Output(2, context)
-		}
-		console(ERROR, context)
+	if currentLevel() <= ERROR {
+		output(ERROR, fmt.Sprintln(args...))
 	}
 }
 
@@ -1102,15 +1206,8 @@ func Errorln(args ...interface{}) {
 *******************************************************************************/
 func Fatalln(args ...interface{}) {
 	defer catchError()
-	if logLevel <= FATAL {
-		context := fmt.Sprintf("FATAL %s", fmt.Sprintln(args...))
-		context = strings.TrimRight(context, "\n")
-		if logFile != nil {
-			logFile.RLock()
-			defer logFile.RUnlock()
-			logFile.logger.Output(2, context)
-		}
-		console(FATAL, context)
+	if currentLevel() <= FATAL {
+		output(FATAL, fmt.Sprintln(args...))
 	}
 }
 
@@ -1125,6 +1222,7 @@ func Fatalln(args ...interface{}) {
  	bool				返回true表示需要重命名，否则不需要
  @history
  	2015-05-16_10:52 	chenzhiguo		创建
+ 	2026-10-16_14:30 	agent		文件操作通过日志存储接口完成
 *******************************************************************************/
 func (f *LOG_FILE) isMustRename() bool {
 
@@ -1142,7 +1240,7 @@ func (f *LOG_FILE) isMustRename() bool {
 	if f.checkFileExist() {
 		return true
 	} else {
-		os.MkdirAll(f.log_dir, os.ModePerm)
+		logStorage.MkdirAll(f.log_dir, os.ModePerm)
 	}
 
 	return false
@@ -1150,7 +1248,7 @@ func (f *LOG_FILE) isMustRename() bool {
 
 /******************************************************************************
  @brief
- 	检查文件日期是否已经跨天
+ 	检查文件日期是否已经跨天，设置了按时间间隔切分时检查是否已经进入下一个时间段
  @author
  	chenzhiguo
  @param
@@ -1159,9 +1257,14 @@ func (f *LOG_FILE) isMustRename() bool {
  	bool				返回true表示已经跨天，否则没有
  @history
  	2015-05-16_10:52 	chenzhiguo		创建
+ 	2026-10-16_14:19 	agent		支持按时间间隔切分
 *******************************************************************************/
 func (f *LOG_FILE) checkFileDate() bool {
-	if time.Now().YearDay() != f.timestamp.YearDay() {
+	if interval, _ := rotateConfig(); interval > 0 {
+		return !rotateTimestamp().Equal(f.timestamp)
+	}
+
+	if rotateNow().YearDay() != f.timestamp.YearDay() {
 		return true
 	}
 
@@ -1179,10 +1282,17 @@ func (f *LOG_FILE) checkFileDate() bool {
  	bool				返回true表示已经超过，否则没有
  @history
  	2015-05-16_10:52 	chenzhiguo		创建
+ 	2026-10-16_14:30 	agent		文件操作通过日志存储接口完成
+ 	2026-10-16_14:43 	agent		使用文件句柄
 *******************************************************************************/
 func (f *LOG_FILE) checkFileSize() bool {
 
-	fileInfo, err := os.Stat(f.logfilepath)
+	h := f.current()
+	if h == nil {
+		return false
+	}
+
+	fileInfo, err := logStorage.Stat(h.path)
 	if err != nil {
 		return false
 	}
@@ -1205,9 +1315,23 @@ func (f *LOG_FILE) checkFileSize() bool {
  	bool				返回true表示存在，否则表示没有有文件或是没有权限访问
  @history
  	2015-05-16_10:52 	chenzhiguo		创建
+ 	2026-10-16_14:25 	agent		等待第一次写入的文件视为存在
+ 	2026-10-16_14:30 	agent		文件操作通过日志存储接口完成
+ 	2026-10-16_14:43 	agent		使用文件句柄
 *******************************************************************************/
 func (f *LOG_FILE) checkFileExist() bool {
-	if !isFileExist(f.logfilepath) {
+
+	h := f.current()
+	if h == nil {
+		return true
+	}
+
+	//还没有日志写入，文件尚未创建
+	if len(h.path) > 0 && !h.isOpened() {
+		return false
+	}
+
+	if !storageExist(h.path) {
 		return true
 	}
 
@@ -1225,129 +1349,665 @@ func (f *LOG_FILE) checkFileExist() bool {
  	-
  @history
  	2015-05-16_10:52 	chenzhiguo		创建
+ 	2026-10-16_14:19 	agent		文件时间按照切分时间段对齐
+ 	2026-10-16_14:22 	agent		创建前检查磁盘空间并预分配
+ 	2026-10-16_14:25 	agent		第一次写入时才创建日志文件，并清理空文件
+ 	2026-10-16_14:27 	agent		只读保留模式下文件设为只读，不清理空文件，并清理过期文件
+ 	2026-10-16_14:28 	agent		记录校验清单
+ 	2026-10-16_14:43 	agent		替换文件句柄，等待正在进行的写入完成后关闭旧文件
+ 	2026-10-16_14:45 	agent		切分后的处理交给后台任务
+ 	2026-10-16_14:46 	agent		发布文件切分事件
+ 	2026-10-16_14:49 	agent		新文件写入文件头
+ 	2026-10-16_15:34 	agent		记录本进程切分过的文件
+ 	2026-10-16_15:39 	agent		切分的文件放入上传队列
 *******************************************************************************/
 func (f *LOG_FILE) rename() {
-	f.timestamp = time.Now()
+	f.timestamp = rotateTimestamp()
 	fn := f.newlogfile()
 
-	if f.logfile != nil {
-		f.logfile.Close()
+	//清理过期文件，磁盘空间不足时也可以释放空间
+	f.sweep()
+
+	//没有日志写入时不创建空文件，磁盘空间不足时不创建日志文件
+	h := &fileHandle{header: &f.header}
+	if diskCheck(f.log_dir) {
+		h.path = fn
 	}
 
-	f.logfile, _ = os.OpenFile(fn, os.O_RDWR|os.O_APPEND|os.O_CREATE, os.ModePerm)
-	f.logger = log.New(logFile.logfile, "", logFlags)
-	f.logfilepath = fn
+	//替换句柄后关闭旧文件
+	old := f.swap(h)
+	if old != nil && old.close() {
+		lifecycle(LIFECYCLE_EVENT{Kind: LIFECYCLE_FILE_ROTATED, File: old.path})
+
+		if f.rotated == nil {
+			f.rotated = map[string]bool{}
+		}
+		f.rotated[filepath.Clean(old.path)] = true
+		uploadRotated(old.path)
+
+		worm := f.worm
+		goBackground(func() {
+			if worm > 0 {
+				wormSeal(old.path)
+			} else {
+				pruneEmpty(old.path)
+			}
+			manifestAppend(old.path)
+		})
+	}
 }
 
 /******************************************************************************
  @brief
- 	获取新的日志文件的名称
+ 	写入一行日志到当前日志文件，开启异步写入时放入队列，不需要加锁
  @author
- 	chenzhiguo
+ 	agent
  @param
-	-
+	b					日志内容
  @return
- 	string				返回名称
+ 	-
  @history
- 	2015-05-16_10:52 	chenzhiguo		创建
+ 	2026-10-16_14:11 	agent		创建
+ 	2026-10-16_14:11 	agent		支持异步写入，文件写入移到writeFile
+ 	2026-10-16_14:25 	agent		第一次写入时创建日志文件
+ 	2026-10-16_14:43 	agent		通过引用计数代替读锁
+ 	2026-10-16_14:45 	agent		写入移到writeLevel
 *******************************************************************************/
-func (f *LOG_FILE) newlogfile() string {
-
-	dir := fmt.Sprintf("%s/%04d-%02d-%02d/", f.log_dir, f.timestamp.Year(), f.timestamp.Month(), f.timestamp.Day())
-	os.MkdirAll(dir, os.ModePerm)
-	filename := fmt.Sprintf("%s/%s.%02d_%02d_%02d", dir, f.log_filename, f.timestamp.Hour(), f.timestamp.Minute(), f.timestamp.Second())
-
-	fn := filename + ".log"
-	if !isFileExist(fn) {
-		return fn
-	}
-
-	n := 1
-	for {
-		fn = fmt.Sprintf("%s_%d.log", filename, n)
-		if !isFileExist(fn) {
-			break
-		}
-		n += 1
-	}
-
-	return fn
+func (f *LOG_FILE) write(b []byte) {
+	f.writeLevel(levelNone, b)
 }
 
 /******************************************************************************
  @brief
- 	判断文件是否存在
+ 	写入一行带级别的日志到当前日志文件，开启异步写入时放入队列，
+ 	队列满被丢弃时按级别统计，不需要加锁
  @author
- 	chenzhiguo
+ 	agent
  @param
-	file				文件路径
+	ll					日志级别
+	b					日志内容
  @return
- 	bool				返回true表示文件存在，否则表示不存在
+ 	-
  @history
- 	2015-05-16_10:52 	chenzhiguo		创建
+ 	2026-10-16_14:45 	agent		创建，从write拆分
 *******************************************************************************/
-func isFileExist(file string) bool {
-	finfo, err := os.Stat(file)
-	if err != nil {
-		return false
-	}
-	if finfo.IsDir() {
-		return false
-	} else {
-		return true
+func (f *LOG_FILE) writeLevel(ll LEVEL, b []byte) {
+	if asyncWrite(f, ll, b) {
+		return
 	}
+
+	f.writeFile(b)
 }
 
 /******************************************************************************
  @brief
- 	输出信息到终端控制台上
+ 	同步写入一行日志到当前日志文件，异步写入的后台协程直接调用，不需要加锁
  @author
- 	chenzhiguo
+ 	agent
  @param
-	ll					日志等级
-	args				要输出的内容
+	b					日志内容
  @return
  	-
  @history
- 	2015-05-16_10:52 	chenzhiguo		创建
+ 	2026-10-16_14:11 	agent		创建，从write拆分
 *******************************************************************************/
-func console(ll LEVEL, args string) {
-	if logConsole {
-		_, file, line, _ := runtime.Caller(2)
-		short := file
-		for i := len(file) - 1; i > 0; i-- {
-			if file[i] == '/' {
-				short = file[i+1:]
-				break
-			}
+func (f *LOG_FILE) writeFile(b []byte) {
+	for {
+		h := f.current()
+		if h == nil {
+			return
+		}
+
+		//增加引用后句柄已经被替换，使用新的句柄重试
+		atomic.AddInt64(&h.refs, 1)
+		if f.current() != h {
+			atomic.AddInt64(&h.refs, -1)
+			continue
 		}
-		file = short
 
-		now := time.Now()
+		h.create.Do(h.open)
+		if h.file != nil {
+			h.file.Write(b)
+		}
 
-		context := ""
-		if len(logConsolePrefix) > 0 {
-			context = fmt.Sprintf("==>[%04d/%02d/%02d_%02d:%02d:%02d.%06d] @%s #%s:%d %s", now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second(), time.Duration(now.Nanosecond())/(time.Microsecond), logConsolePrefix, file, line, args)
+		atomic.AddInt64(&h.refs, -1)
+		return
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	删除没有写入任何日志的空文件，日期目录为空时一并删除
+ @author
+ 	agent
+ @param
+	fn					日志文件路径
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:25 	agent		创建
+ 	2026-10-16_14:30 	agent		文件操作通过日志存储接口完成
+*******************************************************************************/
+func pruneEmpty(fn string) {
+	fi, err := logStorage.Stat(fn)
+	if err != nil || fi.IsDir() || fi.Size() > 0 {
+		return
+	}
+
+	logStorage.Remove(fn)
+
+	//目录不为空时删除失败
+	logStorage.Remove(filepath.Dir(fn))
+}
+
+/******************************************************************************
+ @brief
+ 	获取新的日志文件的名称
+ @author
+ 	chenzhiguo
+ @param
+	-
+ @return
+ 	string				返回名称
+ @history
+ 	2015-05-16_10:52 	chenzhiguo		创建
+ 	2026-10-16_14:18 	agent		支持文件名中包含主机名和进程ID
+ 	2026-10-16_14:25 	agent		不再创建日期目录，由创建文件时创建
+ 	2026-10-16_14:30 	agent		文件操作通过日志存储接口完成
+ 	2026-10-16_15:34 	agent		文件名前缀改由basename生成
+*******************************************************************************/
+func (f *LOG_FILE) newlogfile() string {
+
+	dir := fmt.Sprintf("%s/%04d-%02d-%02d/", f.log_dir, f.timestamp.Year(), f.timestamp.Month(), f.timestamp.Day())
+
+	filename := fmt.Sprintf("%s/%s.%02d_%02d_%02d", dir, f.basename(), f.timestamp.Hour(), f.timestamp.Minute(), f.timestamp.Second())
+
+	fn := filename + ".log"
+	if !storageExist(fn) {
+		return fn
+	}
+
+	n := 1
+	for {
+		fn = fmt.Sprintf("%s_%d.log", filename, n)
+		if !storageExist(fn) {
+			break
+		}
+		n += 1
+	}
+
+	return fn
+}
+
+/******************************************************************************
+ @brief
+ 	获取日志文件名中时间之前的部分，开启主机名和进程ID时包含两者
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	string				返回文件名前缀
+ @history
+ 	2026-10-16_15:34 	agent		创建，从newlogfile拆分
+*******************************************************************************/
+func (f *LOG_FILE) basename() string {
+	if logFileHostPID {
+		return fmt.Sprintf("%s.%s.%d", f.log_filename, hostname(), os.Getpid())
+	}
+
+	return f.log_filename
+}
+
+/******************************************************************************
+ @brief
+ 	获取主机名，文件名中不能使用的字符替换为下划线
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	string				返回主机名，获取失败时返回unknown
+ @history
+ 	2026-10-16_14:18 	agent		创建
+*******************************************************************************/
+func hostname() string {
+
+	name, err := os.Hostname()
+	if err != nil || len(name) == 0 {
+		return "unknown"
+	}
+
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', ' ':
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+/******************************************************************************
+ @brief
+ 	输出日志到文件、扩展输出目标以及终端控制台，仅供内部使用
+ @author
+ 	agent
+ @param
+	ll					日志等级
+	arg					要输出的内容
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:11 	agent		创建
+ 	2026-10-16_14:11 	agent		开启异步写入时放入队列
+*******************************************************************************/
+func output(ll LEVEL, arg string) {
+	outputFile(logFile, ll, arg, nil)
+}
+
+/******************************************************************************
+ @brief
+ 	输出日志到指定日志文件、扩展输出目标以及终端控制台，仅供内部使用，
+ 	调用者需要保证调用深度与output相同
+ @author
+ 	agent
+ @param
+	f					日志文件，为nil表示不写入文件
+	ll					日志等级
+	arg					要输出的内容
+	labels				路由标签，为nil表示没有
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:25 	agent		创建
+ 	2026-10-16_14:40 	agent		文件和输出目标支持不同的时间精度
+ 	2026-10-16_14:44 	agent		支持自身开销统计
+ 	2026-10-16_14:45 	agent		写入文件时带上级别
+ 	2026-10-16_14:50 	agent		文件和终端控制台分别设置是否输出易读字段
+ 	2026-10-16_14:51 	agent		文件和终端控制台分别设置时区
+ 	2026-10-16_14:51 	agent		支持跨重启的日志去重，输出改由outputEntry完成
+ 	2026-10-16_14:58 	agent		支持过滤规则
+ 	2026-10-16_14:59 	agent		支持日志风暴熔断
+ 	2026-10-16_14:59 	agent		支持改写规则
+ 	2026-10-16_15:00 	agent		支持多租户
+ 	2026-10-16_15:01 	agent		支持写入配额
+ 	2026-10-16_15:06 	agent		传递路由标签
+ 	2026-10-16_15:07 	agent		支持日志级别回调
+ 	2026-10-16_15:11 	agent		支持清理控制字符
+ 	2026-10-16_15:14 	agent		支持字段约束
+ 	2026-10-16_15:14 	agent		支持OpenTelemetry日志量指标
+*******************************************************************************/
+func outputFile(f *LOG_FILE, ll LEVEL, arg string, labels map[string]string) {
+
+	//清理控制字符，改写和过滤规则匹配的是清理后的内容
+	if logSanitize {
+		arg = sanitizeArg(arg)
+	}
+
+	//应用改写规则，改写后的级别低于日志级别时丢弃
+	ll, arg = rewrite(ll, arg)
+	if ll < currentLevel() {
+		return
+	}
+
+	//被过滤规则丢弃
+	if filterDrop(ll, arg) {
+		return
+	}
+
+	//字段不符合约束时提示或丢弃
+	if drop, notice := schemaCheck(f, arg); drop || len(notice) > 0 {
+		if len(notice) > 0 {
+			outputEntry(logFile, WARN, notice, nil, true)
+		}
+		if drop {
+			return
+		}
+	}
+
+	//日志风暴时降级为采样，状态变化的提示不参与采样
+	drop, notice := stormCheck(ll)
+	if len(notice) > 0 {
+		outputEntry(logFile, WARN, notice, nil, true)
+	}
+	if drop {
+		return
+	}
+
+	//间隔内重复的日志，包括重启前写过的
+	if drop, notice := dedupDrop(f, ll, arg); drop {
+		return
+	} else if len(notice) > 0 {
+		outputEntry(tenantFile(f, arg), WARN, notice, nil, true)
+	}
+
+	//超过租户或分类的写入配额
+	if quotaDrop(f, arg) {
+		return
+	}
+
+	//日志级别回调和指标计数，FATAL日志退出进程前也会触发
+	levelHooks(f, ll)
+	otelCount(f, ll)
+
+	//多租户模式下按tenant字段选择日志文件
+	outputEntry(tenantFile(f, arg), ll, arg, labels, true)
+}
+
+/******************************************************************************
+ @brief
+ 	生成日志行并写入日志文件、扩展输出目标以及终端控制台，仅供内部使用，
+ 	获取调用者信息时只能由outputFile调用，其它地方调用时caller需要为false
+ @author
+ 	agent
+ @param
+	f					日志文件，为nil表示不写入文件
+	ll					日志等级
+	arg					要输出的内容
+	labels				路由标签，为nil表示没有
+	caller				是否获取调用者信息，为false时不输出文件和行号
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:51 	agent		创建，从outputFile拆分
+ 	2026-10-16_14:52 	agent		记录最近的主日志
+ 	2026-10-16_14:52 	agent		FATAL日志支持生成core文件
+ 	2026-10-16_14:53 	agent		FATAL日志生成运行时状态快照
+ 	2026-10-16_14:56 	agent		支持抓取会话日志
+ 	2026-10-16_15:05 	agent		支持转交给slog处理
+ 	2026-10-16_15:06 	agent		传递路由标签
+ 	2026-10-16_15:11 	agent		日志文件支持压缩超长内容
+ 	2026-10-16_15:12 	agent		日志文件支持输出校验值
+ 	2026-10-16_15:13 	agent		支持附带日志ID
+ 	2026-10-16_16:55 	agent		支持不获取调用者信息
+*******************************************************************************/
+func outputEntry(f *LOG_FILE, ll LEVEL, arg string, labels map[string]string, caller bool) {
+
+	//转交给slog处理
+	if h := slogHandler(); h != nil {
+		slogOutput(h, f, ll, arg)
+		fatalExit(ll, arg)
+		return
+	}
+
+	var start time.Time
+	if profiling() {
+		start = time.Now()
+	}
+
+	//公共字段，开启后附带日志ID
+	fields := fieldsText()
+	if logEntryID {
+		fields += " " + configField("id", NewID())
+	}
+
+	context := fmt.Sprintf("%s %s", ll, arg)
+	context = strings.TrimRight(context, "\n") + fields
+
+	//日志文件中的超长内容压缩输出
+	packed := ""
+	if logFileCompressLong > 0 && len(arg) > logFileCompressLong && f != nil {
+		body := compressLong(humanText(strings.TrimRight(arg, "\n"), logFileHumanize))
+		packed = fmt.Sprintf("%s %s", ll, body) + fields
+	}
+
+	//获取调用者信息，上一层为outputFile，再上一层为output，再上一层为日志接口，再上一层才是调用者
+	now := time.Now()
+	flags := logLevelFlags[FATAL]
+	if ll >= ALL && ll <= FATAL {
+		flags = logLevelFlags[ll]
+	}
+	if !caller {
+		flags &^= log.Lshortfile | log.Llongfile
+	}
+
+	file, line, fn := "", 0, ""
+	if flags&(log.Lshortfile|log.Llongfile) != 0 {
+		pc, _file, _line, ok := runtime.Caller(4)
+		if ok {
+			file, line = _file, _line
+			if logCallerFunc {
+				fn = funcName(pc)
+			}
 		} else {
-			context = fmt.Sprintf("==>[%04d/%02d/%02d_%02d:%02d:%02d.%06d] #%s:%d %s", now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second(), time.Duration(now.Nanosecond())/(time.Microsecond), file, line, args)
+			file, line = "???", 0
 		}
+	}
 
-		switch ll {
-		case DEBUG:
-			log.Println(SprintColor(context, STYLE_DEFAULT, CLR_DEFAULT, CLR_DEFAULT))
-		case INFO:
-			log.Println(SprintColor(context, STYLE_DEFAULT, CLR_DEFAULT, CLR_DEFAULT))
-		case WARN:
-			log.Println(SprintColor(context, STYLE_DEFAULT, CLR_YELLOW, CLR_DEFAULT))
-		case ERROR:
-			log.Println(SprintColor(context, STYLE_HIGHLIGHT, CLR_RED, CLR_DEFAULT))
-		case FATAL:
-			log.Println(SprintColor(context, STYLE_HIGHLIGHT, CLR_PURPLE, CLR_DEFAULT))
-		default:
-			log.Println(SprintColor(context, STYLE_DEFAULT, CLR_DEFAULT, CLR_DEFAULT))
+	//按照标准库log的格式生成日志行，文件和输出目标可以使用不同的时间精度
+	l := &logLine{flags: flags, t: now, file: file, line: line, fn: fn, context: context, packed: packed, labels: labels}
+
+	if f != nil {
+		b := l.bytes(lineFormat{precision: logFilePrecision, humanize: logFileHumanize, location: logFileLocation, packed: true, checksum: logFileChecksum})
+		f.writeLevel(ll, b)
+		if f == logFile {
+			crashTailAppend(b)
 		}
+	}
+	writeSinks(l)
+	captureWrite(l)
 
+	if logConsoleFormat == FORMAT_JSON {
+		consoleJSON(consoleTime(now), ll, file, line, fn, humanText(arg, logConsoleHumanize))
+	} else {
+		console(ll, file, line, fn, humanText(context, logConsoleHumanize))
+	}
+
+	if !start.IsZero() {
+		profileEntry.record(time.Since(start))
 	}
+
+	fatalExit(ll, arg)
+}
+
+/******************************************************************************
+ @brief
+ 	FATAL日志退出进程，退出前生成运行时状态快照并写入缓冲区中的日志，退出码见SetExitCode
+ @author
+ 	agent
+ @param
+	ll					日志等级
+	arg					要输出的内容
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:05 	agent		创建，从outputEntry拆分
+ 	2026-10-16_15:25 	agent		按错误分类设置退出码
+*******************************************************************************/
+func fatalExit(ll LEVEL, arg string) {
+	if ll == FATAL && logFatalExit {
+		writeMinidump(strings.TrimRight(arg, "\n"))
+		code := exitHint(fatalClass(arg), EXIT_FATAL, 1, strings.TrimRight(arg, "\n"))
+		Flush()
+		coreDump()
+		os.Exit(code)
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	按照标准库log的flag生成日志头，格式与log.Logger保持一致
+ @author
+ 	agent
+ @param
+	buf					输出缓冲
+	flags				标准库log的flag
+	t					日志时间
+	file				调用者文件
+	line				调用者行号
+	fn					调用者函数名，为空表示不显示
+ @return
+ 	[]byte				返回追加日志头后的缓冲
+ @history
+ 	2026-10-16_14:11 	agent		创建
+ 	2026-10-16_14:14 	agent		支持显示函数名
+*******************************************************************************/
+func formatHeader(buf []byte, flags int, t time.Time, file string, line int, fn string) []byte {
+
+	if flags&log.LUTC != 0 {
+		t = t.UTC()
+	}
+
+	if flags&log.Ldate != 0 {
+		buf = append(buf, fmt.Sprintf("%04d/%02d/%02d ", t.Year(), int(t.Month()), t.Day())...)
+	}
+
+	if flags&(log.Ltime|log.Lmicroseconds) != 0 {
+		buf = append(buf, fmt.Sprintf("%02d:%02d:%02d", t.Hour(), t.Minute(), t.Second())...)
+		if flags&log.Lmicroseconds != 0 {
+			buf = append(buf, fmt.Sprintf(".%06d", t.Nanosecond()/1000)...)
+		}
+		buf = append(buf, ' ')
+	}
+
+	if flags&(log.Lshortfile|log.Llongfile) != 0 {
+		if flags&log.Lshortfile != 0 {
+			file = shortFile(file)
+		}
+		if len(fn) > 0 {
+			buf = append(buf, fmt.Sprintf("%s:%d %s: ", file, line, fn)...)
+		} else {
+			buf = append(buf, fmt.Sprintf("%s:%d: ", file, line)...)
+		}
+	}
+
+	return buf
+}
+
+/******************************************************************************
+ @brief
+ 	获取调用者文件的短路径，优先去掉SetCallerTrimPrefix设置的前缀，否则只保留文件名
+ @author
+ 	agent
+ @param
+	file				调用者文件完整路径
+ @return
+ 	string				返回短路径
+ @history
+ 	2026-10-16_14:14 	agent		创建
+*******************************************************************************/
+func shortFile(file string) string {
+
+	for _, prefix := range logCallerTrim {
+		if strings.HasPrefix(file, prefix) {
+			return file[len(prefix):]
+		}
+	}
+
+	for i := len(file) - 1; i > 0; i-- {
+		if file[i] == '/' {
+			return file[i+1:]
+		}
+	}
+
+	return file
+}
+
+/******************************************************************************
+ @brief
+ 	获取函数名，去掉包路径中的目录部分
+ 		例如：github.com/baickl/server.(*Server).handleLogin 返回 server.(*Server).handleLogin
+ @author
+ 	agent
+ @param
+	pc					函数的程序计数器
+ @return
+ 	string				返回函数名
+ @history
+ 	2026-10-16_14:14 	agent		创建
+*******************************************************************************/
+func funcName(pc uintptr) string {
+
+	f := runtime.FuncForPC(pc)
+	if f == nil {
+		return "???"
+	}
+
+	name := f.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return name
+}
+
+/******************************************************************************
+ @brief
+ 	输出信息到终端控制台上
+ @author
+ 	chenzhiguo
+ @param
+	ll					日志等级
+	file				调用者文件，为空表示不显示调用者信息
+	line				调用者行号
+	fn					调用者函数名，为空表示不显示
+	args				要输出的内容
+ @return
+ 	-
+ @history
+ 	2015-05-16_10:52 	chenzhiguo		创建
+ 	2026-10-16_14:11 	agent		调用者信息由output统一获取
+ 	2026-10-16_14:14 	agent		支持不显示调用者信息
+ 	2026-10-16_14:14 	agent		支持显示函数名
+ 	2026-10-16_14:14 	agent		支持显示相对路径
+ 	2026-10-16_14:15 	agent		支持只给级别着色
+ 	2026-10-16_14:22 	agent		磁盘空间不足时始终显示
+ 	2026-10-16_14:51 	agent		支持设置时区
+*******************************************************************************/
+func console(ll LEVEL, file string, line int, fn string, args string) {
+	if logConsole || diskDegraded() {
+		file = shortFile(file)
+
+		now := consoleTime(time.Now())
+
+		prefix := ""
+		if len(logConsolePrefix) > 0 {
+			prefix = fmt.Sprintf(" @%s", logConsolePrefix)
+		}
+
+		//没有调用者信息时不显示
+		caller := ""
+		if len(file) > 0 {
+			caller = fmt.Sprintf(" #%s:%d", file, line)
+		}
+		if len(fn) > 0 {
+			caller += " " + fn
+		}
+
+		style, color := levelColor(ll)
+
+		//只给级别着色时，级别补齐宽度，内容使用终端默认颜色
+		if logConsoleStyle == CONSOLE_STYLE_LEVEL {
+			tag := SprintColor(fmt.Sprintf("%-5s", ll), style, color, CLR_DEFAULT)
+			args = strings.TrimPrefix(args, ll.String()+" ")
+			context := fmt.Sprintf("==>[%04d/%02d/%02d_%02d:%02d:%02d.%06d]%s%s %s %s", now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second(), time.Duration(now.Nanosecond())/(time.Microsecond), prefix, caller, tag, args)
+			consolePrintln(context)
+			return
+		}
+
+		context := fmt.Sprintf("==>[%04d/%02d/%02d_%02d:%02d:%02d.%06d]%s%s %s", now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second(), time.Duration(now.Nanosecond())/(time.Microsecond), prefix, caller, args)
+		consolePrintln(SprintColor(context, style, color, CLR_DEFAULT))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	转换为终端控制台使用的时区
+ @author
+ 	agent
+ @param
+	t					日志时间
+ @return
+ 	time.Time			返回转换后的时间
+ @history
+ 	2026-10-16_14:51 	agent		创建
+*******************************************************************************/
+func consoleTime(t time.Time) time.Time {
+	if loc := logConsoleLocation; loc != nil {
+		return t.In(loc)
+	}
+
+	return t
 }
 
 /******************************************************************************
@@ -1361,31 +2021,134 @@ func console(ll LEVEL, args string) {
  	-
  @history
  	2015-05-16_10:52 	chenzhiguo		创建
+ 	2026-10-16_14:47 	agent		记录到错误记录
 *******************************************************************************/
 func catchError() {
 	if err := recover(); err != nil {
 		log.Println("err", err)
+		diag("panic: %v", err)
 	}
 }
 
 /******************************************************************************
  @brief
- 	文件监控函数，循环检测文件是否需要重命名
+ 	启动文件监控，已经启动时先停止之前的监控
  @author
- 	chenzhiguo
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:49 	agent		创建
+*******************************************************************************/
+func startFileMonitor() {
+	monitorLock.Lock()
+	defer monitorLock.Unlock()
+
+	if monitorStop != nil {
+		close(monitorStop)
+	}
+
+	monitorStop = make(chan struct{})
+	go fileMonitor(monitorStop)
+}
+
+/******************************************************************************
+ @brief
+ 	停止文件监控
+ @author
+ 	agent
  @param
 	-
  @return
  	-
+ @history
+ 	2026-10-16_15:49 	agent		创建
+*******************************************************************************/
+func stopFileMonitor() {
+	monitorLock.Lock()
+	defer monitorLock.Unlock()
+
+	if monitorStop != nil {
+		close(monitorStop)
+		monitorStop = nil
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	停止文件监控，写入缓冲中的日志并关闭日志文件，之后需要重新Initialize，
+ 	用于测试之间重置日志状态
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:49 	agent		创建
+*******************************************************************************/
+func shutdown() {
+	stopFileMonitor()
+	Flush()
+
+	if f := logFile; f != nil {
+		f.Lock()
+		old := f.swap(&fileHandle{})
+		f.Unlock()
+
+		if old != nil {
+			old.close()
+		}
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	文件监控函数，循环检测文件是否需要重命名，收到停止信号后退出
+ @author
+ 	chenzhiguo
+ @param
+	stop				停止信号
+ @return
+ 	-
  @history
  	2015-05-16_10:52 	chenzhiguo		创建
+ 	2026-10-16_15:49 	agent		支持停止
+ 	2026-10-16_15:50 	agent		按时间切分的边界到达时立即检查
 *******************************************************************************/
-func fileMonitor() {
+func fileMonitor(stop chan struct{}) {
 	timer := time.NewTicker(10 * time.Second)
+	defer timer.Stop()
+
+	//定时检查之间到达切分边界时，不等待下一次定时检查
+	next := rotateBoundary()
+	boundary := time.NewTimer(time.Until(next))
+	defer boundary.Stop()
+
 	for {
 		select {
 		case <-timer.C:
 			fileCheck()
+
+			//切分间隔或时区改变后重新计算边界
+			if b := rotateBoundary(); !b.Equal(next) {
+				if !boundary.Stop() {
+					select {
+					case <-boundary.C:
+					default:
+					}
+				}
+				next = b
+				boundary.Reset(time.Until(next))
+			}
+		case <-boundary.C:
+			fileCheck()
+			next = rotateBoundary()
+			boundary.Reset(time.Until(next))
+		case <-stop:
+			return
 		}
 	}
 }
@@ -1401,13 +2164,49 @@ func fileMonitor() {
  	-
  @history
  	2015-05-16_10:52 	chenzhiguo		创建
+ 	2026-10-16_14:25 	agent		同时检查分类日志文件
+ 	2026-10-16_15:00 	agent		同时检查租户日志文件
+ 	2026-10-16_15:05 	agent		交接给新进程后不再检查
 *******************************************************************************/
 func fileCheck() {
 
 	defer catchError()
-	if logFile != nil && logFile.isMustRename() {
-		logFile.Lock()
-		defer logFile.Unlock()
-		logFile.rename()
+
+	//已经交接给新进程，由新进程切分
+	if handedOver() {
+		return
+	}
+
+	if logFile != nil {
+		logFile.check()
+	}
+
+	for _, f := range categoryFiles() {
+		f.check()
+	}
+
+	for _, f := range tenantFiles() {
+		f.check()
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	检查文件是否需要重命名，如果需要，那么执行重命名逻辑
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:25 	agent		创建
+ 	2026-10-16_14:43 	agent		没有文件句柄时不检查
+*******************************************************************************/
+func (f *LOG_FILE) check() {
+	if f.current() != nil && f.isMustRename() {
+		f.Lock()
+		defer f.Unlock()
+		f.rename()
 	}
 }
@@ -0,0 +1,173 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+const (
+	longMarker = "z64:" //压缩后的日志内容前缀，后面是deflate压缩后不带填充的base64
+)
+
+var (
+	logFileCompressLong int //日志文件中超过该长度的日志内容压缩后输出，0表示不压缩
+
+	lineLong = regexp.MustCompile(`(^|\s)` + longMarker + `([A-Za-z0-9+/]+)`)
+)
+
+/******************************************************************************
+ @brief
+ 	设置日志文件中超长日志内容的压缩，内容超过threshold字节时使用deflate压缩并以base64
+ 	输出为一个z64:开头的单词，偶尔输出的大数据包不会让日志文件变得过大。
+ 	公共字段不压缩，终端控制台和扩展输出目标仍然输出原文，
+ 	ParseLine会自动还原，查看文件时可以使用ExpandReader或logger-expand工具
+ 		例：
+ 			logger.SetFileCompressLong(16 * 1024)
+
+ 		日志文件：INFO z64:jM5BCoMwEIX... server=login01
+ @author
+ 	agent
+ @param
+	threshold			压缩阈值，小于等于0表示不压缩
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:11 	agent		创建
+*******************************************************************************/
+func SetFileCompressLong(threshold int) {
+	if threshold < 0 {
+		threshold = 0
+	}
+	logFileCompressLong = threshold
+}
+
+/******************************************************************************
+ @brief
+ 	压缩日志内容
+ @author
+ 	agent
+ @param
+	msg					日志内容
+ @return
+ 	string				返回z64:开头的压缩内容，压缩失败时返回原文
+ @history
+ 	2026-10-16_15:11 	agent		创建
+*******************************************************************************/
+func compressLong(msg string) string {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestSpeed)
+	if err != nil {
+		return msg
+	}
+
+	io.WriteString(w, msg)
+	if err := w.Close(); err != nil {
+		return msg
+	}
+
+	return longMarker + base64.RawStdEncoding.EncodeToString(buf.Bytes())
+}
+
+/******************************************************************************
+ @brief
+ 	还原压缩的日志内容
+ @author
+ 	agent
+ @param
+	data				z64:之后的base64内容
+ @return
+ 	string				返回原文
+ 	bool				内容无效时返回false
+ @history
+ 	2026-10-16_15:11 	agent		创建
+*******************************************************************************/
+func expandLong(data string) (string, bool) {
+	raw, err := base64.RawStdEncoding.DecodeString(data)
+	if err != nil {
+		return "", false
+	}
+
+	msg, err := ioutil.ReadAll(flate.NewReader(bytes.NewReader(raw)))
+	if err != nil {
+		return "", false
+	}
+
+	return string(msg), true
+}
+
+/******************************************************************************
+ @brief
+ 	还原日志行中压缩的日志内容，没有压缩内容或内容无效时原样返回，
+ 	还原后的内容可能包含换行
+ @author
+ 	agent
+ @param
+	s					日志行
+ @return
+ 	string				返回还原后的日志行
+ @history
+ 	2026-10-16_15:11 	agent		创建
+*******************************************************************************/
+func ExpandLine(s string) string {
+	if !strings.Contains(s, longMarker) {
+		return s
+	}
+
+	m := lineLong.FindStringSubmatchIndex(s)
+	if m == nil {
+		return s
+	}
+
+	msg, ok := expandLong(s[m[4]:m[5]])
+	if !ok {
+		return s
+	}
+
+	return s[:m[3]] + msg + s[m[1]:]
+}
+
+/******************************************************************************
+ @brief
+ 	创建按行还原压缩日志内容的Reader
+ 		例：
+ 			f, _ := os.Open("./log/2026-10-17/gameserver.18_00_00.log")
+ 			io.Copy(os.Stdout, logger.ExpandReader(f))
+ @author
+ 	agent
+ @param
+	r					日志文件内容
+ @return
+ 	io.Reader			返回还原后的内容
+ @history
+ 	2026-10-16_15:11 	agent		创建
+*******************************************************************************/
+func ExpandReader(r io.Reader) io.Reader {
+	pr, pw := io.Pipe()
+
+	go func() {
+		br := bufio.NewReader(r)
+		for {
+			line, err := br.ReadString('\n')
+			if len(line) > 0 {
+				if _, werr := io.WriteString(pw, ExpandLine(line)); werr != nil {
+					return
+				}
+			}
+			if err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+				pw.CloseWithError(err)
+				return
+			}
+		}
+	}()
+
+	return pr
+}
@@ -0,0 +1,168 @@
+package logger
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	manifestName = "MANIFEST" //校验清单文件名
+)
+
+var (
+	logManifest  bool       //切分后是否记录校验清单
+	manifestLock sync.Mutex //校验清单线程锁，多个分类日志共用日期目录
+)
+
+/******************************************************************************
+ @brief
+ 	设置切分后是否记录校验清单，每个切分后的文件会将SHA-256和大小追加到所在日期目录
+ 	下的MANIFEST文件中，归档后可以通过VerifyManifest校验完整性
+ @author
+ 	agent
+ @param
+	isManifest			是否记录校验清单
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:28 	agent		创建
+*******************************************************************************/
+func SetManifest(isManifest bool) {
+	logManifest = isManifest
+}
+
+/******************************************************************************
+ @brief
+ 	校验目录下MANIFEST中记录的文件，文件缺失、大小或SHA-256不一致都会返回错误
+ 		例：
+ 			if err := logger.VerifyManifest("./logs/2026-10-16"); err != nil {
+ 				fmt.Println(err)
+ 			}
+ @author
+ 	agent
+ @param
+	dir					日期目录
+ @return
+ 	error				校验失败时返回错误信息，包含所有不一致的文件
+ @history
+ 	2026-10-16_14:28 	agent		创建
+*******************************************************************************/
+func VerifyManifest(dir string) error {
+
+	file, err := logStorage.OpenFile(filepath.Join(dir, manifestName), os.O_RDONLY, 0)
+	if err != nil {
+		return fmt.Errorf("logger: open manifest: %v", err)
+	}
+	defer file.Close()
+
+	failed := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		parts := strings.Fields(scanner.Text())
+		if len(parts) != 3 {
+			continue
+		}
+
+		size, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: invalid size %q", parts[2], parts[1]))
+			continue
+		}
+
+		sum, n, err := fileSum(filepath.Join(dir, parts[2]))
+		switch {
+		case err != nil:
+			failed = append(failed, fmt.Sprintf("%s: %v", parts[2], err))
+		case n != size:
+			failed = append(failed, fmt.Sprintf("%s: size %d, expected %d", parts[2], n, size))
+		case sum != parts[0]:
+			failed = append(failed, fmt.Sprintf("%s: sha256 mismatch", parts[2]))
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("logger: read manifest: %v", err)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("logger: manifest verify failed: %s", strings.Join(failed, "; "))
+	}
+
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	将切分后的文件追加到所在日期目录的校验清单
+ @author
+ 	agent
+ @param
+	fn					文件路径
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:28 	agent		创建
+ 	2026-10-16_14:47 	agent		记录写入失败
+*******************************************************************************/
+func manifestAppend(fn string) {
+	if !logManifest || len(fn) == 0 {
+		return
+	}
+
+	sum, size, err := fileSum(fn)
+	if err != nil {
+		diag("manifest %s: %v", fn, err)
+		return
+	}
+
+	manifestLock.Lock()
+	defer manifestLock.Unlock()
+
+	file, err := logStorage.OpenFile(filepath.Join(filepath.Dir(fn), manifestName), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		diag("manifest %s: %v", fn, err)
+		return
+	}
+	defer file.Close()
+
+	fmt.Fprintf(file, "%s %d %s\n", sum, size, filepath.Base(fn))
+}
+
+/******************************************************************************
+ @brief
+ 	计算文件的SHA-256和大小
+ @author
+ 	agent
+ @param
+	fn					文件路径
+ @return
+ 	string				返回十六进制的SHA-256
+ 	int64				返回文件大小
+ 	error				读取失败时返回错误信息
+ @history
+ 	2026-10-16_14:28 	agent		创建
+*******************************************************************************/
+func fileSum(fn string) (string, int64, error) {
+
+	file, err := logStorage.OpenFile(fn, os.O_RDONLY, 0)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, file)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
@@ -0,0 +1,430 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	内存文件系统存储，日志文件只保存在内存中，用于测试或者不需要落盘的场景
+ 		例：
+ 			fs := logger.MemStorage()
+ 			logger.SetStorage(fs)
+ 			logger.Initialize("/logs", "LoginServer")
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:42 	agent		创建
+*******************************************************************************/
+type MEM_STORAGE struct {
+	sync.Mutex                     //文件表锁
+	nodes      map[string]*memNode //路径对应的文件或目录
+}
+
+/******************************************************************************
+ @brief
+ 	内存文件系统中的文件或目录
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:42 	agent		创建
+*******************************************************************************/
+type memNode struct {
+	name    string      //文件名
+	data    []byte      //文件内容
+	mode    os.FileMode //文件权限，目录带os.ModeDir
+	modTime time.Time   //最后修改时间
+}
+
+/******************************************************************************
+ @brief
+ 	内存文件系统中打开的文件
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:42 	agent		创建
+*******************************************************************************/
+type memFile struct {
+	s      *MEM_STORAGE //所属的存储
+	node   *memNode     //文件节点
+	flag   int          //打开方式
+	offset int64        //读取偏移
+	closed bool         //是否已经关闭
+}
+
+/******************************************************************************
+ @brief
+ 	创建内存文件系统存储
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	*MEM_STORAGE		返回内存文件系统存储，可以传给SetStorage
+ @history
+ 	2026-10-16_15:42 	agent		创建
+*******************************************************************************/
+func MemStorage() *MEM_STORAGE {
+	return &MEM_STORAGE{nodes: map[string]*memNode{}}
+}
+
+/******************************************************************************
+ @brief
+ 	打开文件，父目录必须已经存在
+ @author
+ 	agent
+ @see
+ 	os.OpenFile
+ @history
+ 	2026-10-16_15:42 	agent		创建
+*******************************************************************************/
+func (s *MEM_STORAGE) OpenFile(name string, flag int, perm os.FileMode) (STORAGE_FILE, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	name = filepath.Clean(name)
+	n, ok := s.nodes[name]
+	switch {
+	case ok && n.mode.IsDir():
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrInvalid}
+	case ok && flag&os.O_CREATE != 0 && flag&os.O_EXCL != 0:
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrExist}
+	case !ok && flag&os.O_CREATE == 0:
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	case !ok && !s.isDir(filepath.Dir(name)):
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+
+	writable := flag&(os.O_WRONLY|os.O_RDWR) != 0
+	if ok && writable && n.mode&0200 == 0 {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrPermission}
+	}
+
+	if !ok {
+		n = &memNode{name: filepath.Base(name), mode: perm.Perm(), modTime: time.Now()}
+		s.nodes[name] = n
+	}
+	if writable && flag&os.O_TRUNC != 0 {
+		n.data = nil
+		n.modTime = time.Now()
+	}
+
+	return &memFile{s: s, node: n, flag: flag}, nil
+}
+
+/******************************************************************************
+ @brief
+ 	创建目录
+ @author
+ 	agent
+ @see
+ 	os.MkdirAll
+ @history
+ 	2026-10-16_15:42 	agent		创建
+*******************************************************************************/
+func (s *MEM_STORAGE) MkdirAll(path string, perm os.FileMode) error {
+	s.Lock()
+	defer s.Unlock()
+
+	path = filepath.Clean(path)
+	for p := path; ; p = filepath.Dir(p) {
+		if n, ok := s.nodes[p]; ok {
+			if !n.mode.IsDir() {
+				return &os.PathError{Op: "mkdir", Path: p, Err: os.ErrExist}
+			}
+		} else {
+			s.nodes[p] = &memNode{name: filepath.Base(p), mode: os.ModeDir | perm.Perm(), modTime: time.Now()}
+		}
+
+		if filepath.Dir(p) == p {
+			return nil
+		}
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	删除文件或空目录
+ @author
+ 	agent
+ @see
+ 	os.Remove
+ @history
+ 	2026-10-16_15:42 	agent		创建
+*******************************************************************************/
+func (s *MEM_STORAGE) Remove(name string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	name = filepath.Clean(name)
+	n, ok := s.nodes[name]
+	if !ok {
+		return &os.PathError{Op: "remove", Path: name, Err: os.ErrNotExist}
+	}
+
+	if n.mode.IsDir() {
+		prefix := name + string(filepath.Separator)
+		for p := range s.nodes {
+			if strings.HasPrefix(p, prefix) {
+				return &os.PathError{Op: "remove", Path: name, Err: os.ErrExist}
+			}
+		}
+	}
+
+	delete(s.nodes, name)
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	获取文件信息
+ @author
+ 	agent
+ @see
+ 	os.Stat
+ @history
+ 	2026-10-16_15:42 	agent		创建
+*******************************************************************************/
+func (s *MEM_STORAGE) Stat(name string) (os.FileInfo, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	name = filepath.Clean(name)
+	n, ok := s.nodes[name]
+	if !ok {
+		return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+	}
+
+	return n.info(), nil
+}
+
+/******************************************************************************
+ @brief
+ 	修改文件权限
+ @author
+ 	agent
+ @see
+ 	os.Chmod
+ @history
+ 	2026-10-16_15:42 	agent		创建
+*******************************************************************************/
+func (s *MEM_STORAGE) Chmod(name string, mode os.FileMode) error {
+	s.Lock()
+	defer s.Unlock()
+
+	name = filepath.Clean(name)
+	n, ok := s.nodes[name]
+	if !ok {
+		return &os.PathError{Op: "chmod", Path: name, Err: os.ErrNotExist}
+	}
+
+	n.mode = n.mode&os.ModeDir | mode.Perm()
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	匹配文件，返回结果按路径排序
+ @author
+ 	agent
+ @see
+ 	filepath.Glob
+ @history
+ 	2026-10-16_15:42 	agent		创建
+*******************************************************************************/
+func (s *MEM_STORAGE) Glob(pattern string) ([]string, error) {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	pattern = filepath.Clean(pattern)
+	var matches []string
+	for p := range s.nodes {
+		if ok, _ := filepath.Match(pattern, p); ok {
+			matches = append(matches, p)
+		}
+	}
+
+	sort.Strings(matches)
+	return matches, nil
+}
+
+/******************************************************************************
+ @brief
+ 	判断路径是否为已经存在的目录，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	path				目录路径
+ @return
+ 	bool				返回true表示目录存在
+ @history
+ 	2026-10-16_15:42 	agent		创建
+*******************************************************************************/
+func (s *MEM_STORAGE) isDir(path string) bool {
+	if path == "." || filepath.Dir(path) == path {
+		return true
+	}
+
+	n, ok := s.nodes[path]
+	return ok && n.mode.IsDir()
+}
+
+/******************************************************************************
+ @brief
+ 	生成文件信息快照，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	os.FileInfo			返回文件信息
+ @history
+ 	2026-10-16_15:42 	agent		创建
+*******************************************************************************/
+func (n *memNode) info() os.FileInfo {
+	return memFileInfo{name: n.name, size: int64(len(n.data)), mode: n.mode, modTime: n.modTime}
+}
+
+/******************************************************************************
+ @brief
+ 	读取文件内容，从上次读取的位置继续
+ @author
+ 	agent
+ @see
+ 	io.Reader
+ @history
+ 	2026-10-16_15:42 	agent		创建
+*******************************************************************************/
+func (f *memFile) Read(p []byte) (int, error) {
+	n, err := f.ReadAt(p, f.offset)
+	f.offset += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+
+	return n, err
+}
+
+/******************************************************************************
+ @brief
+ 	从指定偏移读取文件内容
+ @author
+ 	agent
+ @see
+ 	io.ReaderAt
+ @history
+ 	2026-10-16_15:42 	agent		创建
+*******************************************************************************/
+func (f *memFile) ReadAt(p []byte, off int64) (int, error) {
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+
+	f.s.Lock()
+	defer f.s.Unlock()
+
+	if off >= int64(len(f.node.data)) {
+		return 0, io.EOF
+	}
+
+	n := copy(p, f.node.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+/******************************************************************************
+ @brief
+ 	写入文件内容，不支持随机写入，总是追加到文件末尾
+ @author
+ 	agent
+ @see
+ 	io.Writer
+ @history
+ 	2026-10-16_15:42 	agent		创建
+*******************************************************************************/
+func (f *memFile) Write(p []byte) (int, error) {
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	if f.flag&(os.O_WRONLY|os.O_RDWR) == 0 {
+		return 0, &os.PathError{Op: "write", Path: f.node.name, Err: os.ErrPermission}
+	}
+
+	f.s.Lock()
+	defer f.s.Unlock()
+
+	f.node.data = append(f.node.data, p...)
+	f.node.modTime = time.Now()
+	return len(p), nil
+}
+
+/******************************************************************************
+ @brief
+ 	关闭文件
+ @author
+ 	agent
+ @see
+ 	io.Closer
+ @history
+ 	2026-10-16_15:42 	agent		创建
+*******************************************************************************/
+func (f *memFile) Close() error {
+	if f.closed {
+		return os.ErrClosed
+	}
+
+	f.closed = true
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	获取文件信息
+ @author
+ 	agent
+ @see
+ 	os.File.Stat
+ @history
+ 	2026-10-16_15:42 	agent		创建
+*******************************************************************************/
+func (f *memFile) Stat() (os.FileInfo, error) {
+	f.s.Lock()
+	defer f.s.Unlock()
+
+	return f.node.info(), nil
+}
+
+/******************************************************************************
+ @brief
+ 	内存文件信息
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:42 	agent		创建
+*******************************************************************************/
+type memFileInfo struct {
+	name    string      //文件名
+	size    int64       //文件大小
+	mode    os.FileMode //文件权限
+	modTime time.Time   //最后修改时间
+}
+
+func (fi memFileInfo) Name() string       { return fi.name }
+func (fi memFileInfo) Size() int64        { return fi.size }
+func (fi memFileInfo) Mode() os.FileMode  { return fi.mode }
+func (fi memFileInfo) ModTime() time.Time { return fi.modTime }
+func (fi memFileInfo) IsDir() bool        { return fi.mode.IsDir() }
+func (fi memFileInfo) Sys() interface{}   { return nil }
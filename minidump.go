@@ -0,0 +1,190 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	模块信息
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:53 	agent		创建
+*******************************************************************************/
+type MODULE_INFO struct {
+	Path    string `json:"path"`              //模块路径
+	Version string `json:"version,omitempty"` //模块版本
+	Sum     string `json:"sum,omitempty"`     //模块校验和
+	Replace string `json:"replace,omitempty"` //替换后的模块路径
+}
+
+/******************************************************************************
+ @brief
+ 	运行时状态快照，以JSON格式保存，技术支持工具可以向用户索取后分析
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:53 	agent		创建
+*******************************************************************************/
+type MINIDUMP struct {
+	Version    int               `json:"v"`                  //格式版本，与FORMAT_VERSION一致
+	Time       time.Time         `json:"time"`               //生成时间
+	Reason     string            `json:"reason"`             //生成原因，例如panic的值
+	GoVersion  string            `json:"go_version"`         //Go版本
+	OS         string            `json:"os"`                 //操作系统
+	Arch       string            `json:"arch"`               //CPU架构
+	NumCPU     int               `json:"num_cpu"`            //CPU数量
+	GOMAXPROCS int               `json:"gomaxprocs"`         //GOMAXPROCS
+	Goroutines int               `json:"goroutines"`         //协程数量
+	CgoEnabled bool              `json:"cgo_enabled"`        //编译时是否开启cgo
+	CgoCalls   int64             `json:"cgo_calls"`          //cgo调用次数
+	HeapAlloc  uint64            `json:"heap_alloc"`         //堆上已分配的字节数
+	HeapSys    uint64            `json:"heap_sys"`           //堆从系统申请的字节数
+	NumGC      uint32            `json:"num_gc"`             //GC次数
+	Main       MODULE_INFO       `json:"main"`               //主模块
+	Modules    []MODULE_INFO     `json:"modules,omitempty"`  //依赖模块列表
+	Settings   map[string]string `json:"settings,omitempty"` //编译设置，例如-ldflags、vcs.revision
+}
+
+var (
+	logMinidump bool //异常和FATAL日志是否生成运行时状态快照
+)
+
+/******************************************************************************
+ @brief
+ 	设置CatchException捕获到异常、或者输出FATAL日志退出进程时，是否在异常目录中
+ 	生成运行时状态快照minidump.HH_MM_SS.json，与文本异常报告放在一起
+ 		例：
+ 			logger.SetMinidump(true)
+ @author
+ 	agent
+ @param
+	isMinidump			是否生成
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:53 	agent		创建
+*******************************************************************************/
+func SetMinidump(isMinidump bool) {
+	logMinidump = isMinidump
+}
+
+/******************************************************************************
+ @brief
+ 	生成当前的运行时状态快照，包括协程数量、编译信息、模块列表、cgo状态等，
+ 	也可以在技术支持接口中调用
+ @author
+ 	agent
+ @param
+	reason				生成原因
+ @return
+ 	MINIDUMP			返回运行时状态快照
+ @history
+ 	2026-10-16_14:53 	agent		创建
+*******************************************************************************/
+func Minidump(reason string) MINIDUMP {
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	dump := MINIDUMP{
+		Version:    FORMAT_VERSION,
+		Time:       time.Now(),
+		Reason:     reason,
+		GoVersion:  runtime.Version(),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		NumCPU:     runtime.NumCPU(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		Goroutines: runtime.NumGoroutine(),
+		CgoCalls:   runtime.NumCgoCall(),
+		HeapAlloc:  mem.HeapAlloc,
+		HeapSys:    mem.HeapSys,
+		NumGC:      mem.NumGC,
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return dump
+	}
+
+	dump.Main = moduleInfo(&info.Main)
+	for _, m := range info.Deps {
+		dump.Modules = append(dump.Modules, moduleInfo(m))
+	}
+
+	dump.Settings = map[string]string{}
+	for _, s := range info.Settings {
+		dump.Settings[s.Key] = s.Value
+		if s.Key == "CGO_ENABLED" {
+			dump.CgoEnabled = s.Value == "1"
+		}
+	}
+
+	return dump
+}
+
+/******************************************************************************
+ @brief
+ 	转换模块信息
+ @author
+ 	agent
+ @param
+	m					编译信息中的模块
+ @return
+ 	MODULE_INFO			返回模块信息
+ @history
+ 	2026-10-16_14:53 	agent		创建
+*******************************************************************************/
+func moduleInfo(m *debug.Module) MODULE_INFO {
+	info := MODULE_INFO{Path: m.Path, Version: m.Version, Sum: m.Sum}
+	if m.Replace != nil {
+		info.Replace = m.Replace.Path
+	}
+
+	return info
+}
+
+/******************************************************************************
+ @brief
+ 	开启了运行时状态快照时写入异常目录，没有开启时直接返回
+ @author
+ 	agent
+ @param
+	reason				生成原因
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:53 	agent		创建
+ 	2026-10-16_15:42 	agent		通过日志存储写入
+*******************************************************************************/
+func writeMinidump(reason string) {
+	if !logMinidump {
+		return
+	}
+
+	data, err := json.MarshalIndent(Minidump(reason), "", "\t")
+	if err != nil {
+		diag("minidump: %v", err)
+		return
+	}
+
+	now := time.Now()
+	dir := fmt.Sprintf("./exceptions/%04d-%02d-%02d/", now.Year(), int(now.Month()), now.Day())
+	logStorage.MkdirAll(dir, os.ModePerm)
+
+	fn := fmt.Sprintf("%sminidump.%02d_%02d_%02d.json", dir, now.Hour(), now.Minute(), now.Second())
+	for n := 1; storageExist(fn); n++ {
+		fn = fmt.Sprintf("%sminidump.%02d_%02d_%02d_%d.json", dir, now.Hour(), now.Minute(), now.Second(), n)
+	}
+
+	if err := storageWriteFile(fn, append(data, '\n'), 0644); err != nil {
+		diag("minidump %s: %v", fn, err)
+	}
+}
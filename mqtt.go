@@ -0,0 +1,393 @@
+package logger
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	MQTT输出目标配置
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:17 	agent		创建
+*******************************************************************************/
+type MQTT_CONFIG struct {
+	Broker   string      //服务器地址host:port
+	ClientID string      //客户端ID，为空时使用名称、主机名和进程ID
+	Username string      //用户名，为空表示不认证
+	Password string      //密码
+	Topic    string      //主题模板，可以使用{name}、{level}、{host}，为空时为logs/{name}/{level}
+	Name     string      //{name}的值，为空时使用程序名
+	QoS      int         //服务质量，0或1，1时等待服务器确认
+	TLS      *TLS_CONFIG //TLS配置，为nil时使用明文传输
+}
+
+/******************************************************************************
+ @brief
+ 	MQTT输出目标类结构，每条日志发布为一条消息，断线后下次写入时自动重连，
+ 	只实现了发布日志需要的MQTT 3.1.1协议子集，不依赖第三方库，可以配合AddSink使用
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:17 	agent		创建
+*******************************************************************************/
+type MQTT_WRITER struct {
+	sync.Mutex               //线程锁
+	cfg        MQTT_CONFIG   //配置
+	tlsConfig  *tls.Config   //TLS配置，为nil时使用明文传输
+	conn       net.Conn      //当前连接
+	reader     *bufio.Reader //当前连接的读取缓冲
+	packetID   uint16        //上一条QoS 1消息的报文标识符
+}
+
+/******************************************************************************
+ @brief
+ 	创建MQTT输出目标，边缘设备只允许MQTT出口时使用
+ 		例：
+ 			w, err := logger.NewMQTTWriter(logger.MQTT_CONFIG{
+ 				Broker: "10.0.0.1:1883",
+ 				Topic:  "edge/{host}/{level}",
+ 				QoS:    1,
+ 			})
+ 			if err == nil {
+ 				logger.AddSink("mqtt", w, logger.BUFFER_INTERVAL, 64*1024, 5*time.Second)
+ 			}
+ @author
+ 	agent
+ @param
+	cfg					配置
+ @return
+ 	*MQTT_WRITER		返回输出目标
+ 	error				配置无效或加载证书失败时返回错误信息
+ @history
+ 	2026-10-16_15:17 	agent		创建
+*******************************************************************************/
+func NewMQTTWriter(cfg MQTT_CONFIG) (*MQTT_WRITER, error) {
+	if len(cfg.Broker) == 0 {
+		return nil, fmt.Errorf("logger: empty mqtt broker")
+	}
+
+	if cfg.QoS < 0 || cfg.QoS > 1 {
+		return nil, fmt.Errorf("logger: unsupported mqtt qos %d", cfg.QoS)
+	}
+
+	if len(cfg.Name) == 0 {
+		cfg.Name = strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+	}
+	if len(cfg.Topic) == 0 {
+		cfg.Topic = "logs/{name}/{level}"
+	}
+	if len(cfg.ClientID) == 0 {
+		cfg.ClientID = fmt.Sprintf("%s-%s-%d", cfg.Name, hostname(), os.Getpid())
+	}
+
+	w := &MQTT_WRITER{cfg: cfg}
+	if cfg.TLS != nil {
+		tlsConfig, err := cfg.TLS.build()
+		if err != nil {
+			return nil, err
+		}
+		w.tlsConfig = tlsConfig
+	}
+
+	return w, nil
+}
+
+/******************************************************************************
+ @brief
+ 	将日志行逐条发布到对应主题
+ @author
+ 	agent
+ @param
+	b					要写入的日志行
+ @return
+ 	int					返回写入的字节数
+ 	error				发布失败时返回错误信息
+ @history
+ 	2026-10-16_15:17 	agent		创建
+*******************************************************************************/
+func (w *MQTT_WRITER) Write(b []byte) (int, error) {
+	w.Lock()
+	defer w.Unlock()
+
+	if w.conn == nil {
+		if err := w.connect(); err != nil {
+			return 0, err
+		}
+	}
+
+	entries, raws := parseEntries(b)
+	for i, e := range entries {
+		if err := w.publish(w.topic(e.Level), raws[i]); err != nil {
+			w.conn.Close()
+			w.conn = nil
+			return 0, err
+		}
+	}
+
+	return len(b), nil
+}
+
+/******************************************************************************
+ @brief
+ 	断开连接
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	error				关闭失败时返回错误信息
+ @history
+ 	2026-10-16_15:17 	agent		创建
+*******************************************************************************/
+func (w *MQTT_WRITER) Close() error {
+	w.Lock()
+	defer w.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+
+	w.conn.SetWriteDeadline(time.Now().Add(netWriteTimeout))
+	w.conn.Write([]byte{0xe0, 0x00}) //DISCONNECT
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+/******************************************************************************
+ @brief
+ 	生成日志对应的主题
+ @author
+ 	agent
+ @param
+	ll					日志等级
+ @return
+ 	string				返回主题
+ @history
+ 	2026-10-16_15:17 	agent		创建
+*******************************************************************************/
+func (w *MQTT_WRITER) topic(ll LEVEL) string {
+	return strings.NewReplacer(
+		"{name}", w.cfg.Name,
+		"{level}", strings.ToLower(ll.String()),
+		"{host}", hostname(),
+	).Replace(w.cfg.Topic)
+}
+
+/******************************************************************************
+ @brief
+ 	建立连接并完成MQTT握手，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	error				连接或握手失败时返回错误信息
+ @history
+ 	2026-10-16_15:17 	agent		创建
+*******************************************************************************/
+func (w *MQTT_WRITER) connect() error {
+
+	dialer := &net.Dialer{Timeout: netDialTimeout}
+	var conn net.Conn
+	var err error
+	if w.tlsConfig != nil {
+		conn, err = tls.DialWithDialer(dialer, "tcp", w.cfg.Broker, w.tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", w.cfg.Broker)
+	}
+	if err != nil {
+		return err
+	}
+
+	//CONNECT：协议名MQTT、协议级别4、清除会话、不使用心跳
+	flags := byte(0x02)
+	payload := mqttString(nil, w.cfg.ClientID)
+	if len(w.cfg.Username) > 0 {
+		flags |= 0x80
+		payload = mqttString(payload, w.cfg.Username)
+		if len(w.cfg.Password) > 0 {
+			flags |= 0x40
+			payload = mqttString(payload, w.cfg.Password)
+		}
+	}
+
+	body := mqttString(nil, "MQTT")
+	body = append(body, 4, flags, 0, 0)
+	body = append(body, payload...)
+
+	conn.SetDeadline(time.Now().Add(netWriteTimeout))
+	if _, err := conn.Write(mqttPacket(0x10, body)); err != nil {
+		conn.Close()
+		return err
+	}
+
+	reader := bufio.NewReader(conn)
+	kind, ack, err := mqttRead(reader)
+	if err == nil && (kind != 0x20 || len(ack) != 2) {
+		err = fmt.Errorf("logger: mqtt unexpected packet 0x%02x", kind)
+	}
+	if err == nil && ack[1] != 0 {
+		err = fmt.Errorf("logger: mqtt connection refused, code %d", ack[1])
+	}
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	conn.SetDeadline(time.Time{})
+	w.conn, w.reader = conn, reader
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	发布一条消息，QoS为1时等待服务器确认，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	topic				主题
+	msg					消息内容
+ @return
+ 	error				发布失败时返回错误信息
+ @history
+ 	2026-10-16_15:17 	agent		创建
+*******************************************************************************/
+func (w *MQTT_WRITER) publish(topic, msg string) error {
+
+	body := mqttString(nil, topic)
+	kind := byte(0x30)
+	if w.cfg.QoS == 1 {
+		w.packetID++
+		if w.packetID == 0 {
+			w.packetID = 1
+		}
+		kind |= 0x02
+		body = append(body, byte(w.packetID>>8), byte(w.packetID))
+	}
+	body = append(body, msg...)
+
+	w.conn.SetWriteDeadline(time.Now().Add(netWriteTimeout))
+	if _, err := w.conn.Write(mqttPacket(kind, body)); err != nil {
+		return err
+	}
+
+	if w.cfg.QoS == 0 {
+		return nil
+	}
+
+	//等待PUBACK，忽略服务器发来的其它报文
+	w.conn.SetReadDeadline(time.Now().Add(netWriteTimeout))
+	defer w.conn.SetReadDeadline(time.Time{})
+	for {
+		kind, ack, err := mqttRead(w.reader)
+		if err != nil {
+			return err
+		}
+		if kind&0xf0 == 0x40 && len(ack) >= 2 && binary.BigEndian.Uint16(ack) == w.packetID {
+			return nil
+		}
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	追加MQTT格式的字符串，两个字节的长度加内容
+ @author
+ 	agent
+ @param
+	buf					输出缓冲
+	s					字符串
+ @return
+ 	[]byte				返回追加后的缓冲
+ @history
+ 	2026-10-16_15:17 	agent		创建
+*******************************************************************************/
+func mqttString(buf []byte, s string) []byte {
+	buf = append(buf, byte(len(s)>>8), byte(len(s)))
+	return append(buf, s...)
+}
+
+/******************************************************************************
+ @brief
+ 	生成MQTT报文，固定报头加可变长度编码的剩余长度
+ @author
+ 	agent
+ @param
+	kind				报文类型和标志
+	body				可变报头和有效载荷
+ @return
+ 	[]byte				返回报文
+ @history
+ 	2026-10-16_15:17 	agent		创建
+*******************************************************************************/
+func mqttPacket(kind byte, body []byte) []byte {
+	buf := []byte{kind}
+	n := len(body)
+	for {
+		c := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			c |= 0x80
+		}
+		buf = append(buf, c)
+		if n == 0 {
+			break
+		}
+	}
+
+	return append(buf, body...)
+}
+
+/******************************************************************************
+ @brief
+ 	读取一个MQTT报文
+ @author
+ 	agent
+ @param
+	r					读取缓冲
+ @return
+ 	byte				返回报文类型和标志
+ 	[]byte				返回报文内容
+ 	error				读取失败时返回错误信息
+ @history
+ 	2026-10-16_15:17 	agent		创建
+*******************************************************************************/
+func mqttRead(r *bufio.Reader) (byte, []byte, error) {
+	kind, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	n, shift := 0, uint(0)
+	for i := 0; i < 4; i++ {
+		c, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		n |= int(c&0x7f) << shift
+		if c&0x80 == 0 {
+			break
+		}
+		shift += 7
+	}
+
+	body := make([]byte, n)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+
+	return kind, body, nil
+}
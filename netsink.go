@@ -0,0 +1,532 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	netDialTimeout   = 5 * time.Second  //网络输出目标连接超时
+	netWriteTimeout  = 10 * time.Second //网络输出目标写入超时
+	netAckMaxPending = 1024             //开启确认时内存中最多保存的未确认数据块
+)
+
+/******************************************************************************
+ @brief
+ 	网络输出目标的TLS配置
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:12 	agent		创建
+*******************************************************************************/
+type TLS_CONFIG struct {
+	CAFile             string //CA证书路径，为空时使用系统证书
+	CertFile           string //客户端证书路径，双向认证时使用
+	KeyFile            string //客户端私钥路径，双向认证时使用
+	ServerName         string //服务器名称，为空时使用连接地址中的主机名
+	InsecureSkipVerify bool   //是否跳过服务器证书校验，仅供测试使用
+}
+
+/******************************************************************************
+ @brief
+ 	网络输出目标类结构，断线后下次写入时自动重连，可以配合AddSink使用
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+type NET_WRITER struct {
+	sync.Mutex               //线程锁
+	network    string        //网络类型，tcp、udp等
+	addrs      []string      //远程地址列表
+	next       int           //下次连接优先使用的地址
+	tlsConfig  *tls.Config   //TLS配置，为nil时使用明文传输
+	conn       net.Conn      //当前连接
+	connAt     time.Time     //当前连接建立的时间
+	reresolve  time.Duration //重新解析域名的时间间隔，0表示不主动重连
+	ackTimeout time.Duration //等待确认的超时时间，0表示不等待确认
+	ackReader  *bufio.Reader //读取当前连接上的确认
+	connSeq    uint64        //连接序号，每次建立连接时加1
+	chunkID    string        //数据块编号前缀，每个输出目标随机生成
+	chunkSeq   uint64        //数据块序号
+	unacked    []*netChunk   //没有收到确认的数据块，按发送顺序排列
+}
+
+/******************************************************************************
+ @brief
+ 	开启确认时发送的数据块
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+type netChunk struct {
+	id    string //数据块编号，重发时不变
+	data  []byte //数据内容
+	sends int    //已经发送的次数，大于0时重发带重复标记
+	conn  uint64 //最后一次发送所在的连接序号
+}
+
+/******************************************************************************
+ @brief
+ 	创建网络输出目标
+ 		例：
+ 			w, err := logger.NewNetWriter("tcp", "collector:5170", &logger.TLS_CONFIG{
+ 				CAFile:   "./certs/ca.pem",
+ 				CertFile: "./certs/client.pem",
+ 				KeyFile:  "./certs/client.key",
+ 			})
+ 			if err == nil {
+ 				logger.AddSink("collector", w, logger.BUFFER_INTERVAL, 64*1024, 5*time.Second)
+ 			}
+ @author
+ 	agent
+ @param
+	network				网络类型，tcp、udp等，使用TLS时必须为tcp
+	addr				远程地址
+	cfg					TLS配置，为nil时使用明文传输
+ @return
+ 	*NET_WRITER			返回网络输出目标
+ 	error				加载证书失败时返回错误信息
+ @history
+ 	2026-10-16_14:11 	agent		创建
+ 	2026-10-16_14:12 	agent		支持TLS
+*******************************************************************************/
+func NewNetWriter(network, addr string, cfg *TLS_CONFIG) (*NET_WRITER, error) {
+	return NewFailoverNetWriter(network, []string{addr}, cfg)
+}
+
+/******************************************************************************
+ @brief
+ 	创建支持多个远程地址的网络输出目标，每次重连时轮流使用下一个地址，
+ 	连接失败时依次尝试其它地址，单个收集服务重启不会导致日志停止发送
+ 		例：
+ 			w, _ := logger.NewFailoverNetWriter("tcp", []string{"collector-a:5170", "collector-b:5170"}, nil)
+ 			w.SetReresolveInterval(5 * time.Minute)
+ 			logger.AddSink("collector", w, logger.BUFFER_INTERVAL, 64*1024, 5*time.Second)
+ @author
+ 	agent
+ @param
+	network				网络类型，tcp、udp等，使用TLS时必须为tcp
+	addrs				远程地址列表
+	cfg					TLS配置，为nil时使用明文传输
+ @return
+ 	*NET_WRITER			返回网络输出目标
+ 	error				地址列表为空或加载证书失败时返回错误信息
+ @history
+ 	2026-10-16_14:12 	agent		创建
+*******************************************************************************/
+func NewFailoverNetWriter(network string, addrs []string, cfg *TLS_CONFIG) (*NET_WRITER, error) {
+
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("logger: no endpoints for %s writer", network)
+	}
+
+	w := &NET_WRITER{network: network, addrs: append([]string(nil), addrs...)}
+	if cfg != nil {
+		tlsConfig, err := cfg.build()
+		if err != nil {
+			return nil, err
+		}
+		w.tlsConfig = tlsConfig
+	}
+
+	return w, nil
+}
+
+/******************************************************************************
+ @brief
+ 	写入数据，未连接时先建立连接，写入失败时断开连接等待下次重连，
+ 	开启确认时数据作为一个数据块发送并等待确认
+ @author
+ 	agent
+ @param
+	b					要写入的数据
+ @return
+ 	int					返回写入的字节数
+ 	error				写入失败时返回错误信息
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+func (w *NET_WRITER) Write(b []byte) (int, error) {
+	w.Lock()
+	defer w.Unlock()
+
+	//连接时间超过间隔后重连，重新解析域名
+	if w.conn != nil && w.reresolve > 0 && time.Since(w.connAt) >= w.reresolve {
+		w.disconnect()
+	}
+
+	if w.ackTimeout > 0 {
+		return w.writeChunk(b)
+	}
+
+	if err := w.connect(); err != nil {
+		return 0, err
+	}
+
+	w.conn.SetWriteDeadline(time.Now().Add(netWriteTimeout))
+	n, err := w.conn.Write(b)
+	if err != nil {
+		w.disconnect()
+	}
+
+	return n, err
+}
+
+/******************************************************************************
+ @brief
+ 	设置重新解析域名的时间间隔，连接建立超过此时间后会在下次写入时重连，
+ 	使远程地址的DNS变化能够生效，同时让多个地址之间的负载更加均衡
+ @author
+ 	agent
+ @param
+	interval			时间间隔，0表示只在断线后重连
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:12 	agent		创建
+*******************************************************************************/
+func (w *NET_WRITER) SetReresolveInterval(interval time.Duration) {
+	w.Lock()
+	defer w.Unlock()
+
+	w.reresolve = interval
+}
+
+/******************************************************************************
+ @brief
+ 	开启数据块确认，实现至少一次送达。每次写入的数据作为一个数据块发送，格式为
+ 		CHUNK <编号> <字节数> <new|dup>\n<数据>
+ 	接收端处理完数据块后回复一行ACK <编号>\n。超时没有收到确认时断开连接，
+ 	重连后先重发所有未确认的数据块，重发的数据块编号不变并带dup标记，
+ 	接收端收到dup标记且编号已经处理过的数据块时直接丢弃。
+ 	确认超时的数据块保存在内存中等待重发，最多保存1024个，超过时新的数据返回错误，
+ 	由输出目标的磁盘缓存处理；没有发送出去的数据同样返回错误
+ 		例：
+ 			w, _ := logger.NewNetWriter("tcp", "collector:5170", nil)
+ 			w.SetAckTimeout(5 * time.Second)
+ 			logger.AddSink("collector", w, logger.BUFFER_INTERVAL, 64*1024, time.Second)
+ @author
+ 	agent
+ @param
+	timeout				等待确认的超时时间，0表示不等待确认
+ @return
+ 	error				网络类型不是流式连接时返回错误信息
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+func (w *NET_WRITER) SetAckTimeout(timeout time.Duration) error {
+	if timeout > 0 && !strings.HasPrefix(w.network, "tcp") && w.network != "unix" {
+		return fmt.Errorf("logger: acknowledgements need a stream connection, not %s", w.network)
+	}
+
+	w.Lock()
+	defer w.Unlock()
+
+	if len(w.chunkID) == 0 {
+		id := make([]byte, 8)
+		if _, err := rand.Read(id); err != nil {
+			return err
+		}
+		w.chunkID = hex.EncodeToString(id)
+	}
+
+	w.ackTimeout = timeout
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	关闭当前连接
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	error				关闭失败时返回错误信息
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+func (w *NET_WRITER) Close() error {
+	w.Lock()
+	defer w.Unlock()
+
+	//最后尝试一次发送未确认的数据块
+	var ackErr error
+	if len(w.unacked) > 0 {
+		ackErr = w.deliver()
+	}
+
+	if w.conn == nil {
+		return ackErr
+	}
+
+	err := w.conn.Close()
+	w.conn = nil
+	if ackErr != nil {
+		return ackErr
+	}
+	return err
+}
+
+/******************************************************************************
+ @brief
+ 	按数据块发送一次写入的数据并等待确认，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	b					要写入的数据
+ @return
+ 	int					数据已经发送时返回len(b)，没有确认的数据块保存在内存中等待重发
+ 	error				数据没有发送出去或者未确认的数据块过多时返回错误信息
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+func (w *NET_WRITER) writeChunk(b []byte) (int, error) {
+
+	//未确认的数据块过多时先尝试重发，仍然失败时这次的数据交给调用者处理
+	if len(w.unacked) >= netAckMaxPending {
+		if err := w.deliver(); err != nil {
+			return 0, err
+		}
+	}
+
+	w.chunkSeq++
+	c := &netChunk{id: fmt.Sprintf("%s-%d", w.chunkID, w.chunkSeq), data: append([]byte(nil), b...)}
+	w.unacked = append(w.unacked, c)
+
+	err := w.deliver()
+	if err != nil && c.sends == 0 {
+		//没有发送出去，接收端不会收到，不需要重发
+		w.unacked = w.unacked[:len(w.unacked)-1]
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+/******************************************************************************
+ @brief
+ 	发送当前连接上还没有发送过的未确认数据块，然后等待全部确认，
+ 	失败时断开连接，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	error				连接、发送失败或者等待确认超时时返回错误信息
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+func (w *NET_WRITER) deliver() error {
+	if err := w.connect(); err != nil {
+		return err
+	}
+
+	var frame bytes.Buffer
+	w.conn.SetWriteDeadline(time.Now().Add(netWriteTimeout))
+	for _, c := range w.unacked {
+		if c.conn == w.connSeq {
+			continue
+		}
+
+		marker := "new"
+		if c.sends > 0 {
+			marker = "dup"
+		}
+
+		frame.Reset()
+		fmt.Fprintf(&frame, "CHUNK %s %d %s\n", c.id, len(c.data), marker)
+		frame.Write(c.data)
+
+		c.sends++
+		c.conn = w.connSeq
+		if _, err := w.conn.Write(frame.Bytes()); err != nil {
+			w.disconnect()
+			return err
+		}
+	}
+
+	w.conn.SetReadDeadline(time.Now().Add(w.ackTimeout))
+	for len(w.unacked) > 0 {
+		line, err := w.ackReader.ReadString('\n')
+		if err != nil {
+			w.disconnect()
+			return err
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "ACK" {
+			w.ack(fields[1])
+		}
+	}
+
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	移除已经确认的数据块，重复的确认直接忽略，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	id					数据块编号
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+func (w *NET_WRITER) ack(id string) {
+	for i, c := range w.unacked {
+		if c.id == id {
+			w.unacked = append(w.unacked[:i], w.unacked[i+1:]...)
+			return
+		}
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	未连接时建立连接，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	error				连接失败时返回错误信息
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+func (w *NET_WRITER) connect() error {
+	if w.conn != nil {
+		return nil
+	}
+
+	conn, err := w.dial()
+	if err != nil {
+		return err
+	}
+
+	w.conn = conn
+	w.connAt = time.Now()
+	w.connSeq++
+	w.ackReader = bufio.NewReader(conn)
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	断开当前连接，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+func (w *NET_WRITER) disconnect() {
+	if w.conn != nil {
+		w.conn.Close()
+		w.conn = nil
+	}
+	w.ackReader = nil
+}
+
+/******************************************************************************
+ @brief
+ 	建立连接，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	net.Conn			返回连接
+ 	error				连接失败时返回错误信息
+ @history
+ 	2026-10-16_14:11 	agent		创建
+ 	2026-10-16_14:12 	agent		支持TLS
+ 	2026-10-16_14:12 	agent		支持多个远程地址轮流连接
+*******************************************************************************/
+func (w *NET_WRITER) dial() (net.Conn, error) {
+
+	var lastErr error
+	dialer := &net.Dialer{Timeout: netDialTimeout}
+	for i := 0; i < len(w.addrs); i++ {
+		n := (w.next + i) % len(w.addrs)
+
+		var conn net.Conn
+		var err error
+		if w.tlsConfig != nil {
+			conn, err = tls.DialWithDialer(dialer, w.network, w.addrs[n], w.tlsConfig)
+		} else {
+			conn, err = dialer.Dial(w.network, w.addrs[n])
+		}
+
+		if err == nil {
+			w.next = n + 1
+			return conn, nil
+		}
+		lastErr = err
+	}
+
+	return nil, lastErr
+}
+
+/******************************************************************************
+ @brief
+ 	根据TLS配置生成标准库的tls.Config
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	*tls.Config			返回标准库的TLS配置
+ 	error				加载证书失败时返回错误信息
+ @history
+ 	2026-10-16_14:12 	agent		创建
+*******************************************************************************/
+func (cfg *TLS_CONFIG) build() (*tls.Config, error) {
+
+	tlsConfig := &tls.Config{
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	//加载CA证书
+	if cfg.CAFile != "" {
+		pem, err := ioutil.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("logger: no certificates found in %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	//加载客户端证书
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
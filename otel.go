@@ -0,0 +1,292 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	OpenTelemetry日志条数计数的key
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:14 	agent		创建
+*******************************************************************************/
+type otelKey struct {
+	name string //日志名称
+	ll   LEVEL  //日志级别
+}
+
+/******************************************************************************
+ @brief
+ 	OTLP JSON格式的属性，只使用字符串值
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:14 	agent		创建
+*******************************************************************************/
+type otlpAttr struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue string `json:"stringValue"`
+	} `json:"value"`
+}
+
+/******************************************************************************
+ @brief
+ 	OTLP JSON格式的整数数据点，64位整数按照协议要求编码为字符串
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:14 	agent		创建
+*******************************************************************************/
+type otlpPoint struct {
+	Attributes        []otlpAttr `json:"attributes,omitempty"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	TimeUnixNano      string     `json:"timeUnixNano"`
+	AsInt             string     `json:"asInt"`
+}
+
+/******************************************************************************
+ @brief
+ 	OTLP JSON格式的累计单调计数指标
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:14 	agent		创建
+*******************************************************************************/
+type otlpMetric struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Unit        string `json:"unit"`
+	Sum         struct {
+		DataPoints             []otlpPoint `json:"dataPoints"`
+		AggregationTemporality int         `json:"aggregationTemporality"`
+		IsMonotonic            bool        `json:"isMonotonic"`
+	} `json:"sum"`
+}
+
+var (
+	otelLock     sync.Mutex    //OpenTelemetry导出设置线程锁
+	otelStop     chan struct{} //停止导出协程
+	otelEnabled  int32         //是否开启了日志条数计数
+	otelCounts   sync.Map      //日志条数计数otelKey->*int64
+	otelStart    time.Time     //计数开始时间
+	otelClient   = &http.Client{Timeout: 10 * time.Second}
+	otelInterval = time.Minute //导出间隔
+)
+
+/******************************************************************************
+ @brief
+ 	开启日志量指标导出，按OTLP/HTTP JSON协议定期发送到OpenTelemetry Collector，
+ 	不依赖OpenTelemetry SDK。导出的指标：
+ 		logger.entries			每个日志名称、每个级别的日志条数，ERROR和FATAL即错误数量
+ 		logger.internal.errors	日志自身的错误次数，与Diagnostics记录的错误一致
+ 		例：
+ 			logger.SetOTelExport("http://otel-collector:4318/v1/metrics", 30*time.Second,
+ 				map[string]string{"service.name": "gameserver", "service.instance.id": serverID})
+ @author
+ 	agent
+ @param
+	endpoint			Collector的OTLP/HTTP指标地址，为空表示停止导出
+	interval			导出间隔，小于等于0时为1分钟
+	resource			资源属性，例如service.name
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:14 	agent		创建
+*******************************************************************************/
+func SetOTelExport(endpoint string, interval time.Duration, resource map[string]string) {
+	otelLock.Lock()
+	defer otelLock.Unlock()
+
+	if otelStop != nil {
+		close(otelStop)
+		otelStop = nil
+	}
+
+	if len(endpoint) == 0 {
+		atomic.StoreInt32(&otelEnabled, 0)
+		return
+	}
+
+	if interval <= 0 {
+		interval = otelInterval
+	}
+
+	if atomic.LoadInt32(&otelEnabled) == 0 {
+		otelStart = time.Now()
+		atomic.StoreInt32(&otelEnabled, 1)
+	}
+
+	stop := make(chan struct{})
+	otelStop = stop
+	start := otelStart
+
+	attrs := make(map[string]string, len(resource))
+	for k, v := range resource {
+		attrs[k] = v
+	}
+
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-t.C:
+				if err := otelPost(endpoint, otelPayload(attrs, start)); err != nil {
+					diag("otel export %s: %v", endpoint, err)
+				}
+			}
+		}
+	}()
+}
+
+/******************************************************************************
+ @brief
+ 	日志条数计数，没有开启导出时不计数
+ @author
+ 	agent
+ @param
+	f					日志文件
+	ll					日志等级
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:14 	agent		创建
+*******************************************************************************/
+func otelCount(f *LOG_FILE, ll LEVEL) {
+	if atomic.LoadInt32(&otelEnabled) == 0 {
+		return
+	}
+
+	key := otelKey{name: logName(f), ll: ll}
+	n, ok := otelCounts.Load(key)
+	if !ok {
+		n, _ = otelCounts.LoadOrStore(key, new(int64))
+	}
+	atomic.AddInt64(n.(*int64), 1)
+}
+
+/******************************************************************************
+ @brief
+ 	生成OTLP JSON格式的指标内容
+ @author
+ 	agent
+ @param
+	resource			资源属性
+	start				计数开始时间
+ @return
+ 	[]byte				返回请求内容
+ @history
+ 	2026-10-16_15:14 	agent		创建
+*******************************************************************************/
+func otelPayload(resource map[string]string, start time.Time) []byte {
+
+	startNano := strconv.FormatInt(start.UnixNano(), 10)
+	nowNano := strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	entries := otlpMetric{Name: "logger.entries", Description: "Log entries written, by logger name and level", Unit: "{entry}"}
+	otelCounts.Range(func(k, v interface{}) bool {
+		key := k.(otelKey)
+		entries.Sum.DataPoints = append(entries.Sum.DataPoints, otlpPoint{
+			Attributes:        otlpAttrs(map[string]string{"logger": schemaName(key.name), "level": key.ll.String()}),
+			StartTimeUnixNano: startNano,
+			TimeUnixNano:      nowNano,
+			AsInt:             strconv.FormatInt(atomic.LoadInt64(v.(*int64)), 10),
+		})
+		return true
+	})
+
+	errors := otlpMetric{Name: "logger.internal.errors", Description: "Errors inside the logger itself", Unit: "{error}"}
+	errors.Sum.DataPoints = []otlpPoint{{
+		StartTimeUnixNano: startNano,
+		TimeUnixNano:      nowNano,
+		AsInt:             strconv.FormatInt(diagTotal(), 10),
+	}}
+
+	metrics := []otlpMetric{entries, errors}
+	for i := range metrics {
+		metrics[i].Sum.AggregationTemporality = 2 //AGGREGATION_TEMPORALITY_CUMULATIVE
+		metrics[i].Sum.IsMonotonic = true
+	}
+
+	payload := map[string]interface{}{
+		"resourceMetrics": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{"attributes": otlpAttrs(resource)},
+				"scopeMetrics": []interface{}{
+					map[string]interface{}{
+						"scope":   map[string]string{"name": "github.com/baickl/logger"},
+						"metrics": metrics,
+					},
+				},
+			},
+		},
+	}
+
+	b, _ := json.Marshal(payload)
+	return b
+}
+
+/******************************************************************************
+ @brief
+ 	生成按名称排序的属性列表
+ @author
+ 	agent
+ @param
+	m					属性
+ @return
+ 	[]otlpAttr			返回属性列表
+ @history
+ 	2026-10-16_15:14 	agent		创建
+*******************************************************************************/
+func otlpAttrs(m map[string]string) []otlpAttr {
+	attrs := make([]otlpAttr, 0, len(m))
+	for k, v := range m {
+		a := otlpAttr{Key: k}
+		a.Value.StringValue = v
+		attrs = append(attrs, a)
+	}
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].Key < attrs[j].Key })
+
+	return attrs
+}
+
+/******************************************************************************
+ @brief
+ 	发送指标到Collector
+ @author
+ 	agent
+ @param
+	endpoint			Collector的OTLP/HTTP指标地址
+	body				请求内容
+ @return
+ 	error				发送失败时返回错误信息
+ @history
+ 	2026-10-16_15:14 	agent		创建
+*******************************************************************************/
+func otelPost(endpoint string, body []byte) error {
+	resp, err := otelClient.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("status %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package logger
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	数据包方向
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:55 	agent		创建
+*******************************************************************************/
+type PACKET_DIR int
+
+const (
+	PACKET_IN  PACKET_DIR = iota //收到的数据包
+	PACKET_OUT                   //发出的数据包
+)
+
+const (
+	packetCategory = "packet" //数据包日志写入的分类
+)
+
+/******************************************************************************
+ @brief
+ 	已注册的消息类型
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:55 	agent		创建
+*******************************************************************************/
+type packetType struct {
+	name    string //消息类型名称
+	rate    int64  //每秒最多记录的条数，0表示不限制
+	second  int64  //当前统计的秒
+	count   int64  //当前秒已经记录的条数
+	dropped int64  //超过限制未记录的条数，下一条记录时输出
+}
+
+var (
+	packetOn    int32                          //是否记录数据包日志
+	packetLock  sync.Mutex                     //消息类型线程锁
+	packetTypes = map[int]*packetType{}        //已注册的消息类型
+	packetOther = &packetType{name: "unknown"} //未注册的消息类型
+)
+
+/******************************************************************************
+ @brief
+ 	开启或关闭数据包日志，默认关闭，可以在排查问题时临时开启
+ @author
+ 	agent
+ @param
+	enable				是否开启
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:55 	agent		创建
+*******************************************************************************/
+func SetPacketLog(enable bool) {
+	if enable {
+		atomic.StoreInt32(&packetOn, 1)
+	} else {
+		atomic.StoreInt32(&packetOn, 0)
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	注册消息类型名称和每秒最多记录的条数，开启数据包日志时高频消息不会写满磁盘。
+ 	msgType为-1时设置未注册消息类型的限制
+ 		例：
+ 			logger.RegisterPacket(1001, "LoginReq", 0)
+ 			logger.RegisterPacket(2001, "MoveSync", 50)
+ 			logger.SetPacketLog(true)
+
+ 			log := logger.ForConn(conn)
+ 			log.Packet(logger.PACKET_IN, 2001, len(data))
+
+ 		输出到packet分类日志：INFO packet dir=in type=MoveSync size=36 conn_id=42 remote=10.0.0.1:50122 local=10.0.0.2:7000
+ @author
+ 	agent
+ @param
+	msgType				消息类型编号
+	name				消息类型名称
+	ratePerSec			每秒最多记录的条数，小于等于0表示不限制
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:55 	agent		创建
+*******************************************************************************/
+func RegisterPacket(msgType int, name string, ratePerSec int) {
+	packetLock.Lock()
+	defer packetLock.Unlock()
+
+	if ratePerSec < 0 {
+		ratePerSec = 0
+	}
+
+	if msgType == -1 {
+		packetOther.rate = int64(ratePerSec)
+		return
+	}
+
+	packetTypes[msgType] = &packetType{name: name, rate: int64(ratePerSec)}
+}
+
+/******************************************************************************
+ @brief
+ 	记录一个数据包，超过消息类型的限制时不记录，被跳过的条数在下一条记录中以dropped输出
+ @author
+ 	agent
+ @param
+	dir					数据包方向
+	msgType				消息类型编号
+	size				数据包大小
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:55 	agent		创建
+*******************************************************************************/
+func (t *TAG_LOG) Packet(dir PACKET_DIR, msgType int, size int) {
+	defer catchError()
+	if atomic.LoadInt32(&packetOn) == 0 || currentLevel() > INFO {
+		return
+	}
+
+	name, dropped, ok := packetAllow(msgType, time.Now().Unix())
+	if !ok {
+		return
+	}
+
+	direction := "in"
+	if dir == PACKET_OUT {
+		direction = "out"
+	}
+
+	line := "packet dir=" + direction + " " + configField("type", name) + " size=" + strconv.Itoa(size)
+	if dropped > 0 {
+		line += " dropped=" + strconv.FormatInt(dropped, 10)
+	}
+
+	packetOutput(line + t.text + "\n")
+}
+
+/******************************************************************************
+ @brief
+ 	检查消息类型当前秒是否还可以记录
+ @author
+ 	agent
+ @param
+	msgType				消息类型编号
+	now					当前时间，Unix秒
+ @return
+ 	string				返回消息类型名称
+ 	int64				返回之前被跳过的条数
+ 	bool				可以记录时返回true
+ @history
+ 	2026-10-16_14:55 	agent		创建
+*******************************************************************************/
+func packetAllow(msgType int, now int64) (string, int64, bool) {
+	packetLock.Lock()
+	defer packetLock.Unlock()
+
+	pt, ok := packetTypes[msgType]
+	if !ok {
+		pt = packetOther
+	}
+
+	name := pt.name
+	if !ok {
+		name = strconv.Itoa(msgType)
+	}
+
+	if pt.second != now {
+		pt.second = now
+		pt.count = 0
+	}
+
+	if pt.rate > 0 && pt.count >= pt.rate {
+		pt.dropped += 1
+		return name, 0, false
+	}
+
+	pt.count += 1
+	dropped := pt.dropped
+	pt.dropped = 0
+
+	return name, dropped, true
+}
+
+/******************************************************************************
+ @brief
+ 	输出数据包日志到packet分类，仅供内部使用，调用深度与output相同
+ @author
+ 	agent
+ @param
+	line				日志内容
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:55 	agent		创建
+*******************************************************************************/
+func packetOutput(line string) {
+	outputFile(Category(packetCategory).file, INFO, line, nil)
+}
@@ -0,0 +1,226 @@
+package logger
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	panicDedupStateFile = "./exceptions/panics.state" //异常签名状态文件
+)
+
+/******************************************************************************
+ @brief
+ 	已经写入完整异常报告的异常签名
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:03 	agent		创建
+*******************************************************************************/
+type panicRecord struct {
+	sig   uint64 //调用栈签名
+	first int64  //第一次发生的时间戳
+	count int    //间隔内发生的次数
+	path  string //完整异常报告的路径
+}
+
+var (
+	panicDedupLock     sync.Mutex    //异常签名线程锁
+	panicDedupInterval time.Duration //相同异常只写一次完整报告的间隔，0表示关闭
+)
+
+/******************************************************************************
+ @brief
+ 	设置相同异常的去重间隔，调用栈每一帧都相同的异常在间隔内只写一次完整的异常报告，
+ 	之后只在该报告末尾追加REPEATED行，不再生成运行时状态快照。
+ 	签名保存在状态文件中，崩溃循环反复重启时也能去重，避免异常目录被大量报告占满
+ 		例：
+ 			logger.SetPanicDedup(time.Hour)
+ @author
+ 	agent
+ @param
+	interval			去重间隔，小于等于0表示关闭
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:03 	agent		创建
+*******************************************************************************/
+func SetPanicDedup(interval time.Duration) {
+	panicDedupLock.Lock()
+	defer panicDedupLock.Unlock()
+
+	if interval < 0 {
+		interval = 0
+	}
+	panicDedupInterval = interval
+}
+
+/******************************************************************************
+ @brief
+ 	计算当前调用栈的签名，由每一帧的函数名、文件和行号计算，不包含参数和协程编号
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	uint64				返回调用栈签名
+ @history
+ 	2026-10-16_15:03 	agent		创建
+*******************************************************************************/
+func panicSignature() uint64 {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(3, pcs)
+
+	h := fnv.New64a()
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(h, "%s %s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+
+	return h.Sum64()
+}
+
+/******************************************************************************
+ @brief
+ 	检查相同签名的异常是否已经在间隔内写过完整报告，是则记录一次重复
+ @author
+ 	agent
+ @param
+	sig					调用栈签名
+ @return
+ 	string				返回完整报告的路径
+ 	int					返回间隔内发生的次数，包含本次
+ 	bool				已经写过完整报告时返回true
+ @history
+ 	2026-10-16_15:03 	agent		创建
+ 	2026-10-16_15:42 	agent		通过日志存储检查异常文件
+*******************************************************************************/
+func panicRepeat(sig uint64) (string, int, bool) {
+	panicDedupLock.Lock()
+	defer panicDedupLock.Unlock()
+
+	if panicDedupInterval <= 0 {
+		return "", 0, false
+	}
+
+	records := readPanicRecords(time.Now().Add(-panicDedupInterval))
+	for i := range records {
+		r := &records[i]
+		if r.sig != sig || !storageExist(r.path) {
+			continue
+		}
+
+		r.count += 1
+		writePanicRecords(records)
+		return r.path, r.count, true
+	}
+
+	return "", 0, false
+}
+
+/******************************************************************************
+ @brief
+ 	记录已经写入完整报告的异常签名
+ @author
+ 	agent
+ @param
+	sig					调用栈签名
+	path				完整异常报告的路径
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:03 	agent		创建
+*******************************************************************************/
+func panicRemember(sig uint64, path string) {
+	panicDedupLock.Lock()
+	defer panicDedupLock.Unlock()
+
+	if panicDedupInterval <= 0 {
+		return
+	}
+
+	now := time.Now()
+	records := []panicRecord{}
+	for _, r := range readPanicRecords(now.Add(-panicDedupInterval)) {
+		if r.sig != sig {
+			records = append(records, r)
+		}
+	}
+	records = append(records, panicRecord{sig: sig, first: now.Unix(), count: 1, path: path})
+	writePanicRecords(records)
+}
+
+/******************************************************************************
+ @brief
+ 	读取状态文件中since之后第一次发生的异常签名
+ @author
+ 	agent
+ @param
+	since				起始时间
+ @return
+ 	[]panicRecord		返回异常签名列表
+ @history
+ 	2026-10-16_15:03 	agent		创建
+ 	2026-10-16_16:25 	agent		文件操作通过日志存储接口完成
+*******************************************************************************/
+func readPanicRecords(since time.Time) []panicRecord {
+
+	data, err := storageReadFile(panicDedupStateFile)
+	if err != nil {
+		return nil
+	}
+
+	records := []panicRecord{}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.SplitN(line, " ", 4)
+		if len(fields) != 4 {
+			continue
+		}
+
+		sig, err1 := strconv.ParseUint(fields[0], 16, 64)
+		first, err2 := strconv.ParseInt(fields[1], 10, 64)
+		count, err3 := strconv.Atoi(fields[2])
+		if err1 != nil || err2 != nil || err3 != nil || first < since.Unix() {
+			continue
+		}
+
+		records = append(records, panicRecord{sig: sig, first: first, count: count, path: fields[3]})
+	}
+
+	return records
+}
+
+/******************************************************************************
+ @brief
+ 	写入异常签名到状态文件
+ @author
+ 	agent
+ @param
+	records				异常签名列表
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:03 	agent		创建
+ 	2026-10-16_16:25 	agent		文件操作通过日志存储接口完成
+*******************************************************************************/
+func writePanicRecords(records []panicRecord) {
+
+	logStorage.MkdirAll("./exceptions/", os.ModePerm)
+
+	lines := make([]string, 0, len(records))
+	for _, r := range records {
+		lines = append(lines, fmt.Sprintf("%016x %d %d %s", r.sig, r.first, r.count, r.path))
+	}
+
+	storageWriteFile(panicDedupStateFile, []byte(strings.Join(lines, "\n")+"\n"), os.ModePerm)
+}
@@ -0,0 +1,289 @@
+package logger
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	文本格式日志行解析结果
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:37 	agent		创建
+*******************************************************************************/
+type ENTRY struct {
+	Time   time.Time   //日志时间，没有日期时只有时分秒，没有时间时为零值
+	File   string      //调用者文件，没有调用者信息时为空
+	Line   int         //调用者行号
+	Func   string      //调用者函数名，没有时为空
+	Level  LEVEL       //日志级别
+	Msg    string      //日志内容
+	Fields [][2]string //公共字段，按输出顺序排列
+}
+
+var (
+	lineEpoch  = regexp.MustCompile(`^(\d{13}) `)
+	lineDate   = regexp.MustCompile(`^(\d{4}/\d{2}/\d{2}) `)
+	lineTime   = regexp.MustCompile(`^(\d{2}:\d{2}:\d{2})(\.\d{3}|\.\d{6}|\.\d{9})? `)
+	lineCaller = regexp.MustCompile(`^(\S+):(\d+)(?: (\S+))?: `)
+	lineLevel  = regexp.MustCompile(`^(ALL|DEBUG|INFO|WARN|ERROR|FATAL)(?: |$)`)
+	lineField  = regexp.MustCompile(` ([^ \t\r\n="]+)=("(?:[^"\\]|\\.)*"|[^ \t\r\n"=]+)$`)
+)
+
+/******************************************************************************
+ @brief
+ 	解析一行文本格式的日志，格式为：
+ 		[日期 ][时间 ][文件:行号[ 函数名]: ]级别 内容[ key=value...]
+ 		[Unix毫秒时间戳 ][文件:行号[ 函数名]: ]级别 内容[ key=value...]
+
+ 	日期、时间、调用者信息由日志flag决定，可以不存在。内容末尾的key=value会被解析为公共字段，
+ 	因此内容本身以key=value结尾时也会被当作公共字段。内容中包含换行时只能解析第一行，
+ 	SetFileCompressLong压缩的内容会被还原，SetFileChecksum输出的校验值校验通过时会被去掉
+ 		例：
+ 			e, err := logger.ParseLine("2026/10/16 21:30:00.000001 main.go:12: INFO started server=login01")
+ @author
+ 	agent
+ @param
+	s					日志行，可以带有行尾换行符
+ @return
+ 	ENTRY				返回解析结果
+ 	error				格式错误时返回错误信息
+ @history
+ 	2026-10-16_14:37 	agent		创建
+ 	2026-10-16_14:40 	agent		支持毫秒、纳秒和Unix毫秒时间戳
+ 	2026-10-16_15:11 	agent		还原压缩的超长内容
+ 	2026-10-16_15:12 	agent		去掉校验值
+ 	2026-10-16_15:14 	agent		字段解析拆分到parseFields
+*******************************************************************************/
+func ParseLine(s string) (ENTRY, error) {
+
+	e := ENTRY{}
+	rest := strings.TrimRight(s, "\r\n")
+
+	//去掉校验通过的CRC32校验值
+	if body, ok := splitChecksum(rest); ok {
+		rest = body
+	}
+
+	//Unix毫秒时间戳
+	if m := lineEpoch.FindStringSubmatch(rest); m != nil {
+		ms, _ := strconv.ParseInt(m[1], 10, 64)
+		e.Time = time.Unix(0, ms*int64(time.Millisecond))
+		rest = rest[len(m[0]):]
+	}
+
+	date := ""
+	if m := lineDate.FindStringSubmatch(rest); m != nil {
+		date = m[1]
+		rest = rest[len(m[0]):]
+	}
+
+	clock := ""
+	if m := lineTime.FindStringSubmatch(rest); m != nil {
+		clock = m[1] + m[2]
+		rest = rest[len(m[0]):]
+	}
+
+	if len(date) > 0 || len(clock) > 0 {
+		t, err := parseLineTime(date, clock)
+		if err != nil {
+			return ENTRY{}, err
+		}
+		e.Time = t
+	}
+
+	//级别之前的内容为调用者信息
+	if !lineLevel.MatchString(rest) {
+		m := lineCaller.FindStringSubmatch(rest)
+		if m == nil {
+			return ENTRY{}, fmt.Errorf("logger: parse line: missing level in %q", s)
+		}
+
+		line, err := strconv.Atoi(m[2])
+		if err != nil {
+			return ENTRY{}, fmt.Errorf("logger: parse line: invalid line number %q", m[2])
+		}
+		e.File, e.Line, e.Func = m[1], line, m[3]
+		rest = rest[len(m[0]):]
+	}
+
+	m := lineLevel.FindStringSubmatch(rest)
+	if m == nil {
+		return ENTRY{}, fmt.Errorf("logger: parse line: missing level in %q", s)
+	}
+	e.Level, _ = ParseLevel(m[1])
+	rest = rest[len(m[0]):]
+
+	e.Msg, e.Fields = parseFields(rest)
+
+	//还原压缩的超长内容
+	if strings.HasPrefix(e.Msg, longMarker) {
+		if msg, ok := expandLong(e.Msg[len(longMarker):]); ok {
+			e.Msg = msg
+		}
+	}
+
+	return e, nil
+}
+
+/******************************************************************************
+ @brief
+ 	解析输出目标收到的一批日志行，无法解析的行属于上一条日志的多行内容，
+ 	第一行就无法解析时作为一条没有级别的日志
+ @author
+ 	agent
+ @param
+	b					日志行，以换行分隔
+ @return
+ 	[]ENTRY				返回解析结果
+ 	[]string			返回每条日志的原文，不带行尾换行符
+ @history
+ 	2026-10-16_15:16 	agent		创建
+ 	2026-10-16_15:17 	agent		返回每条日志的原文
+*******************************************************************************/
+func parseEntries(b []byte) ([]ENTRY, []string) {
+
+	entries, raws := []ENTRY{}, []string{}
+	for _, line := range strings.Split(strings.TrimRight(string(b), "\r\n"), "\n") {
+		line = strings.TrimRight(line, "\r")
+		e, err := ParseLine(line)
+		if err == nil {
+			entries, raws = append(entries, e), append(raws, line)
+			continue
+		}
+
+		if n := len(entries); n > 0 {
+			entries[n-1].Msg += "\n" + line
+			raws[n-1] += "\n" + line
+		} else {
+			entries, raws = append(entries, ENTRY{Msg: line}), append(raws, line)
+		}
+	}
+
+	return entries, raws
+}
+
+/******************************************************************************
+ @brief
+ 	从末尾开始解析日志内容中的key=value字段
+ @author
+ 	agent
+ @param
+	rest				级别之后的日志内容
+ @return
+ 	string				返回去掉字段后的日志内容
+ 	[][2]string			返回字段列表，按输出顺序排列
+ @history
+ 	2026-10-16_15:14 	agent		创建，从ParseLine拆分
+*******************************************************************************/
+func parseFields(rest string) (string, [][2]string) {
+
+	var fields [][2]string
+	for {
+		m := lineField.FindStringSubmatchIndex(" " + rest)
+		if m == nil || m[0] == 0 {
+			break
+		}
+
+		key, value := rest[m[2]-1:m[3]-1], rest[m[4]-1:m[5]-1]
+		if strings.HasPrefix(value, `"`) {
+			v, err := strconv.Unquote(value)
+			if err != nil {
+				break
+			}
+			value = v
+		}
+
+		fields = append([][2]string{{key, value}}, fields...)
+		rest = rest[:m[0]-1]
+	}
+
+	return rest, fields
+}
+
+/******************************************************************************
+ @brief
+ 	按照文本格式输出日志行，不包含行尾换行符，ParseLine(e.String())的结果与e相同
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	string				返回日志行
+ @history
+ 	2026-10-16_14:37 	agent		创建
+ 	2026-10-16_14:40 	agent		支持毫秒、纳秒和Unix毫秒时间戳
+*******************************************************************************/
+func (e ENTRY) String() string {
+
+	flags := 0
+	if !e.Time.IsZero() {
+		flags |= log.Ltime | log.Lmicroseconds
+		if e.Time.Year() != 0 || e.Time.YearDay() != 1 {
+			flags |= log.Ldate
+		}
+	}
+	if len(e.File) > 0 {
+		flags |= log.Llongfile
+	}
+
+	//微秒以下的精度使用纳秒输出
+	var buf []byte
+	if e.Time.Nanosecond()%1000 != 0 {
+		buf = formatTime(buf, flags, e.Time, TIME_NANO)
+		flags &^= log.Ldate | log.Ltime | log.Lmicroseconds
+	}
+
+	buf = formatHeader(buf, flags, e.Time, e.File, e.Line, e.Func)
+	buf = append(buf, e.Level.String()...)
+	buf = append(buf, ' ')
+	buf = append(buf, e.Msg...)
+	for _, field := range e.Fields {
+		buf = append(buf, ' ')
+		buf = append(buf, configField(field[0], field[1])...)
+	}
+
+	return string(buf)
+}
+
+/******************************************************************************
+ @brief
+ 	解析日志行中的日期和时间，使用本地时区
+ @author
+ 	agent
+ @param
+	date				日期，格式为2006/01/02，可以为空
+	clock				时间，格式为15:04:05，可以带有毫秒、微秒或纳秒，可以为空
+ @return
+ 	time.Time			返回时间
+ 	error				格式错误时返回错误信息
+ @history
+ 	2026-10-16_14:37 	agent		创建
+ 	2026-10-16_14:40 	agent		支持毫秒、纳秒和Unix毫秒时间戳
+*******************************************************************************/
+func parseLineTime(date, clock string) (time.Time, error) {
+
+	layout, value := "", ""
+	if len(date) > 0 {
+		layout, value = "2006/01/02", date
+	}
+	if len(clock) > 0 {
+		if len(layout) > 0 {
+			layout, value = layout+" ", value+" "
+		}
+		layout, value = layout+"15:04:05.999999999", value+clock
+	}
+
+	t, err := time.ParseInLocation(layout, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("logger: parse line: invalid time %q", value)
+	}
+
+	return t, nil
+}
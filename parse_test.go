@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 按照不同的flag写入日志，再解析日志文件中的行
+func TestParseLine(t *testing.T) {
+	defer SetLevelFlags(INFO, logFlags)
+	defer SetField("server", "")
+	defer SetCallerFunc(false)
+
+	cases := []struct {
+		flags  int
+		fn     bool
+		msg    string
+		fields [][2]string
+	}{
+		{logFlags, false, "started", nil},
+		{logFlags, true, "with func", nil},
+		{log.Ldate | log.Ltime, false, "no caller", nil},
+		{0, false, "bare", nil},
+		{log.Lshortfile, false, "caller only", [][2]string{{"server", "login 01"}}},
+		{logFlags, false, "", [][2]string{{"server", "login01"}}},
+	}
+
+	for _, c := range cases {
+		dir := setupStress(t)
+		SetLevelFlags(INFO, c.flags)
+		SetCallerFunc(c.fn)
+		SetField("server", "")
+		for _, field := range c.fields {
+			SetField(field[0], field[1])
+		}
+		Info(c.msg)
+
+		lines := readLines(t, dir, "stress")
+		if len(lines) != 1 {
+			t.Fatalf("flags %d: expected 1 line, got %d", c.flags, len(lines))
+		}
+
+		e, err := ParseLine(lines[0])
+		if err != nil {
+			t.Fatalf("flags %d: %v", c.flags, err)
+		}
+
+		if e.Level != INFO || e.Msg != c.msg || !reflect.DeepEqual(e.Fields, c.fields) {
+			t.Fatalf("flags %d: unexpected entry %+v from %q", c.flags, e, lines[0])
+		}
+
+		if c.flags&log.Lshortfile != 0 && (e.File != "parse_test.go" || e.Line == 0) {
+			t.Fatalf("flags %d: unexpected caller %s:%d", c.flags, e.File, e.Line)
+		}
+
+		if c.fn && !strings.HasSuffix(e.Func, "TestParseLine") {
+			t.Fatalf("flags %d: unexpected func %q", c.flags, e.Func)
+		}
+
+		if c.flags&log.Ldate != 0 && e.Time.Year() < 2000 {
+			t.Fatalf("flags %d: unexpected time %v", c.flags, e.Time)
+		}
+	}
+}
+
+// 解析成功的行重新输出后再次解析，结果必须相同
+func FuzzParseLine(f *testing.F) {
+	f.Add("2026/10/16 21:30:00.000001 main.go:12: INFO started server=login01")
+	f.Add("2026/10/16 21:30:00 /src/app/main.go:12 main.main: WARN disk low free=\"1 GB\"")
+	f.Add("21:30:00.123456 ERROR failed")
+	f.Add("21:30:00.123 ERROR failed")
+	f.Add("2026/10/16 21:30:00.123456789 INFO nano")
+	f.Add("1792161507345 main.go:18: INFO epoch")
+	f.Add("FATAL ")
+	f.Add("INFO a=b c=d")
+	f.Add("main.go:1: DEBUG x=\"\\\"\"")
+
+	f.Fuzz(func(t *testing.T, s string) {
+		e, err := ParseLine(s)
+		if err != nil {
+			return
+		}
+
+		again, err := ParseLine(e.String())
+		if err != nil {
+			t.Fatalf("reparse %q: %v", e.String(), err)
+		}
+
+		if !again.Time.Equal(e.Time) {
+			t.Fatalf("time %v != %v for %q", again.Time, e.Time, s)
+		}
+		again.Time = e.Time
+
+		if !reflect.DeepEqual(again, e) {
+			t.Fatalf("%+v != %+v for %q", again, e, s)
+		}
+	})
+}
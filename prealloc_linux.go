@@ -0,0 +1,24 @@
+package logger
+
+import (
+	"os"
+	"syscall"
+)
+
+/******************************************************************************
+ @brief
+ 	预分配文件磁盘空间，不改变文件大小
+ @author
+ 	agent
+ @param
+	f					文件
+	size				预分配大小
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:22 	agent		创建
+*******************************************************************************/
+func preallocate(f *os.File, size int64) {
+	//FALLOC_FL_KEEP_SIZE
+	syscall.Fallocate(int(f.Fd()), 0x01, 0, size)
+}
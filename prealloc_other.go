@@ -0,0 +1,24 @@
+//go:build !linux
+// +build !linux
+
+package logger
+
+import (
+	"os"
+)
+
+/******************************************************************************
+ @brief
+ 	预分配文件磁盘空间，当前平台不支持
+ @author
+ 	agent
+ @param
+	f					文件
+	size				预分配大小
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:22 	agent		创建
+*******************************************************************************/
+func preallocate(f *os.File, size int64) {
+}
@@ -0,0 +1,293 @@
+package logger
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+type TIME_PRECISION int //日志时间精度
+
+const (
+	TIME_DEFAULT         TIME_PRECISION = iota //使用日志flag决定的精度，默认为微秒
+	TIME_SECOND                                //秒
+	TIME_MILLI                                 //毫秒
+	TIME_MICRO                                 //微秒
+	TIME_NANO                                  //纳秒
+	TIME_EPOCH_MILLI                           //Unix毫秒时间戳，替换日期和时间
+	time_precision_count                       //精度数量，仅供内部使用
+)
+
+var (
+	logFilePrecision   TIME_PRECISION = TIME_DEFAULT //日志文件的时间精度
+	logFileLocation    *time.Location                //日志文件的时区，nil表示由日志flag决定
+	logConsoleLocation *time.Location                //终端控制台的时区，nil表示本地时间
+)
+
+/******************************************************************************
+ @brief
+ 	日志行的组成部分，按照不同的输出格式生成日志行，同一格式只生成一次
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:40 	agent		创建
+ 	2026-10-16_14:50 	agent		支持易读字段
+ 	2026-10-16_14:51 	agent		按照输出格式缓存
+ 	2026-10-16_15:06 	agent		增加路由标签
+ 	2026-10-16_15:11 	agent		增加压缩超长内容后的日志内容
+*******************************************************************************/
+type logLine struct {
+	flags   int               //日志flag
+	t       time.Time         //日志时间
+	file    string            //调用者文件
+	line    int               //调用者行号
+	fn      string            //调用者函数名
+	context string            //日志内容
+	packed  string            //压缩超长内容后的日志内容，没有压缩时为空
+	cache   []lineCache       //每种格式生成的日志行
+	labels  map[string]string //路由标签，用于选择输出目标
+}
+
+/******************************************************************************
+ @brief
+ 	日志行的输出格式，文件和每个输出目标可以分别设置
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:51 	agent		创建
+ 	2026-10-16_15:11 	agent		支持压缩超长内容
+ 	2026-10-16_15:12 	agent		支持输出校验值
+*******************************************************************************/
+type lineFormat struct {
+	precision TIME_PRECISION //时间精度
+	humanize  bool           //是否输出易读字段
+	location  *time.Location //时区，nil表示由日志flag决定
+	packed    bool           //是否使用压缩超长内容后的日志内容
+	checksum  bool           //是否在末尾输出CRC32校验值
+}
+
+/******************************************************************************
+ @brief
+ 	按照一种格式生成的日志行
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:51 	agent		创建
+*******************************************************************************/
+type lineCache struct {
+	format lineFormat //输出格式
+	buf    []byte     //日志行
+}
+
+/******************************************************************************
+ @brief
+ 	设置日志文件的时间精度
+ 		例：
+ 			logger.SetFileTimePrecision(logger.TIME_MILLI)
+
+ 		输出：2026/10/16 22:10:00.123 main.go:12: INFO started
+ @author
+ 	agent
+ @param
+	p					时间精度
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:40 	agent		创建
+*******************************************************************************/
+func SetFileTimePrecision(p TIME_PRECISION) {
+	logFilePrecision = p
+}
+
+/******************************************************************************
+ @brief
+ 	设置输出目标的时间精度，例如部分日志收集系统要求Unix毫秒时间戳
+ 		例：
+ 			logger.SetSinkTimePrecision("collector", logger.TIME_EPOCH_MILLI)
+
+ 		输出：1792159800123 main.go:12: INFO started
+ @author
+ 	agent
+ @param
+	name				输出目标名称
+	p					时间精度
+ @return
+ 	bool				输出目标不存在时返回false
+ @history
+ 	2026-10-16_14:40 	agent		创建
+*******************************************************************************/
+func SetSinkTimePrecision(name string, p TIME_PRECISION) bool {
+
+	sink := findSink(name)
+	if sink == nil {
+		return false
+	}
+
+	sink.Lock()
+	defer sink.Unlock()
+
+	sink.precision = p
+	return true
+}
+
+/******************************************************************************
+ @brief
+ 	设置日志文件的时区，例如日志收集流水线统一使用UTC，而终端控制台仍然显示本地时间
+ 		例：
+ 			logger.SetFileLocation(time.UTC)
+ @author
+ 	agent
+ @param
+	loc					时区，nil表示由日志flag决定
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:51 	agent		创建
+*******************************************************************************/
+func SetFileLocation(loc *time.Location) {
+	logFileLocation = loc
+}
+
+/******************************************************************************
+ @brief
+ 	设置终端控制台的时区
+ @author
+ 	agent
+ @param
+	loc					时区，nil表示本地时间
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:51 	agent		创建
+*******************************************************************************/
+func SetConsoleLocation(loc *time.Location) {
+	logConsoleLocation = loc
+}
+
+/******************************************************************************
+ @brief
+ 	设置输出目标的时区
+ 		例：
+ 			logger.SetSinkLocation("collector", time.UTC)
+ @author
+ 	agent
+ @param
+	name				输出目标名称
+	loc					时区，nil表示由日志flag决定
+ @return
+ 	bool				输出目标不存在时返回false
+ @history
+ 	2026-10-16_14:51 	agent		创建
+*******************************************************************************/
+func SetSinkLocation(name string, loc *time.Location) bool {
+	sink := findSink(name)
+	if sink == nil {
+		return false
+	}
+
+	sink.Lock()
+	defer sink.Unlock()
+
+	sink.location = loc
+	return true
+}
+
+/******************************************************************************
+ @brief
+ 	按照输出格式生成日志行，以换行结尾，同一格式只生成一次
+ @author
+ 	agent
+ @param
+	f					输出格式
+ @return
+ 	[]byte				返回日志行，调用者不能修改
+ @history
+ 	2026-10-16_14:40 	agent		创建
+ 	2026-10-16_14:50 	agent		支持易读字段
+ 	2026-10-16_14:51 	agent		按照输出格式生成，支持时区
+ 	2026-10-16_15:11 	agent		支持压缩超长内容
+ 	2026-10-16_15:12 	agent		支持输出校验值
+*******************************************************************************/
+func (l *logLine) bytes(f lineFormat) []byte {
+	if f.precision < TIME_DEFAULT || f.precision >= time_precision_count {
+		f.precision = TIME_DEFAULT
+	}
+
+	for _, c := range l.cache {
+		if c.format == f {
+			return c.buf
+		}
+	}
+
+	//指定时区时不再使用日志flag中的UTC设置
+	t, flags := l.t, l.flags
+	if f.location != nil {
+		t = t.In(f.location)
+		flags &^= log.LUTC
+	}
+
+	var buf []byte
+	if f.precision != TIME_DEFAULT && flags&(log.Ldate|log.Ltime|log.Lmicroseconds) != 0 {
+		buf = formatTime(buf, flags, t, f.precision)
+		flags &^= log.Ldate | log.Ltime | log.Lmicroseconds
+	}
+
+	buf = formatHeader(buf, flags, t, l.file, l.line, l.fn)
+	context := l.context
+	if f.packed && len(l.packed) > 0 {
+		context = l.packed
+	}
+
+	buf = append(buf, humanText(context, f.humanize)...)
+	if f.checksum {
+		buf = append(buf, checksumSuffix(buf)...)
+	}
+	buf = append(buf, '\n')
+
+	l.cache = append(l.cache, lineCache{format: f, buf: buf})
+	return buf
+}
+
+/******************************************************************************
+ @brief
+ 	按照时间精度生成日志时间，日期是否输出仍由日志flag决定
+ @author
+ 	agent
+ @param
+	buf					输出缓冲
+	flags				日志flag
+	t					日志时间
+	p					时间精度
+ @return
+ 	[]byte				返回追加时间后的缓冲
+ @history
+ 	2026-10-16_14:40 	agent		创建
+*******************************************************************************/
+func formatTime(buf []byte, flags int, t time.Time, p TIME_PRECISION) []byte {
+
+	if p == TIME_EPOCH_MILLI {
+		buf = strconv.AppendInt(buf, t.UnixNano()/int64(time.Millisecond), 10)
+		return append(buf, ' ')
+	}
+
+	if flags&log.LUTC != 0 {
+		t = t.UTC()
+	}
+
+	if flags&log.Ldate != 0 {
+		buf = append(buf, fmt.Sprintf("%04d/%02d/%02d ", t.Year(), int(t.Month()), t.Day())...)
+	}
+
+	buf = append(buf, fmt.Sprintf("%02d:%02d:%02d", t.Hour(), t.Minute(), t.Second())...)
+	switch p {
+	case TIME_MILLI:
+		buf = append(buf, fmt.Sprintf(".%03d", t.Nanosecond()/1000000)...)
+	case TIME_MICRO:
+		buf = append(buf, fmt.Sprintf(".%06d", t.Nanosecond()/1000)...)
+	case TIME_NANO:
+		buf = append(buf, fmt.Sprintf(".%09d", t.Nanosecond())...)
+	}
+
+	return append(buf, ' ')
+}
@@ -0,0 +1,211 @@
+package logger
+
+import (
+	"log"
+	"time"
+)
+
+const (
+	remoteSinkName = "remote" //配置的远程日志收集服务的输出目标名称
+)
+
+/******************************************************************************
+ @brief
+ 	日志配置，通过Production、Development、HighThroughput、Compliance获取预设配置，
+ 	按需修改后调用Apply一次生效，新服务可以直接使用统一的配置
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:08 	agent		创建
+ 	2026-10-16_15:08 	agent		增加远程日志收集服务
+ 	2026-10-16_15:35 	agent		增加日志级别时间段规则
+*******************************************************************************/
+type CONFIG struct {
+	Dir               string           //日志目录，为空时不写日志文件
+	Name              string           //日志文件名
+	Level             LEVEL            //日志级别
+	Console           bool             //终端控制台是否显示日志
+	ConsoleFormat     FORMAT           //终端控制台输出格式
+	Caller            bool             //是否输出调用者文件和行号
+	CallerFunc        bool             //调用者信息中是否包含函数名
+	StormLimit        int              //日志风暴熔断的每秒条数上限，0表示不采样
+	StormSample       int              //熔断期间每StormSample条保留1条
+	RotateInterval    time.Duration    //切分时间间隔，0表示按天切分
+	Retention         time.Duration    //日志文件保留时间，0表示永久保留
+	Manifest          bool             //切分后是否记录校验清单
+	BackgroundWorkers int              //后台处理最大并发数量，0表示不限制
+	Remote            []string         //远程日志收集服务地址列表host:port，为空时不发送
+	RemoteCompression string           //远程日志压缩算法，空字符串表示不压缩
+	AsyncQueue        int              //异步写入队列长度，0表示同步写入
+	AsyncPolicy       OVERFLOW_POLICY  //异步写入队列满时的处理方式
+	LevelSchedules    []LEVEL_SCHEDULE //日志级别时间段规则，为空时不使用
+}
+
+/******************************************************************************
+ @brief
+ 	生产环境预设：INFO级别，终端控制台输出JSON供容器日志采集，带调用者信息，
+ 	每秒超过5000条时采样，按天切分，保留14天
+ 		例：
+ 			logger.Production("./log", "gameserver").Apply()
+ @author
+ 	agent
+ @param
+	dir					日志目录
+	name				日志文件名
+ @return
+ 	CONFIG				返回预设配置
+ @history
+ 	2026-10-16_15:08 	agent		创建
+*******************************************************************************/
+func Production(dir, name string) CONFIG {
+	return CONFIG{
+		Dir:           dir,
+		Name:          name,
+		Level:         INFO,
+		Console:       true,
+		ConsoleFormat: FORMAT_JSON,
+		Caller:        true,
+		StormLimit:    5000,
+		StormSample:   100,
+		Retention:     14 * 24 * time.Hour,
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	开发环境预设：输出全部级别，终端控制台输出文本，调用者信息包含函数名，不采样，
+ 	按天切分，永久保留
+ @author
+ 	agent
+ @param
+	dir					日志目录
+	name				日志文件名
+ @return
+ 	CONFIG				返回预设配置
+ @history
+ 	2026-10-16_15:08 	agent		创建
+*******************************************************************************/
+func Development(dir, name string) CONFIG {
+	return CONFIG{
+		Dir:           dir,
+		Name:          name,
+		Level:         ALL,
+		Console:       true,
+		ConsoleFormat: FORMAT_TEXT,
+		Caller:        true,
+		CallerFunc:    true,
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	高吞吐预设：INFO级别，不显示终端控制台，不获取调用者信息，每秒超过20000条时采样，
+ 	每小时切分，保留3天，后台处理只使用1个并发，异步写入日志文件
+ @author
+ 	agent
+ @param
+	dir					日志目录
+	name				日志文件名
+ @return
+ 	CONFIG				返回预设配置
+ @history
+ 	2026-10-16_15:08 	agent		创建
+*******************************************************************************/
+func HighThroughput(dir, name string) CONFIG {
+	return CONFIG{
+		Dir:               dir,
+		Name:              name,
+		Level:             INFO,
+		ConsoleFormat:     FORMAT_TEXT,
+		StormLimit:        20000,
+		StormSample:       100,
+		RotateInterval:    time.Hour,
+		Retention:         3 * 24 * time.Hour,
+		BackgroundWorkers: 1,
+		AsyncQueue:        64 * 1024,
+		AsyncPolicy:       OVERFLOW_BLOCK,
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	合规审计预设：INFO级别，带调用者信息，不采样，按天切分，保留400天，
+ 	切分后记录校验清单，归档后可以通过VerifyManifest校验完整性
+ @author
+ 	agent
+ @param
+	dir					日志目录
+	name				日志文件名
+ @return
+ 	CONFIG				返回预设配置
+ @history
+ 	2026-10-16_15:08 	agent		创建
+*******************************************************************************/
+func Compliance(dir, name string) CONFIG {
+	return CONFIG{
+		Dir:           dir,
+		Name:          name,
+		Level:         INFO,
+		Console:       true,
+		ConsoleFormat: FORMAT_TEXT,
+		Caller:        true,
+		Retention:     400 * 24 * time.Hour,
+		Manifest:      true,
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	使配置生效，设置了日志目录时最后调用Initialize，
+ 	配置错误不会返回，上线前可以通过ValidateConfig检查
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:08 	agent		创建
+ 	2026-10-16_15:08 	agent		增加远程日志收集服务
+ 	2026-10-16_15:35 	agent		增加日志级别时间段规则
+*******************************************************************************/
+func (cfg CONFIG) Apply() {
+
+	SetLevel(cfg.Level)
+	if err := SetLevelSchedules(cfg.LevelSchedules); err != nil {
+		diag("%v", err)
+	}
+	SetConsole(cfg.Console)
+	SetConsoleFormat(cfg.ConsoleFormat)
+
+	flags := log.Ldate | log.Lmicroseconds
+	if cfg.Caller {
+		flags |= log.Lshortfile
+	}
+	SetLevelFlags(ALL, flags)
+	SetCallerFunc(cfg.CallerFunc)
+
+	SetStormBreaker(cfg.StormLimit, cfg.StormSample)
+	SetRotateInterval(cfg.RotateInterval, nil)
+	SetRetention(cfg.Retention)
+	SetManifest(cfg.Manifest)
+	SetBackgroundWorkers(cfg.BackgroundWorkers)
+	SetAsync(cfg.AsyncQueue, cfg.AsyncPolicy)
+
+	RemoveSink(remoteSinkName)
+	if len(cfg.Remote) > 0 {
+		w, err := NewFailoverNetWriter("tcp", cfg.Remote, nil)
+		if err != nil {
+			diag("remote %v: %v", cfg.Remote, err)
+		} else {
+			AddSink(remoteSinkName, w, BUFFER_INTERVAL, 64*1024, 5*time.Second)
+			if err := SetSinkCompression(remoteSinkName, cfg.RemoteCompression); err != nil {
+				diag("%v", err)
+			}
+		}
+	}
+
+	if len(cfg.Dir) > 0 {
+		Initialize(cfg.Dir, cfg.Name)
+	}
+}
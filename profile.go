@@ -0,0 +1,227 @@
+package logger
+
+import (
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	日志自身开销统计，通过SetSelfProfile开启后由Stats获取
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:44 	agent		创建
+ 	2026-10-16_14:53 	agent		增加工作协程异常次数
+ 	2026-10-16_14:57 	agent		增加慢操作耗时统计
+*******************************************************************************/
+type STATS struct {
+	Profiling bool                  //是否开启了自身开销统计
+	Entries   int64                 //统计的日志条数
+	EntryP50  time.Duration         //单条日志耗时50分位
+	EntryP90  time.Duration         //单条日志耗时90分位
+	EntryP99  time.Duration         //单条日志耗时99分位
+	EntryMax  time.Duration         //单条日志最大耗时
+	LockWaits int64                 //统计的锁等待次数
+	LockP50   time.Duration         //锁等待时间50分位
+	LockP90   time.Duration         //锁等待时间90分位
+	LockP99   time.Duration         //锁等待时间99分位
+	LockMax   time.Duration         //锁等待最大时间
+	Panics    map[string]int64      //SafeGo、SafePool每个名称的异常次数，不需要开启统计
+	Slow      map[string]SLOW_STATS //Slow每个操作名称的耗时统计，不需要开启统计
+	Queued    int                   //异步写入队列中等待写入的条数，不需要开启统计
+	Dropped   int64                 //异步写入队列满被丢弃的条数，不需要开启统计
+}
+
+/******************************************************************************
+ @brief
+ 	耗时直方图，按2的幂次分桶，全部使用原子操作，不会给写日志增加锁竞争
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:44 	agent		创建
+*******************************************************************************/
+type latencyHist struct {
+	count   int64     //样本数量
+	max     int64     //最大耗时，单位纳秒
+	buckets [64]int64 //第i个桶记录耗时在[2^(i-1),2^i)纳秒之间的样本数量
+}
+
+var (
+	logProfile   int32       //是否开启自身开销统计
+	profileEntry latencyHist //单条日志耗时
+	profileLock  latencyHist //输出目标锁等待时间
+)
+
+/******************************************************************************
+ @brief
+ 	开启或关闭日志自身开销统计，开启后会记录每条日志的耗时和输出目标的锁等待时间，
+ 	通过Stats获取分位数，也可以通过StartPPROF启动的HTTP服务查看：
+ 		curl "http://127.0.0.1:18000/debug/logger/stats"
+ @author
+ 	agent
+ @param
+	enable				true开启，false关闭，开启时会清空之前的统计
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:44 	agent		创建
+*******************************************************************************/
+func SetSelfProfile(enable bool) {
+	if enable {
+		profileEntry.reset()
+		profileLock.reset()
+		atomic.StoreInt32(&logProfile, 1)
+	} else {
+		atomic.StoreInt32(&logProfile, 0)
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	获取日志自身开销统计，分位数按直方图分桶估算，误差在2倍以内
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	STATS				返回统计结果
+ @history
+ 	2026-10-16_14:44 	agent		创建
+ 	2026-10-16_14:53 	agent		增加工作协程异常次数
+ 	2026-10-16_14:57 	agent		增加慢操作耗时统计
+*******************************************************************************/
+func Stats() STATS {
+	return STATS{
+		Profiling: profiling(),
+		Entries:   atomic.LoadInt64(&profileEntry.count),
+		EntryP50:  profileEntry.percentile(0.50),
+		EntryP90:  profileEntry.percentile(0.90),
+		EntryP99:  profileEntry.percentile(0.99),
+		EntryMax:  time.Duration(atomic.LoadInt64(&profileEntry.max)),
+		LockWaits: atomic.LoadInt64(&profileLock.count),
+		LockP50:   profileLock.percentile(0.50),
+		LockP90:   profileLock.percentile(0.90),
+		LockP99:   profileLock.percentile(0.99),
+		LockMax:   time.Duration(atomic.LoadInt64(&profileLock.max)),
+		Panics:    panicStats(),
+		Slow:      SlowStats(),
+		Queued:    AsyncQueued(),
+		Dropped:   AsyncDropped(),
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	是否开启了自身开销统计
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	bool				开启返回true
+ @history
+ 	2026-10-16_14:44 	agent		创建
+*******************************************************************************/
+func profiling() bool {
+	return atomic.LoadInt32(&logProfile) != 0
+}
+
+/******************************************************************************
+ @brief
+ 	记录一个耗时样本
+ @author
+ 	agent
+ @param
+	d					耗时
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:44 	agent		创建
+*******************************************************************************/
+func (h *latencyHist) record(d time.Duration) {
+	n := int64(d)
+	if n < 0 {
+		n = 0
+	}
+
+	atomic.AddInt64(&h.buckets[bits.Len64(uint64(n))&63], 1)
+	atomic.AddInt64(&h.count, 1)
+
+	for {
+		max := atomic.LoadInt64(&h.max)
+		if n <= max || atomic.CompareAndSwapInt64(&h.max, max, n) {
+			return
+		}
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	估算分位数，返回样本所在桶的上界
+ @author
+ 	agent
+ @param
+	p					分位，取值范围(0,1]
+ @return
+ 	time.Duration		返回分位数，没有样本时返回0
+ @history
+ 	2026-10-16_14:44 	agent		创建
+*******************************************************************************/
+func (h *latencyHist) percentile(p float64) time.Duration {
+
+	var counts [64]int64
+	total := int64(0)
+	for i := range h.buckets {
+		counts[i] = atomic.LoadInt64(&h.buckets[i])
+		total += counts[i]
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	rank := int64(p*float64(total) + 0.5)
+	if rank < 1 {
+		rank = 1
+	}
+
+	max := time.Duration(atomic.LoadInt64(&h.max))
+	seen := int64(0)
+	for i, c := range counts {
+		seen += c
+		if seen < rank {
+			continue
+		}
+
+		//桶上界不超过实际最大值
+		upper := time.Duration(uint64(1)<<uint(i)) - 1
+		if i == 63 || upper > max {
+			upper = max
+		}
+		return upper
+	}
+
+	return max
+}
+
+/******************************************************************************
+ @brief
+ 	清空统计
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:44 	agent		创建
+*******************************************************************************/
+func (h *latencyHist) reset() {
+	for i := range h.buckets {
+		atomic.StoreInt64(&h.buckets[i], 0)
+	}
+	atomic.StoreInt64(&h.count, 0)
+	atomic.StoreInt64(&h.max, 0)
+}
@@ -0,0 +1,229 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	写入配额
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:01 	agent		创建
+*******************************************************************************/
+type writeQuota struct {
+	sync.Mutex         //配额统计线程锁
+	name       string  //配额名称，例如tenant:world2、category:battle
+	lines      int64   //每秒最多写入的条数，0表示不限制
+	bytes      int64   //每天最多写入的字节数，0表示不限制
+	second     int64   //当前统计的秒
+	count      int64   //当前秒已经写入的条数
+	day        int     //当前统计的日期，例如20261017
+	written    int64   //当天已经写入的字节数
+	exceeded   [2]bool //本秒的条数、当天的字节数是否已经发布过超额事件
+}
+
+/******************************************************************************
+ @brief
+ 	配额快照，修改时整体替换，写入日志时不需要加锁
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:01 	agent		创建
+*******************************************************************************/
+type quotaSnapshot struct {
+	tenants    map[string]*writeQuota    //租户的写入配额
+	categories map[*LOG_FILE]*writeQuota //分类日志文件的写入配额
+}
+
+var (
+	quotaLock  sync.Mutex   //配额修改线程锁
+	quotaValue atomic.Value //配额快照*quotaSnapshot
+)
+
+/******************************************************************************
+ @brief
+ 	设置租户的写入配额，带有tenant字段的日志超过配额后被丢弃，不需要开启多租户模式。
+ 	每次开始超额时发布LIFECYCLE_QUOTA_EXCEEDED事件，避免一个租户占满共享的磁盘
+ 		例：
+ 			logger.SetTenantQuota("world2", 2000, 5<<30)
+ 			logger.Subscribe("quota", func(ev logger.LIFECYCLE_EVENT) {
+ 				if ev.Kind == logger.LIFECYCLE_QUOTA_EXCEEDED {
+ 					alarm.Send("log quota exceeded: " + ev.Quota)
+ 				}
+ 			})
+ @author
+ 	agent
+ @param
+	tenant				租户
+	linesPerSec			每秒最多写入的条数，小于等于0表示不限制
+	bytesPerDay			每天最多写入的日志内容字节数，小于等于0表示不限制
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:01 	agent		创建
+*******************************************************************************/
+func SetTenantQuota(tenant string, linesPerSec int, bytesPerDay int64) {
+	setQuota(func(s *quotaSnapshot, q *writeQuota) {
+		if q == nil {
+			delete(s.tenants, tenant)
+		} else {
+			q.name = "tenant:" + tenant
+			s.tenants[tenant] = q
+		}
+	}, linesPerSec, bytesPerDay)
+}
+
+/******************************************************************************
+ @brief
+ 	设置分类日志的写入配额，超过配额后被丢弃，每次开始超额时发布LIFECYCLE_QUOTA_EXCEEDED事件
+ @author
+ 	agent
+ @param
+	linesPerSec			每秒最多写入的条数，小于等于0表示不限制
+	bytesPerDay			每天最多写入的日志内容字节数，小于等于0表示不限制
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:01 	agent		创建
+*******************************************************************************/
+func (c *CATEGORY) SetQuota(linesPerSec int, bytesPerDay int64) {
+	setQuota(func(s *quotaSnapshot, q *writeQuota) {
+		if q == nil {
+			delete(s.categories, c.file)
+		} else {
+			q.name = "category:" + c.name
+			s.categories[c.file] = q
+		}
+	}, linesPerSec, bytesPerDay)
+}
+
+/******************************************************************************
+ @brief
+ 	复制配额快照并修改，两个限制都小于等于0时删除配额
+ @author
+ 	agent
+ @param
+	update				修改快照，q为nil表示删除
+	linesPerSec			每秒最多写入的条数
+	bytesPerDay			每天最多写入的字节数
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:01 	agent		创建
+*******************************************************************************/
+func setQuota(update func(s *quotaSnapshot, q *writeQuota), linesPerSec int, bytesPerDay int64) {
+	quotaLock.Lock()
+	defer quotaLock.Unlock()
+
+	s := &quotaSnapshot{tenants: map[string]*writeQuota{}, categories: map[*LOG_FILE]*writeQuota{}}
+	if old, ok := quotaValue.Load().(*quotaSnapshot); ok {
+		for k, v := range old.tenants {
+			s.tenants[k] = v
+		}
+		for k, v := range old.categories {
+			s.categories[k] = v
+		}
+	}
+
+	var q *writeQuota
+	if linesPerSec > 0 || bytesPerDay > 0 {
+		q = &writeQuota{}
+		if linesPerSec > 0 {
+			q.lines = int64(linesPerSec)
+		}
+		if bytesPerDay > 0 {
+			q.bytes = bytesPerDay
+		}
+	}
+	update(s, q)
+
+	quotaValue.Store(s)
+}
+
+/******************************************************************************
+ @brief
+ 	判断日志是否超过租户或分类的写入配额
+ @author
+ 	agent
+ @param
+	f					日志文件
+	arg					日志内容
+ @return
+ 	bool				超过配额需要丢弃时返回true
+ @history
+ 	2026-10-16_15:01 	agent		创建
+*******************************************************************************/
+func quotaDrop(f *LOG_FILE, arg string) bool {
+	s, ok := quotaValue.Load().(*quotaSnapshot)
+	if !ok || (len(s.tenants) == 0 && len(s.categories) == 0) {
+		return false
+	}
+
+	now := time.Now()
+	if q, ok := s.categories[f]; ok && !q.allow(now, int64(len(arg))) {
+		return true
+	}
+
+	if len(s.tenants) > 0 {
+		if q, ok := s.tenants[tenantOf(arg)]; ok && !q.allow(now, int64(len(arg))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+/******************************************************************************
+ @brief
+ 	统计一条日志，超过配额时返回false，开始超额时发布事件
+ @author
+ 	agent
+ @param
+	now					当前时间
+	size				日志内容大小
+ @return
+ 	bool				没有超过配额时返回true
+ @history
+ 	2026-10-16_15:01 	agent		创建
+*******************************************************************************/
+func (q *writeQuota) allow(now time.Time, size int64) bool {
+	q.Lock()
+
+	second := now.Unix()
+	if q.second != second {
+		q.second, q.count, q.exceeded[0] = second, 0, false
+	}
+
+	y, m, d := now.Date()
+	day := y*10000 + int(m)*100 + d
+	if q.day != day {
+		q.day, q.written, q.exceeded[1] = day, 0, false
+	}
+
+	kind := -1
+	if q.lines > 0 && q.count >= q.lines {
+		kind = 0
+	} else if q.bytes > 0 && q.written+size > q.bytes {
+		kind = 1
+	}
+
+	if kind < 0 {
+		q.count += 1
+		q.written += size
+		q.Unlock()
+		return true
+	}
+
+	notify := !q.exceeded[kind]
+	q.exceeded[kind] = true
+	q.Unlock()
+
+	if notify {
+		lifecycle(LIFECYCLE_EVENT{Kind: LIFECYCLE_QUOTA_EXCEEDED, Quota: q.name})
+	}
+	return false
+}
@@ -0,0 +1,327 @@
+package logger
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	Redis Stream输出目标配置
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:19 	agent		创建
+*******************************************************************************/
+type REDIS_CONFIG struct {
+	Addr     string      //服务器地址host:port
+	Username string      //ACL用户名，为空时只使用密码认证
+	Password string      //密码，为空表示不认证
+	DB       int         //数据库编号
+	Stream   string      //Stream键名
+	MaxLen   int64       //Stream保留的大约条数，0表示不限制
+	TLS      *TLS_CONFIG //TLS配置，为nil时使用明文传输
+}
+
+/******************************************************************************
+ @brief
+ 	Redis Stream输出目标类结构，每条日志通过XADD追加为一条消息，
+ 	一次写入的多条日志使用管道批量发送，断线后下次写入时自动重连，不依赖第三方库
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:19 	agent		创建
+*******************************************************************************/
+type REDIS_WRITER struct {
+	sync.Mutex               //线程锁
+	cfg        REDIS_CONFIG  //配置
+	tlsConfig  *tls.Config   //TLS配置，为nil时使用明文传输
+	conn       net.Conn      //当前连接
+	reader     *bufio.Reader //当前连接的读取缓冲
+}
+
+/******************************************************************************
+ @brief
+ 	创建Redis Stream输出目标，运维工具可以用XREAD、XRANGE读取最近的日志，不需要访问日志文件。
+ 	每条消息包含level、caller、msg、公共字段和原始日志行line
+ 		例：
+ 			w, err := logger.NewRedisWriter(logger.REDIS_CONFIG{
+ 				Addr:   "10.0.0.1:6379",
+ 				Stream: "logs:gameserver",
+ 				MaxLen: 100000,
+ 			})
+ 			if err == nil {
+ 				logger.AddSink("redis", w, logger.BUFFER_INTERVAL, 64*1024, time.Second)
+ 			}
+
+ 			redis-cli XREVRANGE logs:gameserver + - COUNT 10
+ @author
+ 	agent
+ @param
+	cfg					配置
+ @return
+ 	*REDIS_WRITER		返回输出目标
+ 	error				配置无效或加载证书失败时返回错误信息
+ @history
+ 	2026-10-16_15:19 	agent		创建
+*******************************************************************************/
+func NewRedisWriter(cfg REDIS_CONFIG) (*REDIS_WRITER, error) {
+	if len(cfg.Addr) == 0 {
+		return nil, fmt.Errorf("logger: empty redis address")
+	}
+
+	if len(cfg.Stream) == 0 {
+		return nil, fmt.Errorf("logger: empty redis stream")
+	}
+
+	if cfg.DB < 0 || cfg.MaxLen < 0 {
+		return nil, fmt.Errorf("logger: invalid redis db %d or maxlen %d", cfg.DB, cfg.MaxLen)
+	}
+
+	w := &REDIS_WRITER{cfg: cfg}
+	if cfg.TLS != nil {
+		tlsConfig, err := cfg.TLS.build()
+		if err != nil {
+			return nil, err
+		}
+		w.tlsConfig = tlsConfig
+	}
+
+	return w, nil
+}
+
+/******************************************************************************
+ @brief
+ 	将日志行逐条追加到Stream
+ @author
+ 	agent
+ @param
+	b					要写入的日志行
+ @return
+ 	int					返回写入的字节数
+ 	error				写入失败时返回错误信息
+ @history
+ 	2026-10-16_15:19 	agent		创建
+*******************************************************************************/
+func (w *REDIS_WRITER) Write(b []byte) (int, error) {
+	w.Lock()
+	defer w.Unlock()
+
+	if w.conn == nil {
+		if err := w.connect(); err != nil {
+			return 0, err
+		}
+	}
+
+	entries, raws := parseEntries(b)
+	if len(entries) == 0 {
+		return len(b), nil
+	}
+
+	var cmds []byte
+	for i, e := range entries {
+		args := []string{"XADD", w.cfg.Stream}
+		if w.cfg.MaxLen > 0 {
+			args = append(args, "MAXLEN", "~", strconv.FormatInt(w.cfg.MaxLen, 10))
+		}
+		args = append(args, "*", "level", e.Level.String())
+		if len(e.File) > 0 {
+			args = append(args, "caller", e.File+":"+strconv.Itoa(e.Line))
+		}
+		args = append(args, "msg", e.Msg)
+		for _, field := range e.Fields {
+			args = append(args, field[0], field[1])
+		}
+		args = append(args, "line", strings.TrimRight(raws[i], "\n"))
+		cmds = redisCommand(cmds, args...)
+	}
+
+	//管道发送后按顺序读取全部应答，任何一条失败都断开连接
+	w.conn.SetDeadline(time.Now().Add(netWriteTimeout))
+	defer w.conn.SetDeadline(time.Time{})
+
+	_, err := w.conn.Write(cmds)
+	for i := 0; err == nil && i < len(entries); i++ {
+		err = redisRead(w.reader)
+	}
+	if err != nil {
+		w.conn.Close()
+		w.conn = nil
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+/******************************************************************************
+ @brief
+ 	断开连接
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	error				关闭失败时返回错误信息
+ @history
+ 	2026-10-16_15:19 	agent		创建
+*******************************************************************************/
+func (w *REDIS_WRITER) Close() error {
+	w.Lock()
+	defer w.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+/******************************************************************************
+ @brief
+ 	建立连接，按配置认证并选择数据库，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	error				连接或认证失败时返回错误信息
+ @history
+ 	2026-10-16_15:19 	agent		创建
+*******************************************************************************/
+func (w *REDIS_WRITER) connect() error {
+
+	dialer := &net.Dialer{Timeout: netDialTimeout}
+	var conn net.Conn
+	var err error
+	if w.tlsConfig != nil {
+		conn, err = tls.DialWithDialer(dialer, "tcp", w.cfg.Addr, w.tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", w.cfg.Addr)
+	}
+	if err != nil {
+		return err
+	}
+
+	var cmds []byte
+	replies := 0
+	if len(w.cfg.Password) > 0 {
+		if len(w.cfg.Username) > 0 {
+			cmds = redisCommand(cmds, "AUTH", w.cfg.Username, w.cfg.Password)
+		} else {
+			cmds = redisCommand(cmds, "AUTH", w.cfg.Password)
+		}
+		replies++
+	}
+	if w.cfg.DB > 0 {
+		cmds = redisCommand(cmds, "SELECT", strconv.Itoa(w.cfg.DB))
+		replies++
+	}
+
+	reader := bufio.NewReader(conn)
+	if replies > 0 {
+		conn.SetDeadline(time.Now().Add(netWriteTimeout))
+		_, err = conn.Write(cmds)
+		for i := 0; err == nil && i < replies; i++ {
+			err = redisRead(reader)
+		}
+		if err != nil {
+			conn.Close()
+			return err
+		}
+		conn.SetDeadline(time.Time{})
+	}
+
+	w.conn, w.reader = conn, reader
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	追加RESP格式的命令，每个参数编码为批量字符串
+ @author
+ 	agent
+ @param
+	buf					输出缓冲
+	args				命令和参数
+ @return
+ 	[]byte				返回追加后的缓冲
+ @history
+ 	2026-10-16_15:19 	agent		创建
+*******************************************************************************/
+func redisCommand(buf []byte, args ...string) []byte {
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, "\r\n"...)
+	for _, arg := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, "\r\n"...)
+		buf = append(buf, arg...)
+		buf = append(buf, "\r\n"...)
+	}
+
+	return buf
+}
+
+/******************************************************************************
+ @brief
+ 	读取并丢弃一个RESP应答，错误应答转换为错误信息
+ @author
+ 	agent
+ @param
+	r					读取缓冲
+ @return
+ 	error				读取失败或服务器返回错误时返回错误信息
+ @history
+ 	2026-10-16_15:19 	agent		创建
+*******************************************************************************/
+func redisRead(r *bufio.Reader) error {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return err
+	}
+
+	line = strings.TrimRight(line, "\r\n")
+	if len(line) == 0 {
+		return fmt.Errorf("logger: redis empty reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return nil
+	case '-':
+		return fmt.Errorf("logger: redis %s", line[1:])
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return fmt.Errorf("logger: redis bad reply %q", line)
+		}
+		if n < 0 {
+			return nil
+		}
+		_, err = io.CopyN(io.Discard, r, int64(n)+2)
+		return err
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return fmt.Errorf("logger: redis bad reply %q", line)
+		}
+		for i := 0; i < n; i++ {
+			if err := redisRead(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("logger: redis bad reply %q", line)
+}
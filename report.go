@@ -0,0 +1,216 @@
+package logger
+
+import (
+	"strings"
+	"unicode"
+)
+
+/******************************************************************************
+ @brief
+ 	生成带标题的水平分隔线，一般用于启动信息的分段
+ 		例：
+ 			fmt.Println(logger.SprintRule("LoginServer", 60, logger.CLR_CYAN))
+
+ 		输出：----------------------- LoginServer ------------------------
+ @author
+ 	agent
+ @param
+	title				标题，为空时只输出分隔线
+	width				分隔线总宽度，小于0时按0处理
+	fc					显示前景色
+ @return
+ 	string				返回生成的分隔线
+ @history
+ 	2026-10-16_14:16 	agent		创建
+ 	2026-10-16_17:00 	agent		宽度小于0时按0处理，不再panic
+*******************************************************************************/
+func SprintRule(title string, width int, fc COLOR) string {
+
+	if width < 0 {
+		width = 0
+	}
+
+	if len(title) == 0 {
+		return SprintColor(strings.Repeat("-", width), STYLE_DEFAULT, fc, CLR_DEFAULT)
+	}
+
+	title = " " + title + " "
+	rest := width - displayWidth(title)
+	if rest < 2 {
+		rest = 2
+	}
+
+	left := rest / 2
+	line := strings.Repeat("-", left) + title + strings.Repeat("-", rest-left)
+	return SprintColor(line, STYLE_DEFAULT, fc, CLR_DEFAULT)
+}
+
+/******************************************************************************
+ @brief
+ 	生成对齐的键值信息块，键使用指定颜色高亮显示
+ 		例：
+ 			fmt.Print(logger.SprintKV([][2]string{
+ 				{"version", "1.2.0"},
+ 				{"listen", ":8000"},
+ 			}, logger.CLR_GREEN))
+
+ 		输出：
+ 			version : 1.2.0
+ 			listen  : :8000
+ @author
+ 	agent
+ @param
+	kv					键值列表
+	fc					键的显示前景色
+ @return
+ 	string				返回生成的信息块，每行以换行结尾
+ @history
+ 	2026-10-16_14:16 	agent		创建
+*******************************************************************************/
+func SprintKV(kv [][2]string, fc COLOR) string {
+
+	width := 0
+	for _, item := range kv {
+		if w := displayWidth(item[0]); w > width {
+			width = w
+		}
+	}
+
+	buf := []string{}
+	for _, item := range kv {
+		key := SprintColor(padRight(item[0], width), STYLE_HIGHLIGHT, fc, CLR_DEFAULT)
+		buf = append(buf, key+" : "+item[1]+"\n")
+	}
+
+	return strings.Join(buf, "")
+}
+
+/******************************************************************************
+ @brief
+ 	生成带边框的简单表格，表头高亮显示，中文按两个字符宽度对齐
+ 		例：
+ 			fmt.Print(logger.SprintTable([]string{"模块", "状态"}, [][]string{
+ 				{"db", "ok"},
+ 				{"redis", "fail"},
+ 			}))
+
+ 		输出：
+ 			+-------+------+
+ 			| 模块  | 状态 |
+ 			+-------+------+
+ 			| db    | ok   |
+ 			| redis | fail |
+ 			+-------+------+
+ @author
+ 	agent
+ @param
+	header				表头
+	rows				表格内容
+ @return
+ 	string				返回生成的表格，每行以换行结尾
+ @history
+ 	2026-10-16_14:16 	agent		创建
+*******************************************************************************/
+func SprintTable(header []string, rows [][]string) string {
+
+	//计算每列宽度
+	widths := make([]int, len(header))
+	for i, cell := range header {
+		widths[i] = displayWidth(cell)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if w := displayWidth(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	border := "+"
+	for _, w := range widths {
+		border += strings.Repeat("-", w+2) + "+"
+	}
+	border += "\n"
+
+	row := func(cells []string, highlight bool) string {
+		line := "|"
+		for i, w := range widths {
+			cell := ""
+			if i < len(cells) {
+				cell = cells[i]
+			}
+			cell = padRight(cell, w)
+			if highlight {
+				cell = SprintColor(cell, STYLE_HIGHLIGHT, CLR_DEFAULT, CLR_DEFAULT)
+			}
+			line += " " + cell + " |"
+		}
+		return line + "\n"
+	}
+
+	buf := border
+	if len(header) > 0 {
+		buf += row(header, true) + border
+	}
+	for _, cells := range rows {
+		buf += row(cells, false)
+	}
+	if len(rows) > 0 {
+		buf += border
+	}
+
+	return buf
+}
+
+/******************************************************************************
+ @brief
+ 	计算字符串在终端上的显示宽度，中日韩文字和全角字符按两个字符宽度计算
+ @author
+ 	agent
+ @param
+	str					字符串
+ @return
+ 	int					返回显示宽度
+ @history
+ 	2026-10-16_14:16 	agent		创建
+*******************************************************************************/
+func displayWidth(str string) int {
+
+	width := 0
+	for _, r := range str {
+		switch {
+		case unicode.Is(unicode.Han, r), unicode.Is(unicode.Hangul, r),
+			unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r),
+			r >= 0x3000 && r <= 0x303F, r >= 0xFF00 && r <= 0xFF60:
+			width += 2
+		default:
+			width += 1
+		}
+	}
+
+	return width
+}
+
+/******************************************************************************
+ @brief
+ 	在字符串右侧补齐空格到指定的显示宽度
+ @author
+ 	agent
+ @param
+	str					字符串
+	width				显示宽度
+ @return
+ 	string				返回补齐后的字符串
+ @history
+ 	2026-10-16_14:16 	agent		创建
+*******************************************************************************/
+func padRight(str string, width int) string {
+	if n := width - displayWidth(str); n > 0 {
+		return str + strings.Repeat(" ", n)
+	}
+
+	return str
+}
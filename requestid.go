@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	requestIDHeader = "X-Request-ID" //请求ID使用的HTTP头
+	requestIDMax    = 128            //接受的客户端请求ID最大长度
+)
+
+/******************************************************************************
+ @brief
+ 	请求ID在context中的key类型
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:13 	agent		创建
+*******************************************************************************/
+type requestIDKey struct{}
+
+var (
+	logEntryID bool //每条日志是否附带id字段
+)
+
+/******************************************************************************
+ @brief
+ 	请求ID中间件，请求带有X-Request-ID头时沿用，否则使用SetIDGenerator设置的生成器生成，
+ 	请求ID写入应答头并保存在请求的context中，处理函数通过RequestLog获取带有请求ID的日志
+ 		例：
+ 			http.ListenAndServe(":8080", logger.RequestID(mux))
+
+ 			func handleRank(w http.ResponseWriter, r *http.Request) {
+ 				logger.RequestLog(r).Infof("rank page %s", r.URL.Query().Get("page"))
+ 			}
+
+ 		输出：INFO rank page 2 request_id=0192a6c4-6f1e-7000-8a3b-2c5d9e8f1a2b
+ @author
+ 	agent
+ @param
+	next				实际处理请求的Handler
+ @return
+ 	http.Handler		返回包装后的Handler
+ @history
+ 	2026-10-16_15:13 	agent		创建
+*******************************************************************************/
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if len(id) == 0 || len(id) > requestIDMax || !sanitized(id) {
+			id = NewID()
+		}
+
+		w.Header().Set(requestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+/******************************************************************************
+ @brief
+ 	获取RequestID中间件保存的请求ID
+ @author
+ 	agent
+ @param
+	ctx					请求的context
+ @return
+ 	string				返回请求ID，没有时返回空字符串
+ @history
+ 	2026-10-16_15:13 	agent		创建
+*******************************************************************************/
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+/******************************************************************************
+ @brief
+ 	创建带有request_id标签的主日志，请求没有经过RequestID中间件时不带标签
+ @author
+ 	agent
+ @param
+	r					HTTP请求
+ @return
+ 	*TAG_LOG			返回带标签的日志
+ @history
+ 	2026-10-16_15:13 	agent		创建
+*******************************************************************************/
+func RequestLog(r *http.Request) *TAG_LOG {
+	id := RequestIDFrom(r.Context())
+	if len(id) == 0 {
+		return &TAG_LOG{}
+	}
+
+	return With("request_id", id)
+}
+
+/******************************************************************************
+ @brief
+ 	设置每条日志是否附带id字段，ID由SetIDGenerator设置的生成器生成，
+ 	写入下游存储后可以用作去重和排序的主键
+ @author
+ 	agent
+ @param
+	isEntryID			是否附带id字段
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:13 	agent		创建
+*******************************************************************************/
+func SetEntryID(isEntryID bool) {
+	logEntryID = isEntryID
+}
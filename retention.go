@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"path/filepath"
+	"time"
+)
+
+var (
+	logRetention time.Duration //日志文件保留时间，0表示永久保留
+)
+
+/******************************************************************************
+ @brief
+ 	设置日志文件保留时间，每次切分时清理超过保留时间的日志文件和空文件，
+ 	只读保留模式的分类日志在其保留期内不会被删除
+ 		例：
+ 			//保留30天
+ 			logger.SetRetention(30 * 24 * time.Hour)
+ @author
+ 	agent
+ @param
+	maxAge				保留时间，小于等于0表示永久保留
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:27 	agent		创建
+*******************************************************************************/
+func SetRetention(maxAge time.Duration) {
+	logRetention = maxAge
+}
+
+/******************************************************************************
+ @brief
+ 	清理日志目录下属于该日志文件的过期文件和空文件，调用者需要持有锁。
+ 	只匹配本进程的文件名，开启主机名和进程ID时不会清理其它进程的文件；
+ 	空文件只清理本进程切分过的，其它进程刚创建还没有写入的文件不受影响
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:27 	agent		创建
+ 	2026-10-16_14:43 	agent		使用文件句柄
+ 	2026-10-16_14:46 	agent		发布文件删除事件
+ 	2026-10-16_15:00 	agent		支持单独设置保留时间和总大小上限
+ 	2026-10-16_15:34 	agent		只清理本进程的文件，空文件只清理本进程切分过的
+*******************************************************************************/
+func (f *LOG_FILE) sweep() {
+
+	current := ""
+	if h := f.current(); h != nil {
+		current = filepath.Clean(h.path)
+	}
+
+	maxAge := logRetention
+	if f.retention > 0 {
+		maxAge = f.retention
+	}
+
+	//时间部分为HH_MM_SS，没有开启主机名和进程ID时不会匹配到带主机名的文件
+	files, _ := logStorage.Glob(filepath.Join(f.log_dir, "*", f.basename()+".[0-9][0-9]_[0-9][0-9]_[0-9][0-9]*.log"))
+	kept := []string{}
+	now := time.Now()
+	for _, fn := range files {
+		if fn == current {
+			continue
+		}
+
+		fi, err := logStorage.Stat(fn)
+		if err != nil || fi.IsDir() {
+			continue
+		}
+
+		age := now.Sub(fi.ModTime())
+		expired := maxAge > 0 && age >= maxAge
+		if !expired && (fi.Size() > 0 || !f.rotated[fn]) {
+			kept = append(kept, fn)
+			continue
+		}
+
+		//只读保留期内拒绝删除
+		if f.worm > 0 && age < f.worm {
+			kept = append(kept, fn)
+			continue
+		}
+
+		if logStorage.Remove(fn) == nil {
+			delete(f.rotated, fn)
+			lifecycle(LIFECYCLE_EVENT{Kind: LIFECYCLE_FILE_PRUNED, File: fn})
+		}
+
+		//目录不为空时删除失败
+		logStorage.Remove(filepath.Dir(fn))
+	}
+
+	//切分后已经被pruneEmpty删除的文件不再记录
+	for fn := range f.rotated {
+		if !storageExist(fn) {
+			delete(f.rotated, fn)
+		}
+	}
+
+	f.sweepQuota(kept)
+}
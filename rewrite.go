@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+/******************************************************************************
+ @brief
+ 	改写规则类型
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:59 	agent		创建
+*******************************************************************************/
+type REWRITE_KIND int
+
+const (
+	REWRITE_FIELD  REWRITE_KIND = iota //字段改名，From=的字段改为To=
+	REWRITE_LEVEL                      //日志内容以旧的级别词From开头时，去掉级别词并改为Level级别
+	REWRITE_PREFIX                     //去掉日志内容开头的From
+)
+
+/******************************************************************************
+ @brief
+ 	日志改写规则，在生成日志行之前按顺序应用，用于迁移旧的调用代码
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:59 	agent		创建
+*******************************************************************************/
+type REWRITE_RULE struct {
+	Kind  REWRITE_KIND //规则类型
+	From  string       //旧的字段名、级别词或前缀
+	To    string       //新的字段名，只有REWRITE_FIELD使用
+	Level LEVEL        //新的日志级别，只有REWRITE_LEVEL使用
+}
+
+var (
+	rewriteRules atomic.Value //改写规则列表[]REWRITE_RULE，修改时整体替换
+)
+
+/******************************************************************************
+ @brief
+ 	设置日志改写规则，替换之前的全部规则，不传参数时清空规则
+ 		例：
+ 			logger.SetRewriteRules(
+ 				logger.REWRITE_RULE{Kind: logger.REWRITE_PREFIX, From: "[GameServer] "},
+ 				logger.REWRITE_RULE{Kind: logger.REWRITE_LEVEL, From: "warning:", Level: logger.WARN},
+ 				logger.REWRITE_RULE{Kind: logger.REWRITE_FIELD, From: "uid", To: "player_id"},
+ 			)
+ 			logger.Infof("[GameServer] warning: bag full uid=%d", 10086)
+
+ 		输出：WARN bag full player_id=10086
+ @author
+ 	agent
+ @param
+	rules				改写规则，按顺序应用
+ @return
+ 	error				规则不合法时返回错误信息，之前的规则保持不变
+ @history
+ 	2026-10-16_14:59 	agent		创建
+*******************************************************************************/
+func SetRewriteRules(rules ...REWRITE_RULE) error {
+
+	for _, rule := range rules {
+		switch rule.Kind {
+		case REWRITE_FIELD:
+			if !validFieldKey(rule.From) || !validFieldKey(rule.To) {
+				return fmt.Errorf("logger: rewrite field %q to %q has an invalid field name", rule.From, rule.To)
+			}
+		case REWRITE_LEVEL:
+			if len(strings.TrimSpace(rule.From)) == 0 || rule.Level < ALL || rule.Level > FATAL {
+				return fmt.Errorf("logger: rewrite level %q needs a level word and a valid level", rule.From)
+			}
+		case REWRITE_PREFIX:
+			if len(rule.From) == 0 {
+				return fmt.Errorf("logger: rewrite prefix needs a prefix")
+			}
+		default:
+			return fmt.Errorf("logger: unknown rewrite kind %d", rule.Kind)
+		}
+	}
+
+	rewriteRules.Store(append([]REWRITE_RULE{}, rules...))
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	按顺序应用改写规则
+ @author
+ 	agent
+ @param
+	ll					日志等级
+	arg					日志内容
+ @return
+ 	LEVEL				返回改写后的日志等级
+ 	string				返回改写后的日志内容
+ @history
+ 	2026-10-16_14:59 	agent		创建
+*******************************************************************************/
+func rewrite(ll LEVEL, arg string) (LEVEL, string) {
+	rules, _ := rewriteRules.Load().([]REWRITE_RULE)
+
+	for _, rule := range rules {
+		switch rule.Kind {
+		case REWRITE_FIELD:
+			arg = rewriteField(arg, rule.From, rule.To)
+		case REWRITE_LEVEL:
+			text := strings.TrimLeft(arg, " \t")
+			if len(text) >= len(rule.From) && strings.EqualFold(text[:len(rule.From)], rule.From) {
+				arg = strings.TrimLeft(text[len(rule.From):], " \t")
+				ll = rule.Level
+			}
+		case REWRITE_PREFIX:
+			arg = strings.TrimPrefix(arg, rule.From)
+		}
+	}
+
+	return ll, arg
+}
+
+/******************************************************************************
+ @brief
+ 	字段改名，只替换以空白或开头为边界的from=
+ @author
+ 	agent
+ @param
+	arg					日志内容
+	from				旧的字段名
+	to					新的字段名
+ @return
+ 	string				返回改名后的日志内容
+ @history
+ 	2026-10-16_14:59 	agent		创建
+*******************************************************************************/
+func rewriteField(arg, from, to string) string {
+	key := from + "="
+	if !strings.Contains(arg, key) {
+		return arg
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.Index(arg, key)
+		if i < 0 {
+			break
+		}
+
+		if i == 0 || strings.IndexByte(" \t\n", arg[i-1]) >= 0 {
+			b.WriteString(arg[:i])
+			b.WriteString(to)
+			b.WriteByte('=')
+		} else {
+			b.WriteString(arg[:i+len(key)])
+		}
+		arg = arg[i+len(key):]
+	}
+	b.WriteString(arg)
+
+	return b.String()
+}
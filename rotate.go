@@ -0,0 +1,203 @@
+package logger
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	按时间切分的设置，修改时整体替换，文件监控和写日志不需要加锁
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:50 	agent		创建
+*******************************************************************************/
+type rotateSetting struct {
+	interval time.Duration  //按时间间隔切分日志文件，0表示按天切分
+	loc      *time.Location //切分时间段对齐使用的时区，nil表示本地时区
+}
+
+var (
+	logRotate atomic.Value //按时间切分的设置*rotateSetting
+)
+
+/******************************************************************************
+ @brief
+ 	设置按时间间隔切分日志文件，文件边界按照时钟对齐（整点、整天），而不是进程启动时间
+ 		例：
+ 			//每小时整点切分
+ 			logger.SetRotateInterval(time.Hour, nil)
+
+ 			//按照UTC零点切分
+ 			logger.SetRotateInterval(24*time.Hour, time.UTC)
+ @author
+ 	agent
+ @param
+	interval			切分时间间隔，小于等于0表示按天切分，大于等于24小时按天对齐
+	loc					对齐使用的时区，nil表示本地时区
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:19 	agent		创建
+ 	2026-10-16_15:50 	agent		设置整体替换，文件监控读取不需要加锁
+*******************************************************************************/
+func SetRotateInterval(interval time.Duration, loc *time.Location) {
+	logRotate.Store(&rotateSetting{interval: interval, loc: loc})
+}
+
+/******************************************************************************
+ @brief
+ 	获取按时间切分的设置
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	time.Duration		返回切分时间间隔，0表示按天切分
+ 	*time.Location		返回对齐使用的时区，nil表示本地时区
+ @history
+ 	2026-10-16_15:50 	agent		创建
+*******************************************************************************/
+func rotateConfig() (time.Duration, *time.Location) {
+	if s, ok := logRotate.Load().(*rotateSetting); ok {
+		return s.interval, s.loc
+	}
+
+	return 0, nil
+}
+
+/******************************************************************************
+ @brief
+ 	获取对齐时区下的当前时间
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	time.Time			返回当前时间
+ @history
+ 	2026-10-16_14:19 	agent		创建
+*******************************************************************************/
+func rotateNow() time.Time {
+	now := time.Now()
+	if _, loc := rotateConfig(); loc != nil {
+		now = now.In(loc)
+	}
+
+	return now
+}
+
+/******************************************************************************
+ @brief
+ 	获取当前时间所在切分时间段的开始时间，用于日志文件时间戳
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	time.Time			返回时间段开始时间，未设置时间间隔时返回当前时间
+ @history
+ 	2026-10-16_14:19 	agent		创建
+*******************************************************************************/
+func rotateTimestamp() time.Time {
+	now := rotateNow()
+	interval, _ := rotateConfig()
+	if interval <= 0 {
+		return now
+	}
+
+	return alignTime(now, interval)
+}
+
+/******************************************************************************
+ @brief
+ 	获取下一个切分时间段的开始时间，文件监控在这个时间准时切分
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	time.Time			返回下一个时间段的开始时间，按天切分时为第二天零点
+ @history
+ 	2026-10-16_15:50 	agent		创建
+*******************************************************************************/
+func rotateBoundary() time.Time {
+	now := rotateNow()
+	next := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+
+	//每天最后一个时间段在零点结束
+	if interval, _ := rotateConfig(); interval > 0 && interval < 24*time.Hour {
+		if b := alignTime(now, interval).Add(interval); b.Before(next) {
+			next = b
+		}
+	}
+
+	return next
+}
+
+/******************************************************************************
+ @brief
+ 	将时间按照时间间隔对齐到当天零点开始的时间段
+ @author
+ 	agent
+ @param
+	t					需要对齐的时间
+	d					时间间隔
+ @return
+ 	time.Time			返回所在时间段的开始时间
+ @history
+ 	2026-10-16_14:19 	agent		创建
+*******************************************************************************/
+func alignTime(t time.Time, d time.Duration) time.Time {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if d >= 24*time.Hour {
+		return midnight
+	}
+
+	return midnight.Add(t.Sub(midnight) / d * d)
+}
+
+/******************************************************************************
+ @brief
+ 	立即切分主日志和所有分类日志文件，可以在收到信号时调用，也可以在测试中用于
+ 	确定性地触发切分
+ 		例：
+ 			signal.Notify(ch, syscall.SIGHUP)
+ 			go func() {
+ 				for range ch {
+ 					logger.Rotate()
+ 				}
+ 			}()
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:31 	agent		创建
+ 	2026-10-16_14:43 	agent		没有文件句柄时不切分
+ 	2026-10-16_15:00 	agent		同时切分租户日志文件
+ 	2026-10-16_15:05 	agent		交接给新进程后不再切分
+*******************************************************************************/
+func Rotate() {
+
+	//已经交接给新进程，由新进程切分
+	if handedOver() {
+		return
+	}
+
+	files := append(categoryFiles(), tenantFiles()...)
+	if logFile != nil {
+		files = append(files, logFile)
+	}
+
+	for _, f := range files {
+		f.Lock()
+		if f.current() != nil {
+			f.rename()
+		}
+		f.Unlock()
+	}
+}
@@ -0,0 +1,183 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	工作协程异常后的重启策略
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:53 	agent		创建
+*******************************************************************************/
+type RESTART_POLICY struct {
+	MaxRestarts int           //每个工作协程最多重启次数，0表示不重启，小于0表示不限制
+	Backoff     time.Duration //重启前的等待时间，避免连续异常时占满CPU
+}
+
+/******************************************************************************
+ @brief
+ 	带异常捕获的工作协程池
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:53 	agent		创建
+*******************************************************************************/
+type SAFE_POOL struct {
+	name string         //工作协程名称
+	wg   sync.WaitGroup //等待所有工作协程退出
+}
+
+var (
+	panicLock   sync.Mutex       //异常计数线程锁
+	panicCounts map[string]int64 //每个工作协程名称的异常次数
+)
+
+/******************************************************************************
+ @brief
+ 	启动带异常捕获的协程，协程中的panic会像CatchException一样写入异常目录，
+ 	并按名称计数，可以通过Stats查看
+ 		例：
+ 			logger.SafeGo("matchmaker", func() {
+ 				matchmaker.Run()
+ 			})
+ @author
+ 	agent
+ @param
+	name				协程名称，用于异常计数
+	fn					协程函数
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:53 	agent		创建
+*******************************************************************************/
+func SafeGo(name string, fn func()) {
+	go safeRun(name, fn)
+}
+
+/******************************************************************************
+ @brief
+ 	启动带异常捕获的工作协程池，每个工作协程异常退出后按照重启策略重新运行fn
+ 		例：
+ 			pool := logger.SafePool("db_writer", 4, logger.RESTART_POLICY{MaxRestarts: -1, Backoff: time.Second},
+ 				func(worker int) {
+ 					for job := range jobs {
+ 						job.Save()
+ 					}
+ 				})
+ 			pool.Wait()
+ @author
+ 	agent
+ @param
+	name				工作协程名称，用于异常计数
+	workers				工作协程数量
+	policy				重启策略
+	fn					工作协程函数，参数为工作协程序号，从0开始
+ @return
+ 	*SAFE_POOL			返回工作协程池
+ @history
+ 	2026-10-16_14:53 	agent		创建
+*******************************************************************************/
+func SafePool(name string, workers int, policy RESTART_POLICY, fn func(worker int)) *SAFE_POOL {
+	pool := &SAFE_POOL{name: name}
+
+	for i := 0; i < workers; i++ {
+		pool.wg.Add(1)
+		go func(worker int) {
+			defer pool.wg.Done()
+
+			for restarts := 0; ; restarts++ {
+				//正常返回时不再重启
+				if !safeRun(name, func() { fn(worker) }) {
+					return
+				}
+
+				if policy.MaxRestarts >= 0 && restarts >= policy.MaxRestarts {
+					return
+				}
+
+				if policy.Backoff > 0 {
+					time.Sleep(policy.Backoff)
+				}
+			}
+		}(i)
+	}
+
+	return pool
+}
+
+/******************************************************************************
+ @brief
+ 	等待所有工作协程退出，工作协程正常返回或者达到最多重启次数后退出
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:53 	agent		创建
+*******************************************************************************/
+func (p *SAFE_POOL) Wait() {
+	p.wg.Wait()
+}
+
+/******************************************************************************
+ @brief
+ 	运行函数并捕获异常
+ @author
+ 	agent
+ @param
+	name				协程名称，用于异常计数
+	fn					函数
+ @return
+ 	bool				发生异常时返回true
+ @history
+ 	2026-10-16_14:53 	agent		创建
+*******************************************************************************/
+func safeRun(name string, fn func()) (panicked bool) {
+	defer func() {
+		if err := recover(); err != nil {
+			panicked = true
+
+			panicLock.Lock()
+			if panicCounts == nil {
+				panicCounts = map[string]int64{}
+			}
+			panicCounts[name] += 1
+			panicLock.Unlock()
+
+			dumpException(err)
+		}
+	}()
+
+	fn()
+	return false
+}
+
+/******************************************************************************
+ @brief
+ 	获取每个工作协程名称的异常次数
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	map[string]int64	返回异常次数
+ @history
+ 	2026-10-16_14:53 	agent		创建
+*******************************************************************************/
+func panicStats() map[string]int64 {
+	panicLock.Lock()
+	defer panicLock.Unlock()
+
+	counts := make(map[string]int64, len(panicCounts))
+	for name, n := range panicCounts {
+		counts[name] = n
+	}
+
+	return counts
+}
@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	logSanitize bool //是否在写入前清理日志内容中的控制字符
+)
+
+/******************************************************************************
+ @brief
+ 	设置是否在写入前清理日志内容和字段，防止玩家聊天等用户输入伪造日志行或注入终端控制序列：
+ 	无效的UTF-8替换为U+FFFD，换行、回车转义为\n、\r，其它控制字符（包括ANSI转义序列开头的ESC）
+ 	和Unicode双向控制字符转义为\xNN、\uNNNN，制表符保持不变。
+ 	开启后多行日志会输出为一行，只需要清理部分内容时可以不开启，直接使用Sanitize
+ @author
+ 	agent
+ @param
+	isSanitize			是否清理
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:11 	agent		创建
+*******************************************************************************/
+func SetSanitize(isSanitize bool) {
+	logSanitize = isSanitize
+}
+
+/******************************************************************************
+ @brief
+ 	清理用户输入的字符串，规则与SetSanitize相同
+ 		例：
+ 			logger.Infof("chat player=%d text=%s", id, logger.Sanitize(text))
+ @author
+ 	agent
+ @param
+	s					原始字符串
+ @return
+ 	string				返回清理后的字符串
+ @history
+ 	2026-10-16_15:11 	agent		创建
+*******************************************************************************/
+func Sanitize(s string) string {
+	if sanitized(s) {
+		return s
+	}
+
+	s = strings.ToValidUTF8(s, "�")
+
+	var b strings.Builder
+	b.Grow(len(s) + 8)
+	for _, r := range s {
+		switch {
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t' || r == humanMark:
+			b.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, r)
+		case r >= 0x80 && r < 0xa0 || sanitizeBidi(r):
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+/******************************************************************************
+ @brief
+ 	清理日志内容，保留末尾的换行
+ @author
+ 	agent
+ @param
+	arg					日志内容
+ @return
+ 	string				返回清理后的日志内容
+ @history
+ 	2026-10-16_15:11 	agent		创建
+*******************************************************************************/
+func sanitizeArg(arg string) string {
+	if strings.HasSuffix(arg, "\n") {
+		return Sanitize(arg[:len(arg)-1]) + "\n"
+	}
+
+	return Sanitize(arg)
+}
+
+/******************************************************************************
+ @brief
+ 	判断字符串是否不需要清理，大多数日志只有可打印的ASCII字符，不需要重新生成
+ @author
+ 	agent
+ @param
+	s					字符串
+ @return
+ 	bool				不需要清理时返回true
+ @history
+ 	2026-10-16_15:11 	agent		创建
+*******************************************************************************/
+func sanitized(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= utf8.RuneSelf || (c < 0x20 && c != '\t' && c != humanMark) || c == 0x7f {
+			return false
+		}
+	}
+
+	return true
+}
+
+/******************************************************************************
+ @brief
+ 	判断是否为Unicode双向控制字符，这些字符可以让显示的文本顺序与实际内容不同
+ @author
+ 	agent
+ @param
+	r					字符
+ @return
+ 	bool				是双向控制字符时返回true
+ @history
+ 	2026-10-16_15:11 	agent		创建
+*******************************************************************************/
+func sanitizeBidi(r rune) bool {
+	return r == 0x061c || r == 0x200e || r == 0x200f || (r >= 0x202a && r <= 0x202e) || (r >= 0x2066 && r <= 0x2069)
+}
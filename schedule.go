@@ -0,0 +1,337 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	日志级别时间段规则
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:09 	agent		创建
+*******************************************************************************/
+type levelSchedule struct {
+	begin int   //开始时间，当天的第几分钟
+	end   int   //结束时间，当天的第几分钟
+	level LEVEL //时间段内使用的日志级别
+}
+
+/******************************************************************************
+ @brief
+ 	配置文件中的日志级别时间段规则，可以直接嵌入服务自己的配置结构
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:35 	agent		创建
+*******************************************************************************/
+type LEVEL_SCHEDULE struct {
+	Begin string `json:"begin"` //开始时间，格式为HH:MM
+	End   string `json:"end"`   //结束时间，格式为HH:MM，小于Begin表示跨天
+	Level string `json:"level"` //时间段内使用的级别名称，例如debug
+}
+
+var (
+	scheduleLock sync.Mutex            //时间段规则线程锁
+	schedules    []levelSchedule       //时间段规则列表
+	scheduleOnce sync.Once             //时间段监控只启动一次
+	logLevelBase LEVEL           = ALL //不在任何时间段内时使用的日志级别
+	boostLevel   LEVEL                 //临时提升的日志级别
+	boostUntil   time.Time             //临时提升的截止时间
+	boostTimer   *time.Timer           //临时提升到期后的恢复定时器
+)
+
+/******************************************************************************
+ @brief
+ 	添加日志级别时间段规则，在[begin,end)时间段内使用level级别，其它时间使用SetLevel设置的级别
+ 		例：
+ 			logger.SetLevel(logger.INFO)
+ 			logger.AddLevelSchedule("02:00", "03:00", logger.DEBUG)
+
+ 		那么每天02:00到03:00之间会记录DEBUG日志，其它时间只记录INFO及以上日志
+ @author
+ 	agent
+ @param
+	begin				开始时间，格式为HH:MM
+	end					结束时间，格式为HH:MM，小于begin表示跨天
+	level				时间段内使用的级别
+ @return
+ 	error				时间格式错误时返回错误信息
+ @history
+ 	2026-10-16_14:09 	agent		创建
+*******************************************************************************/
+func AddLevelSchedule(begin, end string, level LEVEL) error {
+
+	b, err := parseClock(begin)
+	if err != nil {
+		return err
+	}
+
+	e, err := parseClock(end)
+	if err != nil {
+		return err
+	}
+
+	scheduleLock.Lock()
+	schedules = append(schedules, levelSchedule{begin: b, end: e, level: level})
+	scheduleLock.Unlock()
+
+	//启动时间段监控模块
+	scheduleOnce.Do(func() {
+		go scheduleMonitor()
+	})
+
+	scheduleCheck()
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	使用配置替换全部日志级别时间段规则，任何一条规则无效时不做修改
+ 		例：
+ 			type ServerConfig struct {
+ 				LogSchedules []logger.LEVEL_SCHEDULE `json:"log_schedules"`
+ 			}
+
+ 			logger.SetLevelSchedules(cfg.LogSchedules)
+ @author
+ 	agent
+ @param
+	list				时间段规则列表，为空时清除所有规则
+ @return
+ 	error				时间或级别无效时返回错误信息
+ @history
+ 	2026-10-16_15:35 	agent		创建
+*******************************************************************************/
+func SetLevelSchedules(list []LEVEL_SCHEDULE) error {
+
+	parsed := make([]levelSchedule, 0, len(list))
+	for _, ls := range list {
+		b, err := parseClock(ls.Begin)
+		if err != nil {
+			return err
+		}
+
+		e, err := parseClock(ls.End)
+		if err != nil {
+			return err
+		}
+
+		level, err := ParseLevel(ls.Level)
+		if err != nil {
+			return err
+		}
+
+		parsed = append(parsed, levelSchedule{begin: b, end: e, level: level})
+	}
+
+	if len(parsed) > 0 {
+		scheduleOnce.Do(func() {
+			go scheduleMonitor()
+		})
+	}
+
+	scheduleLock.Lock()
+	defer scheduleLock.Unlock()
+
+	schedules = parsed
+	storeLevel(scheduledLevel(time.Now()))
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	从JSON配置文件加载日志级别时间段规则，替换全部已有规则
+ 		例：
+ 			logger.LoadLevelSchedules("./conf/log_schedule.json")
+
+ 		log_schedule.json：
+ 			[
+ 				{"begin": "02:00", "end": "03:00", "level": "debug"},
+ 				{"begin": "20:00", "end": "23:00", "level": "warn"}
+ 			]
+ @author
+ 	agent
+ @param
+	fn					配置文件路径
+ @return
+ 	error				读取失败或配置无效时返回错误信息
+ @history
+ 	2026-10-16_15:35 	agent		创建
+*******************************************************************************/
+func LoadLevelSchedules(fn string) error {
+	data, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return fmt.Errorf("logger: level schedule %s: %v", fn, err)
+	}
+
+	var list []LEVEL_SCHEDULE
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("logger: level schedule %s: %v", fn, err)
+	}
+
+	return SetLevelSchedules(list)
+}
+
+/******************************************************************************
+ @brief
+ 	清除所有日志级别时间段规则，日志级别恢复为SetLevel设置的级别
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:09 	agent		创建
+*******************************************************************************/
+func ClearLevelSchedule() {
+	scheduleLock.Lock()
+	defer scheduleLock.Unlock()
+
+	schedules = nil
+	storeLevel(scheduledLevel(time.Now()))
+}
+
+/******************************************************************************
+ @brief
+ 	临时调整日志级别，duration时间后自动恢复，期间的优先级高于时间段规则
+ 		例：
+ 			logger.BoostLevel(logger.DEBUG, 10*time.Minute)
+
+ 		也可以通过AdminHandler挂载的管理接口调整：
+ 			curl -X POST "http://127.0.0.1:18000/debug/logger/boost?level=DEBUG&duration=10m"
+ @author
+ 	agent
+ @param
+	level				临时使用的日志级别
+	duration			持续时间，小于等于0表示立即恢复
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:09 	agent		创建
+*******************************************************************************/
+func BoostLevel(level LEVEL, duration time.Duration) {
+	scheduleLock.Lock()
+	defer scheduleLock.Unlock()
+
+	if boostTimer != nil {
+		boostTimer.Stop()
+		boostTimer = nil
+	}
+
+	boostLevel = level
+	boostUntil = time.Now().Add(duration)
+	if duration > 0 {
+		boostTimer = time.AfterFunc(duration, scheduleCheck)
+	}
+
+	storeLevel(scheduledLevel(time.Now()))
+}
+
+/******************************************************************************
+ @brief
+ 	解析HH:MM格式的时间
+ @author
+ 	agent
+ @param
+	clock				时间字符串
+ @return
+ 	int					返回当天的第几分钟
+ 	error				格式错误时返回错误信息
+ @history
+ 	2026-10-16_14:09 	agent		创建
+*******************************************************************************/
+func parseClock(clock string) (int, error) {
+
+	var hour, minute int
+	if _, err := fmt.Sscanf(clock, "%d:%d", &hour, &minute); err != nil {
+		return 0, fmt.Errorf("logger: invalid clock %q: %v", clock, err)
+	}
+
+	if hour < 0 || hour > 24 || minute < 0 || minute > 59 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("logger: invalid clock %q", clock)
+	}
+
+	return hour*60 + minute, nil
+}
+
+/******************************************************************************
+ @brief
+ 	根据当前时间计算应当使用的日志级别
+ @author
+ 	agent
+ @param
+	now					当前时间
+ @return
+ 	LEVEL				返回日志级别
+ @history
+ 	2026-10-16_14:09 	agent		创建
+ 	2026-10-16_14:09 	agent		支持临时调整级别
+*******************************************************************************/
+func scheduledLevel(now time.Time) LEVEL {
+
+	//临时调整优先
+	if now.Before(boostUntil) {
+		return boostLevel
+	}
+
+	minute := now.Hour()*60 + now.Minute()
+	for _, s := range schedules {
+		if s.begin <= s.end {
+			if minute >= s.begin && minute < s.end {
+				return s.level
+			}
+		} else if minute >= s.begin || minute < s.end {
+			return s.level
+		}
+	}
+
+	return logLevelBase
+}
+
+/******************************************************************************
+ @brief
+ 	时间段监控函数，循环检测是否需要切换日志级别
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:09 	agent		创建
+*******************************************************************************/
+func scheduleMonitor() {
+	timer := time.NewTicker(10 * time.Second)
+	for {
+		select {
+		case <-timer.C:
+			scheduleCheck()
+		}
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	检查当前时间所在的时间段，并切换日志级别
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:09 	agent		创建
+*******************************************************************************/
+func scheduleCheck() {
+	scheduleLock.Lock()
+	defer scheduleLock.Unlock()
+
+	storeLevel(scheduledLevel(time.Now()))
+}
@@ -0,0 +1,262 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	字段类型
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:14 	agent		创建
+*******************************************************************************/
+type FIELD_TYPE int
+
+const (
+	FIELD_STRING   FIELD_TYPE = iota //任意字符串
+	FIELD_INT                        //整数
+	FIELD_FLOAT                      //浮点数
+	FIELD_BOOL                       //true或false
+	FIELD_DURATION                   //time.ParseDuration可以解析的时间长度
+)
+
+/******************************************************************************
+ @brief
+ 	字段约束的处理方式
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:14 	agent		创建
+*******************************************************************************/
+type SCHEMA_MODE int
+
+const (
+	SCHEMA_OFF  SCHEMA_MODE = iota //不检查，默认
+	SCHEMA_WARN                    //输出日志，每个日志名称的每个问题只提示一次
+	SCHEMA_DROP                    //丢弃不符合约束的日志，第一次丢弃时提示
+)
+
+var (
+	schemaLock   sync.Mutex   //字段约束修改线程锁
+	schemaValue  atomic.Value //字段约束map[string]map[string]FIELD_TYPE，修改时整体替换
+	schemaMode   int32        //字段约束的处理方式
+	schemaWarned sync.Map     //已经提示过的问题
+)
+
+/******************************************************************************
+ @brief
+ 	注册日志名称允许的字段和类型，同一名称重复注册时合并，团队成员较多时保持结构化日志的一致。
+ 	公共字段和标签也会参与检查，需要一起注册
+ 		例：
+ 			logger.RegisterFields("battle", map[string]logger.FIELD_TYPE{
+ 				"room":   logger.FIELD_INT,
+ 				"player": logger.FIELD_INT,
+ 				"cost":   logger.FIELD_DURATION,
+ 			})
+ 			logger.SetSchemaMode(logger.SCHEMA_WARN)
+
+ 		输出：WARN logger: schema battle field room expects int, got "abc"
+ @author
+ 	agent
+ @param
+	name				日志名称，空字符串为主日志，分类名称为分类日志
+	fields				字段名称和类型
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:14 	agent		创建
+*******************************************************************************/
+func RegisterFields(name string, fields map[string]FIELD_TYPE) {
+	schemaLock.Lock()
+	defer schemaLock.Unlock()
+
+	old, _ := schemaValue.Load().(map[string]map[string]FIELD_TYPE)
+	schemas := make(map[string]map[string]FIELD_TYPE, len(old)+1)
+	for k, v := range old {
+		schemas[k] = v
+	}
+
+	merged := make(map[string]FIELD_TYPE, len(schemas[name])+len(fields))
+	for k, v := range schemas[name] {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	schemas[name] = merged
+
+	schemaValue.Store(schemas)
+}
+
+/******************************************************************************
+ @brief
+ 	设置字段约束的处理方式，只检查注册过字段的日志名称
+ @author
+ 	agent
+ @param
+	mode				处理方式
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:14 	agent		创建
+*******************************************************************************/
+func SetSchemaMode(mode SCHEMA_MODE) {
+	atomic.StoreInt32(&schemaMode, int32(mode))
+}
+
+/******************************************************************************
+ @brief
+ 	获取字段类型的名称
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	string				返回类型名称
+ @history
+ 	2026-10-16_15:14 	agent		创建
+*******************************************************************************/
+func (t FIELD_TYPE) String() string {
+	switch t {
+	case FIELD_STRING:
+		return "string"
+	case FIELD_INT:
+		return "int"
+	case FIELD_FLOAT:
+		return "float"
+	case FIELD_BOOL:
+		return "bool"
+	case FIELD_DURATION:
+		return "duration"
+	}
+
+	return "unknown"
+}
+
+/******************************************************************************
+ @brief
+ 	检查日志内容中的字段，有问题时按照处理方式提示或丢弃
+ @author
+ 	agent
+ @param
+	f					日志文件
+	arg					要输出的内容
+ @return
+ 	bool				需要丢弃时返回true
+ 	string				第一次发现问题时返回提示内容，否则返回空字符串
+ @history
+ 	2026-10-16_15:14 	agent		创建
+*******************************************************************************/
+func schemaCheck(f *LOG_FILE, arg string) (bool, string) {
+	mode := SCHEMA_MODE(atomic.LoadInt32(&schemaMode))
+	if mode == SCHEMA_OFF {
+		return false, ""
+	}
+
+	schemas, _ := schemaValue.Load().(map[string]map[string]FIELD_TYPE)
+	name := logName(f)
+	schema, ok := schemas[name]
+	if !ok {
+		return false, ""
+	}
+
+	_, fields := parseFields(strings.TrimRight(arg, "\n") + fieldsText())
+	for _, kv := range fields {
+		kind, problem := "", ""
+		if t, ok := schema[kv[0]]; !ok {
+			kind = "unregistered"
+			problem = fmt.Sprintf("logger: schema %s field %s is not registered", schemaName(name), kv[0])
+		} else if !schemaValid(t, kv[1]) {
+			kind = "type"
+			problem = fmt.Sprintf("logger: schema %s field %s expects %s, got %q", schemaName(name), kv[0], t, kv[1])
+		} else {
+			continue
+		}
+
+		//同一个字段的同一种问题只提示一次，避免提示本身刷屏
+		notice := ""
+		if _, warned := schemaWarned.LoadOrStore(name+"\x00"+kv[0]+"\x00"+kind, true); !warned {
+			notice = problem
+		}
+
+		return mode == SCHEMA_DROP, notice
+	}
+
+	return false, ""
+}
+
+/******************************************************************************
+ @brief
+ 	检查字段值是否符合类型
+ @author
+ 	agent
+ @param
+	t					字段类型
+	value				字段值
+ @return
+ 	bool				符合时返回true
+ @history
+ 	2026-10-16_15:14 	agent		创建
+*******************************************************************************/
+func schemaValid(t FIELD_TYPE, value string) bool {
+	var err error
+	switch t {
+	case FIELD_INT:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case FIELD_FLOAT:
+		_, err = strconv.ParseFloat(value, 64)
+	case FIELD_BOOL:
+		_, err = strconv.ParseBool(value)
+	case FIELD_DURATION:
+		_, err = time.ParseDuration(value)
+	}
+
+	return err == nil
+}
+
+/******************************************************************************
+ @brief
+ 	获取提示中显示的日志名称
+ @author
+ 	agent
+ @param
+	name				日志名称
+ @return
+ 	string				主日志返回main
+ @history
+ 	2026-10-16_15:14 	agent		创建
+*******************************************************************************/
+func schemaName(name string) string {
+	if len(name) == 0 {
+		return "main"
+	}
+
+	return name
+}
+
+/******************************************************************************
+ @brief
+ 	获取日志文件对应的日志名称
+ @author
+ 	agent
+ @param
+	f					日志文件
+ @return
+ 	string				主日志返回空字符串，分类日志返回分类名称
+ @history
+ 	2026-10-16_15:14 	agent		创建
+*******************************************************************************/
+func logName(f *LOG_FILE) string {
+	if f == nil || f == logFile {
+		return ""
+	}
+
+	return f.log_filename
+}
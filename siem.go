@@ -0,0 +1,186 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	安全审计日志格式
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:49 	agent		创建
+*******************************************************************************/
+type SIEM_FORMAT int
+
+const (
+	SIEM_CEF  SIEM_FORMAT = iota //ArcSight Common Event Format
+	SIEM_LEEF                    //IBM QRadar Log Event Extended Format 1.0
+)
+
+/******************************************************************************
+ @brief
+ 	安全审计事件
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:49 	agent		创建
+*******************************************************************************/
+type SIEM_EVENT struct {
+	ID       string            //事件类型标识，例如login_failed
+	Name     string            //事件描述，只有CEF格式输出
+	Severity int               //严重程度，0到10
+	Fields   map[string]string //扩展字段，建议使用SIEM标准字段名，例如suser、src、act
+}
+
+/******************************************************************************
+ @brief
+ 	安全审计日志类结构，将登录、管理操作等审计事件编码为CEF或LEEF格式写入分类日志，
+ 	SOC团队可以直接将分类日志文件接入SIEM
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:49 	agent		创建
+*******************************************************************************/
+type SIEM_LOG struct {
+	category *CATEGORY   //事件写入的分类
+	format   SIEM_FORMAT //输出格式
+	header   string      //已经转义的厂商、产品、版本
+}
+
+/******************************************************************************
+ @brief
+ 	创建安全审计日志
+ 		例：
+ 			audit := logger.SIEMLog("audit", logger.SIEM_CEF, "Acme", "GameServer", "1.0")
+ 			audit.Write(logger.SIEM_EVENT{
+ 				ID:       "login_failed",
+ 				Name:     "Login failed",
+ 				Severity: 5,
+ 				Fields:   map[string]string{"suser": account, "src": ip},
+ 			})
+
+ 		输出：CEF:0|Acme|GameServer|1.0|login_failed|Login failed|5|rt=1792087800000 src=10.0.0.1 suser=tom
+ @author
+ 	agent
+ @param
+	category			分类名称，事件写入该分类的日志文件
+	format				输出格式
+	vendor				厂商名称
+	product				产品名称
+	version				产品版本
+ @return
+ 	*SIEM_LOG			返回安全审计日志
+ @history
+ 	2026-10-16_14:49 	agent		创建
+*******************************************************************************/
+func SIEMLog(category string, format SIEM_FORMAT, vendor, product, version string) *SIEM_LOG {
+	header := strings.Join([]string{siemHeader(vendor), siemHeader(product), siemHeader(version)}, "|")
+	return &SIEM_LOG{category: Category(category), format: format, header: header}
+}
+
+/******************************************************************************
+ @brief
+ 	写入一条安全审计事件
+ @author
+ 	agent
+ @param
+	ev					安全审计事件
+ @return
+ 	error				严重程度无效或日志文件不可用时返回错误信息
+ @history
+ 	2026-10-16_14:49 	agent		创建
+*******************************************************************************/
+func (s *SIEM_LOG) Write(ev SIEM_EVENT) error {
+
+	if ev.Severity < 0 || ev.Severity > 10 {
+		return fmt.Errorf("logger: siem %s severity %d out of range 0-10", s.category.name, ev.Severity)
+	}
+
+	keys := make([]string, 0, len(ev.Fields))
+	for k := range ev.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	now := strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10)
+
+	var line string
+	switch s.format {
+	case SIEM_LEEF:
+		//LEEF的属性以制表符分隔，严重程度为sev属性
+		attrs := []string{"devTime=" + now, "sev=" + strconv.Itoa(ev.Severity)}
+		for _, k := range keys {
+			attrs = append(attrs, k+"="+siemLEEFValue(ev.Fields[k]))
+		}
+		line = fmt.Sprintf("LEEF:1.0|%s|%s|%s\n", s.header, siemHeader(ev.ID), strings.Join(attrs, "\t"))
+	default:
+		attrs := []string{"rt=" + now}
+		for _, k := range keys {
+			attrs = append(attrs, k+"="+siemCEFValue(ev.Fields[k]))
+		}
+		line = fmt.Sprintf("CEF:0|%s|%s|%s|%d|%s\n", s.header, siemHeader(ev.ID), siemHeader(ev.Name), ev.Severity, strings.Join(attrs, " "))
+	}
+
+	f := s.category.file
+	if f.current() == nil {
+		return fmt.Errorf("logger: siem %s has no log file, call Initialize first", s.category.name)
+	}
+
+	f.write([]byte(line))
+
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	转义CEF和LEEF头部字段中的反斜杠和竖线，换行替换为空格
+ @author
+ 	agent
+ @param
+	s					原始字符串
+ @return
+ 	string				返回转义后的字符串
+ @history
+ 	2026-10-16_14:49 	agent		创建
+*******************************************************************************/
+func siemHeader(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", " ", "\n", " ").Replace(s)
+}
+
+/******************************************************************************
+ @brief
+ 	转义CEF扩展字段的值
+ @author
+ 	agent
+ @param
+	s					原始字符串
+ @return
+ 	string				返回转义后的字符串
+ @history
+ 	2026-10-16_14:49 	agent		创建
+*******************************************************************************/
+func siemCEFValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r", `\r`, "\n", `\n`).Replace(s)
+}
+
+/******************************************************************************
+ @brief
+ 	转换LEEF属性的值，制表符和换行会破坏属性分隔，替换为空格
+ @author
+ 	agent
+ @param
+	s					原始字符串
+ @return
+ 	string				返回转换后的字符串
+ @history
+ 	2026-10-16_14:49 	agent		创建
+*******************************************************************************/
+func siemLEEFValue(s string) string {
+	return strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").Replace(s)
+}
@@ -0,0 +1,663 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type BUFFER_MODE int //输出目标缓冲方式
+
+const (
+	BUFFER_NONE     BUFFER_MODE = iota //不缓冲，每条日志直接写入
+	BUFFER_SIZE                        //缓冲达到指定大小后写入
+	BUFFER_INTERVAL                    //按时间间隔写入
+)
+
+/******************************************************************************
+ @brief
+ 	扩展输出目标类结构，日志会按照与日志文件相同的格式写入到每个输出目标
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:11 	agent		创建
+ 	2026-10-16_14:40 	agent		支持设置时间精度
+ 	2026-10-16_14:41 	agent		支持写入超时
+ 	2026-10-16_14:50 	agent		支持易读字段
+ 	2026-10-16_14:51 	agent		支持设置时区
+ 	2026-10-16_15:06 	agent		支持按路由标签接收日志
+ 	2026-10-16_15:48 	agent		超时写入使用常驻的写入协程
+ 	2026-10-16_16:40 	agent		保留超时写入的日志，移除后拒绝写入
+*******************************************************************************/
+type LOG_SINK struct {
+	sync.Mutex                   //线程锁
+	name       string            //输出目标名称
+	writer     io.Writer         //输出目标实例
+	mode       BUFFER_MODE       //缓冲方式
+	size       int               //缓冲大小
+	interval   time.Duration     //缓冲时间间隔
+	buffer     bytes.Buffer      //缓冲区
+	stop       chan struct{}     //停止定时写入
+	spillDir   string            //写入失败时的磁盘缓存目录
+	spillMax   int64             //磁盘缓存最大大小
+	spilled    int64             //磁盘缓存中待重发的大小
+	spillSeq   int               //磁盘缓存当前分段序号
+	compressor COMPRESSOR        //压缩算法
+	lastErr    error             //最近一次写入失败的原因
+	lastErrAt  time.Time         //最近一次写入失败的时间
+	retries    int64             //连续写入失败的次数
+	precision  TIME_PRECISION    //日志时间精度
+	timeout    time.Duration     //写入超时时间，0表示不限制
+	writes     chan []byte       //设置了超时时间时，交给写入协程的日志
+	results    chan sinkResult   //写入协程的写入结果
+	pending    bool              //超时的写入是否还没有取回结果
+	pendingLen [2]int            //超时的写入压缩前和压缩后的大小
+	pendingBuf []byte            //超时的新日志，写入失败时缓存到磁盘，重发磁盘缓存时为空
+	inflight   string            //超时的写入重发的磁盘缓存分段，为空表示不是重发
+	timeouts   int64             //写入超时或因上次写入未完成而跳过的次数
+	humanize   bool              //是否输出易读字段
+	location   *time.Location    //时区，nil表示由日志flag决定
+	labels     map[string]string //只接收带有这些路由标签的日志，为空时接收所有日志
+	removed    bool              //是否已经移除，移除后不再写入
+}
+
+/******************************************************************************
+ @brief
+ 	写入协程的一次写入结果
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:48 	agent		创建
+*******************************************************************************/
+type sinkResult struct {
+	n   int   //写入的字节数
+	err error //写入失败的原因
+}
+
+/******************************************************************************
+ @brief
+ 	输出目标健康状态
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:13 	agent		创建
+ 	2026-10-16_14:41 	agent		增加写入超时次数
+*******************************************************************************/
+type SINK_STATUS struct {
+	Name        string    //输出目标名称
+	Connected   bool      //最近一次写入是否成功
+	LastError   string    //最近一次写入失败的原因
+	LastErrorAt time.Time //最近一次写入失败的时间
+	RetryCount  int64     //连续写入失败的次数
+	QueueDepth  int       //内存缓冲区中等待写入的大小
+	Spilled     int64     //磁盘缓存中等待重发的大小
+	Timeouts    int64     //写入超时或被跳过的次数
+}
+
+var (
+	sinkLock sync.Mutex   //输出目标列表修改线程锁
+	logSinks atomic.Value //输出目标列表[]*LOG_SINK，修改时整体替换，写入日志不需要加锁
+
+	errSinkTimeout = errors.New("logger: sink write timeout")                         //写入超时
+	errSinkBusy    = errors.New("logger: sink skipped, previous write still pending") //上次超时的写入仍未完成
+	errSinkRemoved = errors.New("logger: sink removed")                               //输出目标已经移除
+)
+
+/******************************************************************************
+ @brief
+ 	添加扩展输出目标，同名的输出目标会被替换
+ 		例：
+ 			//终端保持实时输出，网络输出目标每5秒批量发送一次
+ 			logger.AddSink("collector", conn, logger.BUFFER_INTERVAL, 64*1024, 5*time.Second)
+ @author
+ 	agent
+ @param
+	name				输出目标名称
+	w					输出目标实例
+	mode				缓冲方式
+	size				缓冲大小，BUFFER_SIZE时达到此大小写入，必须大于0，BUFFER_INTERVAL时大于0表示缓冲上限
+	interval			缓冲时间间隔，仅BUFFER_INTERVAL有效，必须大于0
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:11 	agent		创建
+ 	2026-10-16_14:43 	agent		输出目标列表改为整体替换，读取不需要加锁
+ 	2026-10-16_15:47 	agent		缓冲参数无效时不缓冲，避免缓冲区无限增长
+*******************************************************************************/
+func AddSink(name string, w io.Writer, mode BUFFER_MODE, size int, interval time.Duration) {
+
+	RemoveSink(name)
+
+	//缓冲参数无效时永远不会写入，改为不缓冲
+	if mode == BUFFER_SIZE && size <= 0 {
+		diag("sink %s: buffer size %d is not positive, buffering disabled", name, size)
+		mode = BUFFER_NONE
+	}
+	if mode == BUFFER_INTERVAL && interval <= 0 {
+		diag("sink %s: buffer interval %v is not positive, buffering disabled", name, interval)
+		mode = BUFFER_NONE
+	}
+
+	sink := &LOG_SINK{name: name, writer: w, mode: mode, size: size, interval: interval}
+	if mode == BUFFER_INTERVAL {
+		sink.stop = make(chan struct{})
+		go sink.monitor()
+	}
+
+	sinkLock.Lock()
+	defer sinkLock.Unlock()
+	sinks := sinkList()
+	logSinks.Store(append(sinks[:len(sinks):len(sinks)], sink))
+}
+
+/******************************************************************************
+ @brief
+ 	移除扩展输出目标，移除前会写入缓冲区中的日志
+ @author
+ 	agent
+ @param
+	name				输出目标名称
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:11 	agent		创建
+ 	2026-10-16_14:43 	agent		输出目标列表改为整体替换，读取不需要加锁
+ 	2026-10-16_15:48 	agent		停止写入协程
+ 	2026-10-16_16:40 	agent		标记为已经移除，之后的写入直接丢弃
+*******************************************************************************/
+func RemoveSink(name string) {
+	sinkLock.Lock()
+	var sink *LOG_SINK
+	sinks := sinkList()
+	for i, s := range sinks {
+		if s.name == name {
+			sink = s
+			logSinks.Store(append(sinks[:i:i], sinks[i+1:]...))
+			break
+		}
+	}
+	sinkLock.Unlock()
+
+	if sink == nil {
+		return
+	}
+
+	if sink.stop != nil {
+		close(sink.stop)
+	}
+
+	//仍然持有旧列表的写入在标记之后直接丢弃，不会再创建写入协程
+	sink.Lock()
+	sink.writeBuffer()
+	sink.removed = true
+
+	//正在进行的超时写入完成后写入协程退出
+	if sink.writes != nil {
+		close(sink.writes)
+		sink.writes = nil
+	}
+	sink.Unlock()
+}
+
+/******************************************************************************
+ @brief
+ 	将异步写入队列和所有输出目标缓冲区中的日志写入，并保存日志去重的状态文件
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:11 	agent		创建
+ 	2026-10-16_14:11 	agent		等待异步写入队列
+ 	2026-10-16_14:43 	agent		输出目标列表改为整体替换，读取不需要加锁
+ 	2026-10-16_14:45 	agent		等待后台任务完成
+ 	2026-10-16_16:45 	agent		保存日志去重的状态文件
+*******************************************************************************/
+func Flush() {
+	asyncFlush()
+	waitBackground()
+	dedupFlush()
+
+	for _, sink := range sinkList() {
+		sink.flush()
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	设置输出目标的写入超时时间，输出目标（NFS文件、网络连接等）阻塞超过超时时间时
+ 	不再等待，记录超时次数后继续，避免一个慢的输出目标拖慢整个程序
+ 		例：
+ 			logger.SetSinkTimeout("collector", 200*time.Millisecond)
+ @author
+ 	agent
+ @param
+	name				输出目标名称
+	timeout				写入超时时间，小于等于0表示不限制
+ @return
+ 	bool				输出目标不存在时返回false
+ @history
+ 	2026-10-16_14:41 	agent		创建
+*******************************************************************************/
+func SetSinkTimeout(name string, timeout time.Duration) bool {
+
+	sink := findSink(name)
+	if sink == nil {
+		return false
+	}
+
+	sink.Lock()
+	defer sink.Unlock()
+
+	sink.timeout = timeout
+	return true
+}
+
+/******************************************************************************
+ @brief
+ 	获取所有输出目标的健康状态，也可以通过StartPPROF启动的HTTP服务查看：
+ 		GET /debug/logger/sinks
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	[]SINK_STATUS		返回输出目标健康状态列表
+ @history
+ 	2026-10-16_14:13 	agent		创建
+ 	2026-10-16_14:41 	agent		增加写入超时次数
+ 	2026-10-16_14:43 	agent		输出目标列表改为整体替换，读取不需要加锁
+*******************************************************************************/
+func SinkStatus() []SINK_STATUS {
+	sinks := sinkList()
+	status := make([]SINK_STATUS, 0, len(sinks))
+	for _, sink := range sinks {
+		sink.Lock()
+		st := SINK_STATUS{
+			Name:        sink.name,
+			Connected:   sink.retries == 0,
+			LastErrorAt: sink.lastErrAt,
+			RetryCount:  sink.retries,
+			QueueDepth:  sink.buffer.Len(),
+			Spilled:     sink.spilled,
+			Timeouts:    sink.timeouts,
+		}
+		if sink.lastErr != nil {
+			st.LastError = sink.lastErr.Error()
+		}
+		sink.Unlock()
+
+		status = append(status, st)
+	}
+
+	return status
+}
+
+/******************************************************************************
+ @brief
+ 	获取当前的输出目标列表，返回的列表不能修改
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	[]*LOG_SINK			返回输出目标列表
+ @history
+ 	2026-10-16_14:43 	agent		创建
+*******************************************************************************/
+func sinkList() []*LOG_SINK {
+	sinks, _ := logSinks.Load().([]*LOG_SINK)
+	return sinks
+}
+
+/******************************************************************************
+ @brief
+ 	写入一行日志到所有输出目标
+ @author
+ 	agent
+ @param
+	l					日志行
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:11 	agent		创建
+ 	2026-10-16_14:40 	agent		按照输出目标的时间精度生成日志行
+ 	2026-10-16_14:43 	agent		输出目标列表改为整体替换，读取不需要加锁
+*******************************************************************************/
+func writeSinks(l *logLine) {
+	for _, sink := range sinkList() {
+		sink.write(l)
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	写入一行日志，根据缓冲方式决定直接写入还是进入缓冲区
+ @author
+ 	agent
+ @param
+	l					日志行
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:11 	agent		创建
+ 	2026-10-16_14:40 	agent		按照时间精度生成日志行
+ 	2026-10-16_14:44 	agent		支持统计锁等待时间
+ 	2026-10-16_14:50 	agent		支持易读字段
+ 	2026-10-16_14:51 	agent		支持设置时区
+ 	2026-10-16_15:06 	agent		按路由标签过滤
+ 	2026-10-16_16:40 	agent		已经移除时不写入
+*******************************************************************************/
+func (s *LOG_SINK) write(l *logLine) {
+	if profiling() {
+		start := time.Now()
+		s.Lock()
+		profileLock.record(time.Since(start))
+	} else {
+		s.Lock()
+	}
+	defer s.Unlock()
+
+	if s.removed || !labelsMatch(s.labels, l.labels) {
+		return
+	}
+
+	b := l.bytes(lineFormat{precision: s.precision, humanize: s.humanize, location: s.location})
+
+	switch s.mode {
+	case BUFFER_SIZE, BUFFER_INTERVAL:
+		s.buffer.Write(b)
+		if s.size > 0 && s.buffer.Len() >= s.size {
+			s.writeBuffer()
+		}
+	default:
+		s.send(b)
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	写入缓冲区中的日志
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+func (s *LOG_SINK) flush() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.writeBuffer()
+}
+
+/******************************************************************************
+ @brief
+ 	写入缓冲区中的日志，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+func (s *LOG_SINK) writeBuffer() {
+	if s.buffer.Len() == 0 {
+		return
+	}
+
+	s.send(s.buffer.Bytes())
+	s.buffer.Reset()
+}
+
+/******************************************************************************
+ @brief
+ 	发送日志到输出目标，如果设置了磁盘缓存，写入失败的日志会缓存到磁盘，
+ 	并在输出目标恢复后优先重发，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	b					日志内容
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:11 	agent		创建
+ 	2026-10-16_14:41 	agent		支持写入超时
+ 	2026-10-16_14:46 	agent		发布输出目标恢复事件
+ 	2026-10-16_15:48 	agent		只缓存没有写入的部分
+*******************************************************************************/
+func (s *LOG_SINK) send(b []byte) {
+
+	//先取回上次超时写入的结果，重发时不会重复发送已经写入的缓存
+	s.collect()
+
+	//先重发磁盘缓存，保证日志顺序
+	if s.spilled > 0 {
+		if err := s.replay(); err != nil {
+			s.failure(err)
+			s.spill(b)
+			return
+		}
+	}
+
+	if n, err := s.writeDeadline(b); err != nil {
+		s.failure(err)
+
+		//超时的日志仍在写入中，不能再缓存，避免重复
+		if err != errSinkTimeout {
+			s.spill(b[n:])
+		}
+		return
+	}
+
+	if s.retries > 0 {
+		lifecycle(LIFECYCLE_EVENT{Kind: LIFECYCLE_SINK_RECONNECTED, Sink: s.name})
+	}
+	s.retries = 0
+}
+
+/******************************************************************************
+ @brief
+ 	压缩后写入输出目标，设置了超时时间时由常驻的写入协程写入，超时后不再等待直接返回，
+ 	超时的写入完成之前后续的写入会被直接跳过，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	b					日志内容
+ @return
+ 	int					返回已经写入的日志字节数，压缩时只有全部写入才计算
+ 	error				写入失败、超时或被跳过时返回错误信息
+ @history
+ 	2026-10-16_14:41 	agent		创建
+ 	2026-10-16_15:48 	agent		使用常驻的写入协程，返回已经写入的字节数
+ 	2026-10-16_16:40 	agent		保留超时的新日志，已经移除时拒绝写入
+*******************************************************************************/
+func (s *LOG_SINK) writeDeadline(b []byte) (int, error) {
+	if s.removed {
+		return 0, errSinkRemoved
+	}
+
+	if !s.collect() {
+		s.timeouts += 1
+		return 0, errSinkBusy
+	}
+
+	data := s.compress(b)
+	if s.timeout <= 0 {
+		n, err := s.writer.Write(data)
+		return s.written(len(b), len(data), n), err
+	}
+
+	if s.writes == nil {
+		s.writes = make(chan []byte)
+		s.results = make(chan sinkResult, 1)
+		go sinkWriteLoop(s.writer, s.writes, s.results)
+	}
+
+	//超时后写入仍在进行，需要复制一份数据
+	if s.compressor == nil {
+		data = append([]byte(nil), data...)
+	}
+	s.writes <- data
+
+	timer := time.NewTimer(s.timeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-s.results:
+		return s.written(len(b), len(data), r.n), r.err
+	case <-timer.C:
+		s.timeouts += 1
+		s.pending = true
+		s.pendingLen = [2]int{len(b), len(data)}
+		if len(s.inflight) == 0 {
+			s.pendingBuf = append(s.pendingBuf[:0], b...)
+		}
+		return 0, errSinkTimeout
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	取回上次超时写入的结果，重发磁盘缓存超时的，按实际写入的字节数推进分段，
+ 	新日志写入失败时没有写入的部分缓存到磁盘，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	bool				没有仍在进行的写入时返回true
+ @history
+ 	2026-10-16_15:48 	agent		创建
+ 	2026-10-16_16:40 	agent		新日志写入失败时缓存到磁盘
+*******************************************************************************/
+func (s *LOG_SINK) collect() bool {
+	if !s.pending {
+		return true
+	}
+
+	select {
+	case r := <-s.results:
+		s.pending = false
+		n := s.written(s.pendingLen[0], s.pendingLen[1], r.n)
+		if len(s.inflight) > 0 {
+			s.spillAdvance(s.inflight, n)
+			s.inflight = ""
+		} else if r.err != nil {
+			s.spill(s.pendingBuf[n:])
+		}
+		s.pendingBuf = s.pendingBuf[:0]
+		if r.err != nil {
+			s.failure(r.err)
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	将写入输出目标的字节数换算为日志字节数，压缩后的数据只写入一部分时无法换算，按没有写入计算
+ @author
+ 	agent
+ @param
+	raw					压缩前的大小
+	size				压缩后的大小
+	n					写入输出目标的字节数
+ @return
+ 	int					返回已经写入的日志字节数
+ @history
+ 	2026-10-16_15:48 	agent		创建
+*******************************************************************************/
+func (s *LOG_SINK) written(raw, size, n int) int {
+	switch {
+	case n >= size:
+		return raw
+	case s.compressor != nil || n < 0:
+		return 0
+	}
+
+	return n
+}
+
+/******************************************************************************
+ @brief
+ 	写入协程，按顺序写入日志并返回结果，队列关闭后退出
+ @author
+ 	agent
+ @param
+	w					输出目标实例
+	writes				要写入的日志
+	results				写入结果
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:48 	agent		创建
+*******************************************************************************/
+func sinkWriteLoop(w io.Writer, writes chan []byte, results chan sinkResult) {
+	for data := range writes {
+		n, err := w.Write(data)
+		results <- sinkResult{n: n, err: err}
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	记录一次写入失败，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	err					失败原因
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:13 	agent		创建
+ 	2026-10-16_14:47 	agent		记录到错误记录
+*******************************************************************************/
+func (s *LOG_SINK) failure(err error) {
+	//只记录连续失败中的第一次，避免输出目标不可用时刷屏
+	if s.retries == 0 {
+		diag("sink %s: %v", s.name, err)
+	}
+
+	s.lastErr = err
+	s.lastErrAt = time.Now()
+	s.retries += 1
+}
+
+/******************************************************************************
+ @brief
+ 	定时写入监控函数，按照时间间隔写入缓冲区中的日志
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:11 	agent		创建
+*******************************************************************************/
+func (s *LOG_SINK) monitor() {
+	timer := time.NewTicker(s.interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			s.flush()
+		case <-s.stop:
+			return
+		}
+	}
+}
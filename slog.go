@@ -0,0 +1,139 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	SLOG_LEVEL_FATAL = slog.LevelError + 4 //FATAL日志对应的slog级别，slog没有FATAL级别
+)
+
+/******************************************************************************
+ @brief
+ 	slog后端，包装后存入atomic.Value
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:05 	agent		创建
+*******************************************************************************/
+type slogBackend struct {
+	handler slog.Handler //slog处理器
+}
+
+var (
+	logSlogBackend atomic.Value //slog后端*slogBackend
+)
+
+/******************************************************************************
+ @brief
+ 	将本包的日志接口转交给已有的slog处理流程，设置后日志不再写入本包的日志文件、
+ 	扩展输出目标和终端控制台，日志级别、过滤规则等仍然生效，FATAL日志仍然会退出进程。
+ 	公共字段作为slog属性输出，分类日志带有category属性，可以逐步从本包迁移到slog
+ 		例：
+ 			logger.UseSlogBackend(slog.NewJSONHandler(os.Stdout, nil))
+ 			logger.Infof("player %d login", 10086)
+
+ 		输出：{"time":"2026-10-17T14:00:00.000+08:00","level":"INFO","msg":"player 10086 login"}
+ @author
+ 	agent
+ @param
+	h					slog处理器，nil表示恢复使用本包的输出
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:05 	agent		创建
+*******************************************************************************/
+func UseSlogBackend(h slog.Handler) {
+	logSlogBackend.Store(&slogBackend{handler: h})
+}
+
+/******************************************************************************
+ @brief
+ 	获取slog处理器
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	slog.Handler		返回slog处理器，没有设置时返回nil
+ @history
+ 	2026-10-16_15:05 	agent		创建
+*******************************************************************************/
+func slogHandler() slog.Handler {
+	if b, _ := logSlogBackend.Load().(*slogBackend); b != nil {
+		return b.handler
+	}
+
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	将日志交给slog处理器，只能由outputEntry调用
+ @author
+ 	agent
+ @param
+	h					slog处理器
+	f					日志文件，分类日志时输出category属性
+	ll					日志等级
+	arg					要输出的内容
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:05 	agent		创建
+*******************************************************************************/
+func slogOutput(h slog.Handler, f *LOG_FILE, ll LEVEL, arg string) {
+	ctx := context.Background()
+	level := slogLevel(ll)
+	if !h.Enabled(ctx, level) {
+		return
+	}
+
+	//跳过runtime.Callers、slogOutput、outputEntry、outputFile、output和日志接口
+	var pcs [1]uintptr
+	runtime.Callers(6, pcs[:])
+
+	r := slog.NewRecord(time.Now(), level, humanText(strings.TrimRight(arg, "\n"), false), pcs[0])
+	if f != nil && f != logFile {
+		r.AddAttrs(slog.String("category", f.log_filename))
+	}
+	for _, field := range fieldsList() {
+		r.AddAttrs(slog.String(field[0], field[1]))
+	}
+
+	h.Handle(ctx, r)
+}
+
+/******************************************************************************
+ @brief
+ 	转换为slog级别
+ @author
+ 	agent
+ @param
+	ll					日志等级
+ @return
+ 	slog.Level			返回slog级别
+ @history
+ 	2026-10-16_15:05 	agent		创建
+*******************************************************************************/
+func slogLevel(ll LEVEL) slog.Level {
+	switch ll {
+	case ALL:
+		return slog.LevelDebug - 4
+	case DEBUG:
+		return slog.LevelDebug
+	case INFO:
+		return slog.LevelInfo
+	case WARN:
+		return slog.LevelWarn
+	case ERROR:
+		return slog.LevelError
+	}
+
+	return SLOG_LEVEL_FATAL
+}
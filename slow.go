@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	slowCategory = "slow" //慢操作日志写入的分类
+)
+
+/******************************************************************************
+ @brief
+ 	慢操作统计，包含所有调用的耗时分位数，不只是超过阈值的调用
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:57 	agent		创建
+*******************************************************************************/
+type SLOW_STATS struct {
+	Count int64         //调用次数
+	Slow  int64         //超过阈值的次数
+	P50   time.Duration //耗时50分位
+	P90   time.Duration //耗时90分位
+	P99   time.Duration //耗时99分位
+	Max   time.Duration //最大耗时
+}
+
+/******************************************************************************
+ @brief
+ 	单个操作名称的耗时统计
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:57 	agent		创建
+*******************************************************************************/
+type slowStat struct {
+	hist latencyHist //耗时直方图
+	slow int64       //超过阈值的次数
+}
+
+var (
+	slowLock  sync.Mutex               //慢操作统计线程锁
+	slowStats = map[string]*slowStat{} //每个操作名称的耗时统计
+)
+
+/******************************************************************************
+ @brief
+ 	记录一次操作耗时，超过阈值时写入slow分类日志，所有调用都会计入耗时分位数统计，
+ 	通过SlowStats获取，也可以通过StartPPROF启动的HTTP服务查看
+ 		例：
+ 			start := time.Now()
+ 			rows, err := db.Query(sql)
+ 			logger.Slow("db.query", time.Since(start), 100*time.Millisecond, "table", "player")
+
+ 		输出到slow分类日志：WARN slow db.query dur_ms=532 threshold_ms=100 table=player
+ @author
+ 	agent
+ @param
+	name				操作名称
+	dur					耗时
+	threshold			阈值
+	fields				字段名称和值交替排列
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:57 	agent		创建
+*******************************************************************************/
+func Slow(name string, dur, threshold time.Duration, fields ...string) {
+	defer catchError()
+
+	st := slowStatOf(name)
+	st.hist.record(dur)
+	if dur <= threshold {
+		return
+	}
+	atomic.AddInt64(&st.slow, 1)
+
+	if currentLevel() > WARN {
+		return
+	}
+
+	text := []string{"slow", configField("", name), Duration("dur", dur), configField("threshold_ms", strconv.FormatInt(threshold.Milliseconds(), 10))}
+	for i := 0; i+1 < len(fields); i += 2 {
+		text = append(text, configField(fields[i], fields[i+1]))
+	}
+
+	Category(slowCategory).output(WARN, strings.Join(text, " ")+"\n")
+}
+
+/******************************************************************************
+ @brief
+ 	获取每个操作名称的耗时统计，分位数按直方图分桶估算，误差在2倍以内
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	map[string]SLOW_STATS	返回耗时统计
+ @history
+ 	2026-10-16_14:57 	agent		创建
+*******************************************************************************/
+func SlowStats() map[string]SLOW_STATS {
+	slowLock.Lock()
+	defer slowLock.Unlock()
+
+	stats := make(map[string]SLOW_STATS, len(slowStats))
+	for name, st := range slowStats {
+		stats[name] = SLOW_STATS{
+			Count: atomic.LoadInt64(&st.hist.count),
+			Slow:  atomic.LoadInt64(&st.slow),
+			P50:   st.hist.percentile(0.50),
+			P90:   st.hist.percentile(0.90),
+			P99:   st.hist.percentile(0.99),
+			Max:   time.Duration(atomic.LoadInt64(&st.hist.max)),
+		}
+	}
+
+	return stats
+}
+
+/******************************************************************************
+ @brief
+ 	获取操作名称的耗时统计，不存在时创建
+ @author
+ 	agent
+ @param
+	name				操作名称
+ @return
+ 	*slowStat			返回耗时统计
+ @history
+ 	2026-10-16_14:57 	agent		创建
+*******************************************************************************/
+func slowStatOf(name string) *slowStat {
+	slowLock.Lock()
+	defer slowLock.Unlock()
+
+	st, ok := slowStats[name]
+	if !ok {
+		st = &slowStat{}
+		slowStats[name] = st
+	}
+
+	return st
+}
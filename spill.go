@@ -0,0 +1,227 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	spillSegmentSize = 4 * 1024 * 1024 //磁盘缓存单个分段最大大小
+)
+
+/******************************************************************************
+ @brief
+ 	为输出目标设置磁盘缓存，当输出目标写入失败（例如远程收集服务不可用）时，
+ 	日志会缓存到磁盘目录中，输出目标恢复后按顺序重发。
+ 	磁盘缓存按分段文件存放，总大小超过上限时丢弃最旧的分段，
+ 	目录中上次进程遗留的缓存也会在恢复后重发
+ 		例：
+ 			logger.AddSink("collector", conn, logger.BUFFER_INTERVAL, 64*1024, 5*time.Second)
+ 			logger.SetSinkSpill("collector", "./log/spill/collector", 256*1024*1024)
+ @author
+ 	agent
+ @param
+	name				输出目标名称
+	dir					磁盘缓存目录，每个输出目标需要使用独立的目录
+	maxSize				磁盘缓存最大大小
+ @return
+ 	bool				输出目标不存在时返回false
+ @history
+ 	2026-10-16_14:11 	agent		创建
+ 	2026-10-16_16:25 	agent		文件操作通过日志存储接口完成
+*******************************************************************************/
+func SetSinkSpill(name, dir string, maxSize int64) bool {
+
+	sink := findSink(name)
+	if sink == nil {
+		return false
+	}
+
+	logStorage.MkdirAll(dir, os.ModePerm)
+
+	sink.Lock()
+	defer sink.Unlock()
+
+	sink.spillDir = dir
+	sink.spillMax = maxSize
+	sink.spilled = 0
+	sink.spillSeq = 0
+
+	//统计遗留的磁盘缓存
+	for _, fn := range sink.spillSegments() {
+		if finfo, err := logStorage.Stat(fn); err == nil {
+			sink.spilled += finfo.Size()
+		}
+		fmt.Sscanf(filepath.Base(fn), "%d.spill", &sink.spillSeq)
+	}
+
+	return true
+}
+
+/******************************************************************************
+ @brief
+ 	根据名称查找输出目标
+ @author
+ 	agent
+ @param
+	name				输出目标名称
+ @return
+ 	*LOG_SINK			返回输出目标，不存在时返回nil
+ @history
+ 	2026-10-16_14:11 	agent		创建
+ 	2026-10-16_14:43 	agent		输出目标列表改为整体替换，读取不需要加锁
+*******************************************************************************/
+func findSink(name string) *LOG_SINK {
+	for _, sink := range sinkList() {
+		if sink.name == name {
+			return sink
+		}
+	}
+
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	获取磁盘缓存的分段文件列表，按从旧到新排序
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	[]string			返回分段文件路径列表
+ @history
+ 	2026-10-16_14:11 	agent		创建
+ 	2026-10-16_16:25 	agent		文件操作通过日志存储接口完成
+*******************************************************************************/
+func (s *LOG_SINK) spillSegments() []string {
+	files, _ := logStorage.Glob(filepath.Join(s.spillDir, "*.spill"))
+	sort.Strings(files)
+	return files
+}
+
+/******************************************************************************
+ @brief
+ 	将写入失败的日志缓存到磁盘，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	b					日志内容
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:11 	agent		创建
+ 	2026-10-16_16:25 	agent		文件操作通过日志存储接口完成
+*******************************************************************************/
+func (s *LOG_SINK) spill(b []byte) {
+
+	if s.spillDir == "" {
+		return
+	}
+
+	//当前分段已满时创建新的分段
+	fn := filepath.Join(s.spillDir, fmt.Sprintf("%010d.spill", s.spillSeq))
+	if finfo, err := logStorage.Stat(fn); err == nil && finfo.Size()+int64(len(b)) > spillSegmentSize {
+		s.spillSeq += 1
+		fn = filepath.Join(s.spillDir, fmt.Sprintf("%010d.spill", s.spillSeq))
+	}
+
+	f, err := logStorage.OpenFile(fn, os.O_RDWR|os.O_APPEND|os.O_CREATE, os.ModePerm)
+	if err != nil {
+		return
+	}
+	n, _ := f.Write(b)
+	f.Close()
+	s.spilled += int64(n)
+
+	//超过上限时丢弃最旧的分段
+	segments := s.spillSegments()
+	for len(segments) > 1 && s.spilled > s.spillMax {
+		if finfo, err := logStorage.Stat(segments[0]); err == nil {
+			s.spilled -= finfo.Size()
+		}
+		logStorage.Remove(segments[0])
+		segments = segments[1:]
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	按顺序重发磁盘缓存中的日志，重发成功的分段会被删除，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	error				输出目标仍然无法写入时返回错误信息
+ @history
+ 	2026-10-16_14:11 	agent		创建
+ 	2026-10-16_14:41 	agent		支持写入超时
+ 	2026-10-16_15:48 	agent		只删除已经写入的部分，超时的写入完成后再推进
+ 	2026-10-16_16:25 	agent		文件操作通过日志存储接口完成
+*******************************************************************************/
+func (s *LOG_SINK) replay() error {
+
+	for _, fn := range s.spillSegments() {
+		data, err := storageReadFile(fn)
+		if err != nil {
+			continue
+		}
+
+		s.inflight = fn
+		n, err := s.writeDeadline(data)
+		if err == errSinkTimeout {
+			return err
+		}
+
+		s.inflight = ""
+		s.spillAdvance(fn, n)
+		if err != nil {
+			return err
+		}
+	}
+
+	s.spilled = 0
+	s.spillSeq = 0
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	磁盘缓存分段的前n个字节已经写入，全部写入时删除分段，否则只保留剩余的部分，
+ 	调用者需要持有锁
+ @author
+ 	agent
+ @param
+	fn					分段文件路径
+	n					已经写入的字节数
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:48 	agent		创建
+ 	2026-10-16_16:25 	agent		文件操作通过日志存储接口完成
+*******************************************************************************/
+func (s *LOG_SINK) spillAdvance(fn string, n int) {
+	if n <= 0 {
+		return
+	}
+
+	data, err := storageReadFile(fn)
+	if err != nil {
+		return
+	}
+
+	if n >= len(data) {
+		logStorage.Remove(fn)
+		s.spilled -= int64(len(data))
+		return
+	}
+
+	if err := storageWriteFile(fn, data[n:], os.ModePerm); err != nil {
+		diag("sink %s spill %s: %v", s.name, fn, err)
+		return
+	}
+	s.spilled -= int64(n)
+}
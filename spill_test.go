@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// 测试用的输出目标，可以设置为正常写入、只写入一半或全部失败
+type flakyWriter struct {
+	sync.Mutex
+	mode string //ok、partial、fail
+	data bytes.Buffer
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	w.Lock()
+	defer w.Unlock()
+
+	switch w.mode {
+	case "partial":
+		n := len(p) / 2
+		w.data.Write(p[:n])
+		return n, io.ErrShortWrite
+	case "fail":
+		return 0, errors.New("collector down")
+	}
+
+	return w.data.Write(p)
+}
+
+func (w *flakyWriter) set(mode string) {
+	w.Lock()
+	defer w.Unlock()
+	w.mode = mode
+}
+
+func (w *flakyWriter) String() string {
+	w.Lock()
+	defer w.Unlock()
+	return w.data.String()
+}
+
+// 获取输出目标磁盘缓存中等待重发的大小
+func spilledOf(t *testing.T, name string) int64 {
+	for _, st := range SinkStatus() {
+		if st.Name == name {
+			return st.Spilled
+		}
+	}
+
+	t.Fatalf("sink %s not found", name)
+	return 0
+}
+
+// 输出目标只写入一半和写入失败时缓存到磁盘，恢复后按顺序重发，每条日志完整且只出现一次
+func TestSinkSpillReplay(t *testing.T) {
+	dir := setupStress(t)
+
+	//正常的输出目标收到的内容作为对照
+	ref := &flakyWriter{mode: "ok"}
+	AddSink("ref", ref, BUFFER_NONE, 0, 0)
+	defer RemoveSink("ref")
+
+	w := &flakyWriter{mode: "partial"}
+	AddSink("flaky", w, BUFFER_NONE, 0, 0)
+	defer RemoveSink("flaky")
+	if !SetSinkSpill("flaky", filepath.Join(dir, "spill"), 1024*1024) {
+		t.Fatal("SetSinkSpill: sink not found")
+	}
+
+	Info("entry one")
+	if spilledOf(t, "flaky") == 0 {
+		t.Fatal("unwritten half of a partial write not spilled")
+	}
+
+	w.set("fail")
+	Info("entry two")
+	Info("entry three")
+
+	w.set("ok")
+	Info("entry four")
+
+	if n := spilledOf(t, "flaky"); n != 0 {
+		t.Fatalf("%d bytes left in spill after recovery", n)
+	}
+	if files, _ := logStorage.Glob(filepath.Join(dir, "spill", "*.spill")); len(files) != 0 {
+		t.Fatalf("spill segments left after recovery: %v", files)
+	}
+
+	want := ref.String()
+	if strings.Count(want, "\n") != 4 {
+		t.Fatalf("reference sink received %q", want)
+	}
+	if got := w.String(); got != want {
+		t.Fatalf("sink received\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,212 @@
+package logger
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var (
+	sqliteTable = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,63}$`)
+)
+
+/******************************************************************************
+ @brief
+ 	SQLite输出目标类结构，每次写入的日志行在一个事务中插入，超过行数上限时删除最早的记录，
+ 	现场排查时可以直接用SQL查询。数据库驱动由调用者导入，本模块只使用database/sql
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:18 	agent		创建
+*******************************************************************************/
+type SQLITE_WRITER struct {
+	db      *sql.DB //数据库
+	table   string  //表名
+	maxRows int64   //最多保留的行数，0表示不限制
+}
+
+/******************************************************************************
+ @brief
+ 	创建SQLite输出目标，表不存在时创建
+ 		例：
+ 			import _ "modernc.org/sqlite"
+
+ 			db, _ := sql.Open("sqlite", "./log/logs.db")
+ 			w, err := logger.NewSQLiteWriter(db, "logs", 1000000)
+ 			if err == nil {
+ 				logger.AddSink("sqlite", w, logger.BUFFER_INTERVAL, 256*1024, time.Second)
+ 			}
+
+ 			entries, _ := w.Query("level = ? AND time > ?", "ERROR", time.Now().Add(-time.Hour).UnixNano())
+
+ 		表结构：id、time（Unix纳秒，没有时间时为0）、level、caller、func、msg、
+ 			fields（JSON数组[[key,value],...]，按输出顺序排列）、line（原文）
+ @author
+ 	agent
+ @param
+	db					已经打开的SQLite数据库
+	table				表名
+	maxRows				最多保留的行数，小于等于0表示不限制
+ @return
+ 	*SQLITE_WRITER		返回输出目标
+ 	error				表名无效或建表失败时返回错误信息
+ @history
+ 	2026-10-16_15:18 	agent		创建
+*******************************************************************************/
+func NewSQLiteWriter(db *sql.DB, table string, maxRows int) (*SQLITE_WRITER, error) {
+	if !sqliteTable.MatchString(table) {
+		return nil, fmt.Errorf("logger: invalid sqlite table name %q", table)
+	}
+
+	if maxRows < 0 {
+		maxRows = 0
+	}
+
+	stmts := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	time INTEGER NOT NULL,
+	level TEXT NOT NULL,
+	caller TEXT NOT NULL,
+	func TEXT NOT NULL,
+	msg TEXT NOT NULL,
+	fields TEXT NOT NULL,
+	line TEXT NOT NULL)`, table),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s_time ON %s (time)`, table, table),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s_level ON %s (level, time)`, table, table),
+	}
+
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return nil, fmt.Errorf("logger: sqlite create table %s: %v", table, err)
+		}
+	}
+
+	return &SQLITE_WRITER{db: db, table: table, maxRows: int64(maxRows)}, nil
+}
+
+/******************************************************************************
+ @brief
+ 	解析日志行并在一个事务中插入，插入后删除超过行数上限的最早记录
+ @author
+ 	agent
+ @param
+	b					要写入的日志行
+ @return
+ 	int					返回写入的字节数
+ 	error				写入失败时返回错误信息
+ @history
+ 	2026-10-16_15:18 	agent		创建
+*******************************************************************************/
+func (w *SQLITE_WRITER) Write(b []byte) (int, error) {
+
+	tx, err := w.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	stmt, err := tx.Prepare(fmt.Sprintf(`INSERT INTO %s (time, level, caller, func, msg, fields, line) VALUES (?, ?, ?, ?, ?, ?, ?)`, w.table))
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	defer stmt.Close()
+
+	entries, raws := parseEntries(b)
+	for i, e := range entries {
+		fields := e.Fields
+		if fields == nil {
+			fields = [][2]string{}
+		}
+		data, _ := json.Marshal(fields)
+
+		ns := int64(0)
+		if !e.Time.IsZero() {
+			ns = e.Time.UnixNano()
+		}
+
+		caller := ""
+		if len(e.File) > 0 {
+			caller = e.File + ":" + strconv.Itoa(e.Line)
+		}
+
+		if _, err := stmt.Exec(ns, e.Level.String(), caller, e.Func, e.Msg, string(data), raws[i]); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	if w.maxRows > 0 {
+		prune := fmt.Sprintf(`DELETE FROM %s WHERE id <= (SELECT MAX(id) FROM %s) - ?`, w.table, w.table)
+		if _, err := tx.Exec(prune, w.maxRows); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+/******************************************************************************
+ @brief
+ 	按条件查询日志，按时间顺序返回
+ @author
+ 	agent
+ @param
+	where				WHERE子句，不带WHERE关键字，为空表示全部，例如level = ? AND msg LIKE ?
+	args				WHERE子句中的参数
+ @return
+ 	[]ENTRY				返回查询结果
+ 	error				查询失败时返回错误信息
+ @history
+ 	2026-10-16_15:18 	agent		创建
+*******************************************************************************/
+func (w *SQLITE_WRITER) Query(where string, args ...interface{}) ([]ENTRY, error) {
+
+	query := fmt.Sprintf(`SELECT time, level, caller, func, msg, fields FROM %s`, w.table)
+	if len(where) > 0 {
+		query += " WHERE " + where
+	}
+	query += " ORDER BY id"
+
+	rows, err := w.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []ENTRY{}
+	for rows.Next() {
+		var ns int64
+		var level, caller, fn, msg, fields string
+		if err := rows.Scan(&ns, &level, &caller, &fn, &msg, &fields); err != nil {
+			return entries, err
+		}
+
+		e := ENTRY{Func: fn, Msg: msg}
+		if ns != 0 {
+			e.Time = time.Unix(0, ns)
+		}
+		e.Level, _ = ParseLevel(level)
+		if m := lineCaller.FindStringSubmatch(caller + ": "); m != nil {
+			e.File = m[1]
+			e.Line, _ = strconv.Atoi(m[2])
+		}
+
+		json.Unmarshal([]byte(fields), &e.Fields)
+		if len(e.Fields) == 0 {
+			e.Fields = nil
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
@@ -0,0 +1,237 @@
+package logger
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+/******************************************************************************
+ @brief
+ 	日志存储接口，日志文件的创建、切分、清理，以及异常文件、minidump和错误记录文件
+ 	都通过此接口完成，默认使用操作系统文件系统，测试时可以替换为MemStorage
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:30 	agent		创建
+ 	2026-10-16_15:42 	agent		异常文件和错误记录文件也通过此接口
+*******************************************************************************/
+type STORAGE interface {
+	OpenFile(name string, flag int, perm os.FileMode) (STORAGE_FILE, error) //打开文件
+	MkdirAll(path string, perm os.FileMode) error                           //创建目录
+	Remove(name string) error                                               //删除文件或空目录
+	Stat(name string) (os.FileInfo, error)                                  //获取文件信息
+	Chmod(name string, mode os.FileMode) error                              //修改文件权限
+	Glob(pattern string) ([]string, error)                                  //匹配文件
+}
+
+/******************************************************************************
+ @brief
+ 	日志存储文件接口，*os.File实现了此接口
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:30 	agent		创建
+*******************************************************************************/
+type STORAGE_FILE interface {
+	io.Reader
+	io.ReaderAt
+	io.Writer
+	io.Closer
+	Stat() (os.FileInfo, error)
+}
+
+/******************************************************************************
+ @brief
+ 	操作系统文件系统存储，默认使用
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:30 	agent		创建
+*******************************************************************************/
+type OS_STORAGE struct{}
+
+var (
+	logStorage STORAGE = OS_STORAGE{} //日志存储
+)
+
+/******************************************************************************
+ @brief
+ 	设置日志存储，需要在Initialize之前调用
+ 		例：
+ 			logger.SetStorage(logger.MemStorage())
+ 			logger.Initialize("/logs", "LoginServer")
+ @author
+ 	agent
+ @param
+	s					日志存储，nil表示恢复为操作系统文件系统
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:30 	agent		创建
+*******************************************************************************/
+func SetStorage(s STORAGE) {
+	if s == nil {
+		s = OS_STORAGE{}
+	}
+
+	logStorage = s
+}
+
+/******************************************************************************
+ @brief
+ 	打开文件
+ @author
+ 	agent
+ @see
+ 	os.OpenFile
+ @history
+ 	2026-10-16_14:30 	agent		创建
+*******************************************************************************/
+func (OS_STORAGE) OpenFile(name string, flag int, perm os.FileMode) (STORAGE_FILE, error) {
+	f, err := os.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+/******************************************************************************
+ @brief
+ 	创建目录
+ @author
+ 	agent
+ @see
+ 	os.MkdirAll
+ @history
+ 	2026-10-16_14:30 	agent		创建
+*******************************************************************************/
+func (OS_STORAGE) MkdirAll(path string, perm os.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+
+/******************************************************************************
+ @brief
+ 	删除文件或空目录
+ @author
+ 	agent
+ @see
+ 	os.Remove
+ @history
+ 	2026-10-16_14:30 	agent		创建
+*******************************************************************************/
+func (OS_STORAGE) Remove(name string) error {
+	return os.Remove(name)
+}
+
+/******************************************************************************
+ @brief
+ 	获取文件信息
+ @author
+ 	agent
+ @see
+ 	os.Stat
+ @history
+ 	2026-10-16_14:30 	agent		创建
+*******************************************************************************/
+func (OS_STORAGE) Stat(name string) (os.FileInfo, error) {
+	return os.Stat(name)
+}
+
+/******************************************************************************
+ @brief
+ 	修改文件权限
+ @author
+ 	agent
+ @see
+ 	os.Chmod
+ @history
+ 	2026-10-16_14:30 	agent		创建
+*******************************************************************************/
+func (OS_STORAGE) Chmod(name string, mode os.FileMode) error {
+	return os.Chmod(name, mode)
+}
+
+/******************************************************************************
+ @brief
+ 	匹配文件
+ @author
+ 	agent
+ @see
+ 	filepath.Glob
+ @history
+ 	2026-10-16_14:30 	agent		创建
+*******************************************************************************/
+func (OS_STORAGE) Glob(pattern string) ([]string, error) {
+	return filepath.Glob(pattern)
+}
+
+/******************************************************************************
+ @brief
+ 	判断日志存储中的文件是否存在
+ @author
+ 	agent
+ @param
+	name				文件路径
+ @return
+ 	bool				返回true表示存在且不是目录
+ @history
+ 	2026-10-16_14:30 	agent		创建
+*******************************************************************************/
+func storageExist(name string) bool {
+	fi, err := logStorage.Stat(name)
+	return err == nil && !fi.IsDir()
+}
+
+/******************************************************************************
+ @brief
+ 	读取日志存储中的整个文件
+ @author
+ 	agent
+ @param
+	name				文件路径
+ @return
+ 	[]byte				返回文件内容
+ 	error				读取失败时返回错误信息
+ @history
+ 	2026-10-16_15:42 	agent		创建
+*******************************************************************************/
+func storageReadFile(name string) ([]byte, error) {
+	f, err := logStorage.OpenFile(name, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ioutil.ReadAll(f)
+}
+
+/******************************************************************************
+ @brief
+ 	写入日志存储中的文件，文件已经存在时覆盖
+ @author
+ 	agent
+ @param
+	name				文件路径
+	data				文件内容
+	perm				新建文件的权限
+ @return
+ 	error				写入失败时返回错误信息
+ @history
+ 	2026-10-16_15:42 	agent		创建
+*******************************************************************************/
+func storageWriteFile(name string, data []byte, perm os.FileMode) error {
+	f, err := logStorage.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
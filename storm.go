@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	stormLimit   int64 //每秒日志条数上限，超过时熔断，0表示关闭
+	stormSample  int64 //熔断期间每stormSample条保留1条
+	stormSecond  int64 //当前统计的秒
+	stormCount   int64 //当前秒的日志条数
+	stormTripped int32 //是否处于熔断状态
+	stormSeen    int64 //熔断期间参与采样的条数
+	stormDropped int64 //熔断期间丢弃的条数
+)
+
+/******************************************************************************
+ @brief
+ 	设置日志风暴熔断，每秒日志条数超过limit时降级为采样，只保留每sample条中的1条，
+ 	并输出一条WARN日志说明；日志速率回落到limit以下后恢复，并输出丢弃的条数。
+ 	ERROR和FATAL日志不参与采样
+ 		例：
+ 			logger.SetStormBreaker(5000, 100)
+
+ 		输出：WARN logger: log storm detected, more than 5000 entries per second, keeping 1 of 100
+ @author
+ 	agent
+ @param
+	limit				每秒日志条数上限，小于等于0表示关闭
+	sample				熔断期间每sample条保留1条，小于等于1时丢弃全部
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:59 	agent		创建
+*******************************************************************************/
+func SetStormBreaker(limit, sample int) {
+	if limit < 0 {
+		limit = 0
+	}
+
+	atomic.StoreInt64(&stormSample, int64(sample))
+	atomic.StoreInt64(&stormLimit, int64(limit))
+	if limit == 0 {
+		atomic.StoreInt32(&stormTripped, 0)
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	统计日志速率并判断是否需要丢弃，熔断状态变化时返回需要输出的提示
+ @author
+ 	agent
+ @param
+	ll					日志等级
+ @return
+ 	bool				需要丢弃时返回true
+ 	string				熔断或恢复时返回提示内容，否则返回空字符串
+ @history
+ 	2026-10-16_14:59 	agent		创建
+*******************************************************************************/
+func stormCheck(ll LEVEL) (bool, string) {
+	limit := atomic.LoadInt64(&stormLimit)
+	if limit <= 0 {
+		return false, ""
+	}
+
+	notice := ""
+
+	//进入新的一秒，上一秒没有超过上限或者中间有空闲的秒时恢复
+	now := time.Now().Unix()
+	second := atomic.LoadInt64(&stormSecond)
+	if second != now && atomic.CompareAndSwapInt64(&stormSecond, second, now) {
+		last := atomic.SwapInt64(&stormCount, 0)
+		if (last <= limit || now-second > 1) && atomic.CompareAndSwapInt32(&stormTripped, 1, 0) {
+			notice = fmt.Sprintf("logger: log storm ended, %d entries dropped\n", atomic.SwapInt64(&stormDropped, 0))
+		}
+	}
+
+	sample := atomic.LoadInt64(&stormSample)
+	if atomic.AddInt64(&stormCount, 1) > limit && atomic.CompareAndSwapInt32(&stormTripped, 0, 1) {
+		atomic.StoreInt64(&stormSeen, 0)
+		notice = fmt.Sprintf("logger: log storm detected, more than %d entries per second, keeping 1 of %d\n", limit, sample)
+	}
+
+	if atomic.LoadInt32(&stormTripped) == 0 || ll >= ERROR {
+		return false, notice
+	}
+
+	if sample > 1 && atomic.AddInt64(&stormSeen, 1)%sample == 1 {
+		return false, notice
+	}
+
+	atomic.AddInt64(&stormDropped, 1)
+	return true, notice
+}
@@ -0,0 +1,189 @@
+package logger
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"testing/quick"
+	"time"
+)
+
+// 初始化测试用的内存日志目录，返回目录路径
+func setupStress(t *testing.T) string {
+	dir := t.TempDir()
+	SetStorage(MemStorage())
+	t.Cleanup(func() { SetStorage(nil) })
+
+	SetConsole(false)
+	SetLevel(ALL)
+	Initialize(dir, "stress")
+	t.Cleanup(shutdown)
+	return dir
+}
+
+// 读取目录下所有日志文件的行
+func readLines(t *testing.T, dir, name string) []string {
+	files, err := logStorage.Glob(filepath.Join(dir, "*", name+".*.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := []string{}
+	for _, fn := range files {
+		file, err := logStorage.OpenFile(fn, os.O_RDONLY, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		file.Close()
+	}
+
+	return lines
+}
+
+// 大量协程并发写入的同时不断切分，每一行都必须完整且只出现一次
+func TestConcurrentRotation(t *testing.T) {
+	dir := setupStress(t)
+	db := Category("stress_db")
+
+	const workers, count = 200, 50
+
+	stop := make(chan struct{})
+	rotated := make(chan int)
+	go func() {
+		n := 0
+		for {
+			select {
+			case <-stop:
+				rotated <- n
+				return
+			default:
+				Rotate()
+				n++
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < count; i++ {
+				Infof("worker=%d seq=%d", w, i)
+				db.Infof("worker=%d seq=%d", w, i)
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(stop)
+	t.Logf("rotations during test: %d", <-rotated)
+
+	for _, name := range []string{"stress", "stress_db"} {
+		seen := map[string]int{}
+		for _, line := range readLines(t, dir, name) {
+			i := strings.Index(line, "INFO worker=")
+			if i < 0 {
+				t.Fatalf("%s: corrupted line %q", name, line)
+			}
+			seen[line[i:]]++
+		}
+
+		for w := 0; w < workers; w++ {
+			for i := 0; i < count; i++ {
+				key := fmt.Sprintf("INFO worker=%d seq=%d", w, i)
+				if seen[key] != 1 {
+					t.Fatalf("%s: %q written %d times", name, key, seen[key])
+				}
+			}
+		}
+	}
+}
+
+// 任意顺序的写入和切分，写入的行数与文件中的行数一致
+func TestRotationProperty(t *testing.T) {
+	check := func(ops []bool) bool {
+		dir := setupStress(t)
+
+		writes := 0
+		for _, write := range ops {
+			if write {
+				Info("x")
+				writes++
+			} else {
+				Rotate()
+			}
+		}
+
+		return len(readLines(t, dir, "stress")) == writes
+	}
+
+	if err := quick.Check(check, &quick.Config{MaxCount: 50}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// 并发调整日志级别的同时写日志，级别读写不能有数据竞争，恢复后按原级别过滤
+func TestConcurrentLevel(t *testing.T) {
+	dir := setupStress(t)
+
+	const workers, count = 50, 200
+
+	stop := make(chan struct{})
+	var adjust sync.WaitGroup
+	adjust.Add(1)
+	go func() {
+		defer adjust.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+
+			if i%2 == 0 {
+				SetLevel(LEVEL(i % int(FATAL+1)))
+			} else {
+				BoostLevel(DEBUG, time.Millisecond)
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < count; i++ {
+				Debugf("worker=%d seq=%d", w, i)
+				Errorf("worker=%d seq=%d", w, i)
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(stop)
+	adjust.Wait()
+
+	BoostLevel(ALL, 0)
+	SetLevel(ERROR)
+	Info("filtered")
+	Error("kept")
+
+	lines := readLines(t, dir, "stress")
+	for _, line := range lines {
+		if strings.Contains(line, "filtered") {
+			t.Fatalf("INFO written at ERROR level: %q", line)
+		}
+	}
+	if len(lines) == 0 || !strings.HasSuffix(lines[len(lines)-1], "ERROR kept") {
+		t.Fatalf("last line is not the ERROR entry: %v", lines[len(lines)-1:])
+	}
+}
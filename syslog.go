@@ -0,0 +1,176 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+/******************************************************************************
+ @brief
+ 	syslog输出目标配置，facility和severity使用syslog的标准名称
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:49 	agent		创建
+*******************************************************************************/
+type SYSLOG_CONFIG struct {
+	Network  string           //网络类型，udp、tcp，为空表示本机syslog
+	Addr     string           //syslog服务地址，Network为空时忽略
+	Tag      string           //程序标识，为空表示进程名
+	Facility string           //facility，例如local0，为空表示user
+	Severity map[LEVEL]string //日志级别对应的severity，例如ERROR对应err，没有配置的级别使用默认映射
+}
+
+/******************************************************************************
+ @brief
+ 	syslog输出目标类结构，每条日志按照级别映射为severity后发送
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:49 	agent		创建
+*******************************************************************************/
+type SYSLOG_WRITER struct {
+	sync.Mutex               //线程锁
+	conn       syslogConn    //syslog连接
+	severity   map[LEVEL]int //日志级别对应的severity
+}
+
+/******************************************************************************
+ @brief
+ 	syslog连接，由各平台实现
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:49 	agent		创建
+*******************************************************************************/
+type syslogConn interface {
+	send(severity int, msg string) error //按severity发送一条消息
+	Close() error                        //关闭连接
+}
+
+var (
+	//facility名称对应的编号
+	syslogFacilities = map[string]int{
+		"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+		"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+		"local0": 16, "local1": 17, "local2": 18, "local3": 19, "local4": 20, "local5": 21, "local6": 22, "local7": 23,
+	}
+
+	//severity名称对应的编号
+	syslogSeverities = map[string]int{
+		"emerg": 0, "alert": 1, "crit": 2, "err": 3, "warning": 4, "notice": 5, "info": 6, "debug": 7,
+	}
+
+	//日志级别默认对应的severity
+	syslogDefaultSeverity = map[LEVEL]string{
+		ALL: "debug", DEBUG: "debug", INFO: "info", WARN: "warning", ERROR: "err", FATAL: "crit",
+	}
+)
+
+/******************************************************************************
+ @brief
+ 	创建syslog输出目标，日志级别默认映射为DEBUG→debug、INFO→info、WARN→warning、
+ 	ERROR→err、FATAL→crit，采集端按facility分流时可以为每个输出目标设置不同的facility
+ 		例：
+ 			w, err := logger.NewSyslogWriter(logger.SYSLOG_CONFIG{
+ 				Network:  "udp",
+ 				Addr:     "10.0.0.5:514",
+ 				Facility: "local3",
+ 				Severity: map[logger.LEVEL]string{logger.WARN: "notice"},
+ 			})
+ 			if err == nil {
+ 				logger.AddSink("syslog", w, logger.BUFFER_NONE, 0, 0)
+ 			}
+ @author
+ 	agent
+ @param
+	cfg					syslog配置
+ @return
+ 	*SYSLOG_WRITER		返回输出目标
+ 	error				facility或severity名称无效、连接失败或当前平台不支持时返回错误信息
+ @history
+ 	2026-10-16_14:49 	agent		创建
+*******************************************************************************/
+func NewSyslogWriter(cfg SYSLOG_CONFIG) (*SYSLOG_WRITER, error) {
+	name := cfg.Facility
+	if len(name) == 0 {
+		name = "user"
+	}
+	facility, ok := syslogFacilities[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("logger: unknown syslog facility %q", cfg.Facility)
+	}
+
+	severity := map[LEVEL]int{}
+	for ll, name := range syslogDefaultSeverity {
+		severity[ll] = syslogSeverities[name]
+	}
+	for ll, name := range cfg.Severity {
+		s, ok := syslogSeverities[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("logger: unknown syslog severity %q for %s", name, ll)
+		}
+		severity[ll] = s
+	}
+
+	conn, err := syslogDial(cfg.Network, cfg.Addr, cfg.Tag, facility)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SYSLOG_WRITER{conn: conn, severity: severity}, nil
+}
+
+/******************************************************************************
+ @brief
+ 	发送日志，缓冲写入的多条日志逐条发送，每条日志根据解析出的级别选择severity，
+ 	多行日志作为一条消息发送，无法解析级别的日志按INFO发送
+ @author
+ 	agent
+ @param
+	b					要发送的日志行
+ @return
+ 	int					返回发送的字节数
+ 	error				发送失败时返回错误信息
+ @history
+ 	2026-10-16_14:49 	agent		创建
+ 	2026-10-16_16:35 	agent		按parseEntries解析的日志逐条发送
+*******************************************************************************/
+func (w *SYSLOG_WRITER) Write(b []byte) (int, error) {
+	w.Lock()
+	defer w.Unlock()
+
+	entries, raws := parseEntries(b)
+	for i, e := range entries {
+		ll := e.Level
+		if ll == ALL {
+			ll = INFO
+		}
+
+		if err := w.conn.send(w.severity[ll], raws[i]); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(b), nil
+}
+
+/******************************************************************************
+ @brief
+ 	关闭syslog连接
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	error				关闭失败时返回错误信息
+ @history
+ 	2026-10-16_14:49 	agent		创建
+*******************************************************************************/
+func (w *SYSLOG_WRITER) Close() error {
+	w.Lock()
+	defer w.Unlock()
+
+	return w.conn.Close()
+}
@@ -0,0 +1,28 @@
+//go:build !linux && !darwin && !freebsd
+// +build !linux,!darwin,!freebsd
+
+package logger
+
+import (
+	"fmt"
+)
+
+/******************************************************************************
+ @brief
+ 	连接syslog服务，当前平台不支持
+ @author
+ 	agent
+ @param
+	network				网络类型
+	addr				syslog服务地址
+	tag					程序标识
+	facility			facility编号
+ @return
+ 	syslogConn			返回nil
+ 	error				返回错误信息
+ @history
+ 	2026-10-16_14:49 	agent		创建
+*******************************************************************************/
+func syslogDial(network, addr, tag string, facility int) (syslogConn, error) {
+	return nil, fmt.Errorf("logger: syslog is not supported on this platform")
+}
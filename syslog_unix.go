@@ -0,0 +1,79 @@
+//go:build linux || darwin || freebsd
+// +build linux darwin freebsd
+
+package logger
+
+import (
+	"log/syslog"
+)
+
+/******************************************************************************
+ @brief
+ 	标准库syslog连接
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:49 	agent		创建
+*******************************************************************************/
+type syslogWriter struct {
+	*syslog.Writer //标准库syslog连接
+}
+
+/******************************************************************************
+ @brief
+ 	连接syslog服务
+ @author
+ 	agent
+ @param
+	network				网络类型，为空表示本机syslog
+	addr				syslog服务地址
+	tag					程序标识
+	facility			facility编号
+ @return
+ 	syslogConn			返回syslog连接
+ 	error				连接失败时返回错误信息
+ @history
+ 	2026-10-16_14:49 	agent		创建
+*******************************************************************************/
+func syslogDial(network, addr, tag string, facility int) (syslogConn, error) {
+	w, err := syslog.Dial(network, addr, syslog.Priority(facility<<3)|syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return syslogWriter{w}, nil
+}
+
+/******************************************************************************
+ @brief
+ 	按severity发送一条消息，facility使用连接时设置的值
+ @author
+ 	agent
+ @param
+	severity			severity编号
+	msg					消息内容
+ @return
+ 	error				发送失败时返回错误信息
+ @history
+ 	2026-10-16_14:49 	agent		创建
+*******************************************************************************/
+func (w syslogWriter) send(severity int, msg string) error {
+	switch syslog.Priority(severity) {
+	case syslog.LOG_EMERG:
+		return w.Emerg(msg)
+	case syslog.LOG_ALERT:
+		return w.Alert(msg)
+	case syslog.LOG_CRIT:
+		return w.Crit(msg)
+	case syslog.LOG_ERR:
+		return w.Err(msg)
+	case syslog.LOG_WARNING:
+		return w.Warning(msg)
+	case syslog.LOG_NOTICE:
+		return w.Notice(msg)
+	case syslog.LOG_DEBUG:
+		return w.Debug(msg)
+	}
+
+	return w.Info(msg)
+}
@@ -0,0 +1,423 @@
+package logger
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+/******************************************************************************
+ @brief
+ 	带标签的日志，每条日志的末尾都会附带创建时指定的key=value标签，
+ 	写入主日志或者创建时所在的分类日志
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:54 	agent		创建
+ 	2026-10-16_15:06 	agent		增加路由标签
+*******************************************************************************/
+type TAG_LOG struct {
+	category *CATEGORY         //写入的分类，nil表示主日志
+	tags     [][2]string       //标签列表，按添加顺序输出
+	text     string            //标签的文本格式缓存
+	labels   map[string]string //路由标签，只用于选择输出目标，不写入日志内容
+}
+
+var (
+	connSeq uint64 //连接编号，ForConn每次加1
+)
+
+/******************************************************************************
+ @brief
+ 	创建带标签的主日志
+ 		例：
+ 			log := logger.With("player", strconv.FormatInt(playerID, 10))
+ 			log.Infof("enter room %d", roomID)
+
+ 		输出：INFO enter room 1001 player=10001
+ @author
+ 	agent
+ @param
+	key					标签名称
+	value				标签值
+ @return
+ 	*TAG_LOG			返回带标签的日志
+ @history
+ 	2026-10-16_14:54 	agent		创建
+*******************************************************************************/
+func With(key, value string) *TAG_LOG {
+	return (&TAG_LOG{}).With(key, value)
+}
+
+/******************************************************************************
+ @brief
+ 	创建带标签的分类日志
+ @author
+ 	agent
+ @param
+	key					标签名称
+	value				标签值
+ @return
+ 	*TAG_LOG			返回带标签的日志
+ @history
+ 	2026-10-16_14:54 	agent		创建
+*******************************************************************************/
+func (c *CATEGORY) With(key, value string) *TAG_LOG {
+	return (&TAG_LOG{category: c}).With(key, value)
+}
+
+/******************************************************************************
+ @brief
+ 	在已有标签的基础上增加标签，返回新的日志，原来的日志不受影响
+ @author
+ 	agent
+ @param
+	key					标签名称
+	value				标签值
+ @return
+ 	*TAG_LOG			返回带标签的日志
+ @history
+ 	2026-10-16_14:54 	agent		创建
+ 	2026-10-16_15:06 	agent		保留路由标签
+*******************************************************************************/
+func (t *TAG_LOG) With(key, value string) *TAG_LOG {
+	tags := make([][2]string, 0, len(t.tags)+1)
+	tags = append(tags, t.tags...)
+	tags = append(tags, [2]string{key, value})
+
+	return &TAG_LOG{category: t.category, tags: tags, text: t.text + " " + configField(key, value), labels: t.labels}
+}
+
+/******************************************************************************
+ @brief
+ 	获取标签的值
+ @author
+ 	agent
+ @param
+	key					标签名称
+ @return
+ 	string				返回标签值，不存在时返回空字符串
+ @history
+ 	2026-10-16_14:54 	agent		创建
+*******************************************************************************/
+func (t *TAG_LOG) Tag(key string) string {
+	for i := len(t.tags) - 1; i >= 0; i-- {
+		if t.tags[i][0] == key {
+			return t.tags[i][1]
+		}
+	}
+
+	return ""
+}
+
+/******************************************************************************
+ @brief
+ 	创建网络连接的日志，预先带有连接编号、远端地址和本地地址标签，
+ 	省去每个TCP服务器重复实现的连接标记
+ 		例：
+ 			log := logger.ForConn(conn)
+ 			log.Infof("login account %s", account)
+
+ 		输出：INFO login account tom conn_id=42 remote=10.0.0.1:50122 local=10.0.0.2:7000
+ @author
+ 	agent
+ @param
+	conn				网络连接
+ @return
+ 	*TAG_LOG			返回带标签的日志
+ @history
+ 	2026-10-16_14:54 	agent		创建
+*******************************************************************************/
+func ForConn(conn net.Conn) *TAG_LOG {
+	id := strconv.FormatUint(atomic.AddUint64(&connSeq, 1), 10)
+	t := With("conn_id", id)
+
+	if addr := conn.RemoteAddr(); addr != nil {
+		t = t.With("remote", addr.String())
+	}
+	if addr := conn.LocalAddr(); addr != nil {
+		t = t.With("local", addr.String())
+	}
+
+	return t
+}
+
+/******************************************************************************
+ @brief
+ 	输出带标签的日志，仅供内部使用，调用深度与output相同
+ @author
+ 	agent
+ @param
+	ll					日志级别
+	arg					日志内容
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:54 	agent		创建
+ 	2026-10-16_15:06 	agent		传递路由标签
+*******************************************************************************/
+func (t *TAG_LOG) output(ll LEVEL, arg string) {
+	f := logFile
+	if t.category != nil {
+		f = t.category.file
+	}
+
+	outputFile(f, ll, strings.TrimRight(arg, "\n")+t.text+"\n", t.labels)
+}
+
+/******************************************************************************
+ @brief
+ 	输出Debug带标签日志
+ @author
+ 	agent
+ @see
+ 	logger.Debug
+ @history
+ 	2026-10-16_14:54 	agent		创建
+*******************************************************************************/
+func (t *TAG_LOG) Debug(arg interface{}) {
+	defer catchError()
+	if currentLevel() <= DEBUG {
+		t.output(DEBUG, fmt.Sprintln(arg))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Info带标签日志
+ @author
+ 	agent
+ @see
+ 	logger.Info
+ @history
+ 	2026-10-16_14:54 	agent		创建
+*******************************************************************************/
+func (t *TAG_LOG) Info(arg interface{}) {
+	defer catchError()
+	if currentLevel() <= INFO {
+		t.output(INFO, fmt.Sprintln(arg))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Warn带标签日志
+ @author
+ 	agent
+ @see
+ 	logger.Warn
+ @history
+ 	2026-10-16_14:54 	agent		创建
+*******************************************************************************/
+func (t *TAG_LOG) Warn(arg interface{}) {
+	defer catchError()
+	if currentLevel() <= WARN {
+		t.output(WARN, fmt.Sprintln(arg))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Error带标签日志
+ @author
+ 	agent
+ @see
+ 	logger.Error
+ @history
+ 	2026-10-16_14:54 	agent		创建
+*******************************************************************************/
+func (t *TAG_LOG) Error(arg interface{}) {
+	defer catchError()
+	if currentLevel() <= ERROR {
+		t.output(ERROR, fmt.Sprintln(arg))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Fatal带标签日志
+ @author
+ 	agent
+ @see
+ 	logger.Fatal
+ @history
+ 	2026-10-16_14:54 	agent		创建
+*******************************************************************************/
+func (t *TAG_LOG) Fatal(arg interface{}) {
+	defer catchError()
+	if currentLevel() <= FATAL {
+		t.output(FATAL, fmt.Sprintln(arg))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Debug带标签日志
+ @author
+ 	agent
+ @see
+ 	logger.Debugf
+ @history
+ 	2026-10-16_14:54 	agent		创建
+*******************************************************************************/
+func (t *TAG_LOG) Debugf(format string, args ...interface{}) {
+	defer catchError()
+	if currentLevel() <= DEBUG {
+		t.output(DEBUG, fmt.Sprintf(format, args...))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Info带标签日志
+ @author
+ 	agent
+ @see
+ 	logger.Infof
+ @history
+ 	2026-10-16_14:54 	agent		创建
+*******************************************************************************/
+func (t *TAG_LOG) Infof(format string, args ...interface{}) {
+	defer catchError()
+	if currentLevel() <= INFO {
+		t.output(INFO, fmt.Sprintf(format, args...))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Warn带标签日志
+ @author
+ 	agent
+ @see
+ 	logger.Warnf
+ @history
+ 	2026-10-16_14:54 	agent		创建
+*******************************************************************************/
+func (t *TAG_LOG) Warnf(format string, args ...interface{}) {
+	defer catchError()
+	if currentLevel() <= WARN {
+		t.output(WARN, fmt.Sprintf(format, args...))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Error带标签日志
+ @author
+ 	agent
+ @see
+ 	logger.Errorf
+ @history
+ 	2026-10-16_14:54 	agent		创建
+*******************************************************************************/
+func (t *TAG_LOG) Errorf(format string, args ...interface{}) {
+	defer catchError()
+	if currentLevel() <= ERROR {
+		t.output(ERROR, fmt.Sprintf(format, args...))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Fatal带标签日志
+ @author
+ 	agent
+ @see
+ 	logger.Fatalf
+ @history
+ 	2026-10-16_14:54 	agent		创建
+*******************************************************************************/
+func (t *TAG_LOG) Fatalf(format string, args ...interface{}) {
+	defer catchError()
+	if currentLevel() <= FATAL {
+		t.output(FATAL, fmt.Sprintf(format, args...))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Debug带标签日志
+ @author
+ 	agent
+ @see
+ 	logger.Debugln
+ @history
+ 	2026-10-16_14:54 	agent		创建
+*******************************************************************************/
+func (t *TAG_LOG) Debugln(args ...interface{}) {
+	defer catchError()
+	if currentLevel() <= DEBUG {
+		t.output(DEBUG, fmt.Sprintln(args...))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Info带标签日志
+ @author
+ 	agent
+ @see
+ 	logger.Infoln
+ @history
+ 	2026-10-16_14:54 	agent		创建
+*******************************************************************************/
+func (t *TAG_LOG) Infoln(args ...interface{}) {
+	defer catchError()
+	if currentLevel() <= INFO {
+		t.output(INFO, fmt.Sprintln(args...))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Warn带标签日志
+ @author
+ 	agent
+ @see
+ 	logger.Warnln
+ @history
+ 	2026-10-16_14:54 	agent		创建
+*******************************************************************************/
+func (t *TAG_LOG) Warnln(args ...interface{}) {
+	defer catchError()
+	if currentLevel() <= WARN {
+		t.output(WARN, fmt.Sprintln(args...))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Error带标签日志
+ @author
+ 	agent
+ @see
+ 	logger.Errorln
+ @history
+ 	2026-10-16_14:54 	agent		创建
+*******************************************************************************/
+func (t *TAG_LOG) Errorln(args ...interface{}) {
+	defer catchError()
+	if currentLevel() <= ERROR {
+		t.output(ERROR, fmt.Sprintln(args...))
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	输出Fatal带标签日志
+ @author
+ 	agent
+ @see
+ 	logger.Fatalln
+ @history
+ 	2026-10-16_14:54 	agent		创建
+*******************************************************************************/
+func (t *TAG_LOG) Fatalln(args ...interface{}) {
+	defer catchError()
+	if currentLevel() <= FATAL {
+		t.output(FATAL, fmt.Sprintln(args...))
+	}
+}
@@ -0,0 +1,334 @@
+package logger
+
+import (
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	租户的保留配额
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:00 	agent		创建
+*******************************************************************************/
+type tenantQuota struct {
+	maxAge   time.Duration //保留时间，0表示使用SetRetention的设置
+	maxBytes int64         //每个日志文件的历史文件总大小上限，0表示不限制
+}
+
+const (
+	tenantDefaultMax = 64 //默认最多创建的租户日志文件数量
+)
+
+var (
+	tenantOn     int32                      //是否按租户分开写入日志文件
+	tenantLock   sync.RWMutex               //租户日志文件线程锁
+	tenantLogs   = map[string]*LOG_FILE{}   //租户日志文件，键为租户/日志文件基础名字
+	tenantQuotas = map[string]tenantQuota{} //每个租户的保留配额
+	tenantAllow  map[string]bool            //允许单独写入的租户，为nil表示不限制
+	tenantMax    = tenantDefaultMax         //最多创建的租户日志文件数量
+	tenantFull   bool                       //是否已经提示过租户日志文件数量达到上限
+)
+
+/******************************************************************************
+ @brief
+ 	开启或关闭多租户模式，开启后带有tenant字段的日志写入日志目录下的
+ 	tenants/租户/日期/文件名.时间.log，分类日志同样按租户分开，不带tenant字段的日志不受影响。
+ 	一个进程承载多个游戏世界时，每个世界的日志可以单独保留和清理。
+ 	tenant字段来自日志内容，可以通过SetTenantLimit限制允许的租户和租户日志文件数量，
+ 	不允许的租户和超过数量后的新租户写入原来的日志文件
+ 		例：
+ 			logger.SetTenancy(true)
+ 			logger.SetTenantRetention("world2", 7*24*time.Hour, 10<<30)
+
+ 			world := logger.With("tenant", "world2")
+ 			world.Info("boss spawned")
+
+ 		日志文件为：
+ 			./logs/tenants/world2/2026-10-17/game.11_00_00.log
+ @author
+ 	agent
+ @param
+	enable				是否开启
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:00 	agent		创建
+*******************************************************************************/
+func SetTenancy(enable bool) {
+	if enable {
+		atomic.StoreInt32(&tenantOn, 1)
+	} else {
+		atomic.StoreInt32(&tenantOn, 0)
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	设置租户的保留配额，切分时先清理超过保留时间的文件，历史文件总大小仍超过上限时
+ 	从最旧的文件开始删除，只读保留期内的文件不会被删除
+ @author
+ 	agent
+ @param
+	tenant				租户
+	maxAge				保留时间，小于等于0表示使用SetRetention的设置
+	maxBytes			每个日志文件的历史文件总大小上限，小于等于0表示不限制
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:00 	agent		创建
+*******************************************************************************/
+func SetTenantRetention(tenant string, maxAge time.Duration, maxBytes int64) {
+	if maxAge < 0 {
+		maxAge = 0
+	}
+	if maxBytes < 0 {
+		maxBytes = 0
+	}
+
+	tenantLock.Lock()
+	defer tenantLock.Unlock()
+
+	tenantQuotas[tenant] = tenantQuota{maxAge: maxAge, maxBytes: maxBytes}
+	for key, f := range tenantLogs {
+		if strings.HasPrefix(key, tenant+"/") {
+			f.Lock()
+			f.retention, f.quota = maxAge, maxBytes
+			f.Unlock()
+		}
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	限制单独写入的租户，tenant字段来自日志内容，不加限制时每个不同的值都会创建日志文件。
+ 	不在允许列表中的租户和租户日志文件数量达到上限后的新租户写入原来的日志文件，
+ 	默认不限制租户，最多创建64个租户日志文件
+ 		例：
+ 			logger.SetTenantLimit(16, "world1", "world2")
+ @author
+ 	agent
+ @param
+	max					最多创建的租户日志文件数量，每个租户的每个分类日志各算一个，小于等于0表示使用默认值
+	tenants				允许单独写入的租户，为空表示不限制
+ @return
+ 	-
+ @history
+ 	2026-10-16_16:50 	agent		创建
+*******************************************************************************/
+func SetTenantLimit(max int, tenants ...string) {
+	if max <= 0 {
+		max = tenantDefaultMax
+	}
+
+	tenantLock.Lock()
+	defer tenantLock.Unlock()
+
+	tenantMax = max
+	tenantFull = false
+	tenantAllow = nil
+	if len(tenants) > 0 {
+		tenantAllow = map[string]bool{}
+		for _, tenant := range tenants {
+			tenantAllow[tenant] = true
+		}
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	根据日志内容中的tenant字段选择租户日志文件
+ @author
+ 	agent
+ @param
+	f					原来的日志文件
+	arg					日志内容
+ @return
+ 	*LOG_FILE			返回租户日志文件，没有开启多租户、没有tenant字段、租户不允许或数量达到上限时返回f
+ @history
+ 	2026-10-16_15:00 	agent		创建
+ 	2026-10-16_16:50 	agent		限制允许的租户和租户日志文件数量
+*******************************************************************************/
+func tenantFile(f *LOG_FILE, arg string) *LOG_FILE {
+	if f == nil || atomic.LoadInt32(&tenantOn) == 0 {
+		return f
+	}
+
+	tenant := tenantOf(arg)
+	if len(tenant) == 0 {
+		return f
+	}
+
+	h := f.current()
+	if h == nil {
+		return f
+	}
+
+	key := tenant + "/" + f.log_filename
+
+	tenantLock.RLock()
+	tf, ok := tenantLogs[key]
+	full := len(tenantLogs) >= tenantMax && tenantFull
+	denied := tenantAllow != nil && !tenantAllow[tenant]
+	tenantLock.RUnlock()
+	if ok {
+		return tf
+	}
+	if full || denied {
+		return f
+	}
+
+	tenantLock.Lock()
+	defer tenantLock.Unlock()
+
+	if tf, ok := tenantLogs[key]; ok {
+		return tf
+	}
+
+	//租户日志文件数量达到上限时写入原来的日志文件，只提示一次
+	if len(tenantLogs) >= tenantMax {
+		if !tenantFull {
+			tenantFull = true
+			diag("tenant log files reached the limit of %d, new tenants are written to the main log", tenantMax)
+		}
+		return f
+	}
+
+	quota := tenantQuotas[tenant]
+	tf = &LOG_FILE{log_filename: f.log_filename, worm: f.worm, retention: quota.maxAge, quota: quota.maxBytes}
+	if header, ok := f.header.Load().(func() []byte); ok {
+		tf.header.Store(header)
+	}
+	tf.start(filepath.Join(f.log_dir, "tenants", safeName(tenant)))
+	tenantLogs[key] = tf
+
+	return tf
+}
+
+/******************************************************************************
+ @brief
+ 	获取日志内容中tenant字段的值
+ @author
+ 	agent
+ @param
+	arg					日志内容
+ @return
+ 	string				返回租户，没有时返回空字符串
+ @history
+ 	2026-10-16_15:00 	agent		创建
+*******************************************************************************/
+func tenantOf(arg string) string {
+	s := arg
+	for {
+		i := strings.Index(s, "tenant=")
+		if i < 0 {
+			return ""
+		}
+
+		if i > 0 && strings.IndexByte(" \t\n", s[i-1]) < 0 {
+			s = s[i+len("tenant="):]
+			continue
+		}
+
+		value := s[i+len("tenant="):]
+		if strings.HasPrefix(value, `"`) {
+			if unquoted, err := strconv.QuotedPrefix(value); err == nil {
+				value, _ = strconv.Unquote(unquoted)
+				return value
+			}
+		}
+
+		if end := strings.IndexAny(value, " \t\r\n"); end >= 0 {
+			value = value[:end]
+		}
+		return value
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	获取所有租户日志文件，用于文件监控
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	[]*LOG_FILE			返回日志文件列表
+ @history
+ 	2026-10-16_15:00 	agent		创建
+*******************************************************************************/
+func tenantFiles() []*LOG_FILE {
+	tenantLock.RLock()
+	defer tenantLock.RUnlock()
+
+	files := make([]*LOG_FILE, 0, len(tenantLogs))
+	for _, f := range tenantLogs {
+		if f.current() != nil {
+			files = append(files, f)
+		}
+	}
+
+	return files
+}
+
+/******************************************************************************
+ @brief
+ 	历史文件总大小超过上限时，从最旧的文件开始删除，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	files				历史文件列表
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:00 	agent		创建
+*******************************************************************************/
+func (f *LOG_FILE) sweepQuota(files []string) {
+	if f.quota <= 0 {
+		return
+	}
+
+	type history struct {
+		path string
+		size int64
+		mod  time.Time
+	}
+
+	list := []history{}
+	total := int64(0)
+	for _, fn := range files {
+		fi, err := logStorage.Stat(fn)
+		if err != nil || fi.IsDir() {
+			continue
+		}
+		list = append(list, history{path: fn, size: fi.Size(), mod: fi.ModTime()})
+		total += fi.Size()
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].mod.Before(list[j].mod) })
+
+	now := time.Now()
+	for _, h := range list {
+		if total <= f.quota {
+			return
+		}
+
+		//只读保留期内拒绝删除
+		if f.worm > 0 && now.Sub(h.mod) < f.worm {
+			continue
+		}
+
+		if logStorage.Remove(h.path) == nil {
+			total -= h.size
+			lifecycle(LIFECYCLE_EVENT{Kind: LIFECYCLE_FILE_PRUNED, File: h.path})
+		}
+		logStorage.Remove(filepath.Dir(h.path))
+	}
+}
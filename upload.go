@@ -0,0 +1,234 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	增量上传函数，file为日志文件路径，offset为data在文件中的偏移，
+ 	返回错误时下次从同一偏移重新上传
+ @author
+ 	agent
+ @history
+ 	2026-10-16_14:28 	agent		创建
+*******************************************************************************/
+type UPLOADER func(file string, offset int64, data []byte) error
+
+const (
+	uploadChunk = 4 * 1024 * 1024 //每次上传的最大大小
+)
+
+var (
+	uploadLock    sync.Mutex       //增量上传线程锁
+	uploader      UPLOADER         //增量上传函数
+	uploadStop    chan struct{}    //停止增量上传
+	uploadOffsets map[string]int64 //每个文件已经上传的偏移
+
+	uploadEnabled int32      //是否开启了增量上传
+	uploadPending []string   //已经切分、等待上传剩余内容的文件
+	uploadQueueMu sync.Mutex //切分文件队列锁，切分时不能等待上传
+)
+
+/******************************************************************************
+ @brief
+ 	设置当前日志文件的增量上传，每隔interval将主日志和分类日志新写入的内容上传到
+ 	远程存储，切分时会先上传旧文件剩余的内容，主机宕机时也可以恢复未切分的文件
+ 		例：
+ 			logger.SetActiveUpload(func(file string, offset int64, data []byte) error {
+ 				return bucket.WriteAt(file, offset, data)
+ 			}, 10*time.Second)
+ @author
+ 	agent
+ @param
+	up					增量上传函数，nil表示关闭
+	interval			上传时间间隔
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:28 	agent		创建
+ 	2026-10-16_15:39 	agent		切分的文件放入队列上传
+*******************************************************************************/
+func SetActiveUpload(up UPLOADER, interval time.Duration) {
+	uploadLock.Lock()
+	defer uploadLock.Unlock()
+
+	if uploadStop != nil {
+		close(uploadStop)
+		uploadStop = nil
+	}
+
+	uploader = up
+	uploadOffsets = map[string]int64{}
+
+	uploadQueueMu.Lock()
+	uploadPending = nil
+	uploadQueueMu.Unlock()
+
+	if up == nil || interval <= 0 {
+		atomic.StoreInt32(&uploadEnabled, 0)
+		return
+	}
+
+	atomic.StoreInt32(&uploadEnabled, 1)
+
+	uploadStop = make(chan struct{})
+	go uploadMonitor(interval, uploadStop)
+}
+
+/******************************************************************************
+ @brief
+ 	增量上传监控函数，按照时间间隔上传新写入的内容
+ @author
+ 	agent
+ @param
+	interval			上传时间间隔
+	stop				停止信号
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:28 	agent		创建
+ 	2026-10-16_14:45 	agent		上传受后台任务并发数量限制
+*******************************************************************************/
+func uploadMonitor(interval time.Duration, stop chan struct{}) {
+	timer := time.NewTicker(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			runBackground(uploadCheck)
+		case <-stop:
+			return
+		}
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	文件切分后放入上传队列，下次上传时先上传文件剩余的内容，
+ 	两次上传之间切分多次时中间的文件也不会遗漏
+ @author
+ 	agent
+ @param
+	fn					切分前的文件路径
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:39 	agent		创建
+*******************************************************************************/
+func uploadRotated(fn string) {
+	if atomic.LoadInt32(&uploadEnabled) == 0 || len(fn) == 0 {
+		return
+	}
+
+	uploadQueueMu.Lock()
+	uploadPending = append(uploadPending, fn)
+	uploadQueueMu.Unlock()
+}
+
+/******************************************************************************
+ @brief
+ 	上传所有日志文件新写入的内容
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:28 	agent		创建
+ 	2026-10-16_14:43 	agent		使用文件句柄
+ 	2026-10-16_14:47 	agent		记录上传失败
+ 	2026-10-16_15:00 	agent		同时上传租户日志文件
+ 	2026-10-16_15:39 	agent		先上传队列中切分的文件
+*******************************************************************************/
+func uploadCheck() {
+
+	defer catchError()
+	files := append(categoryFiles(), tenantFiles()...)
+	if logFile != nil {
+		files = append(files, logFile)
+	}
+
+	uploadLock.Lock()
+	defer uploadLock.Unlock()
+
+	if uploader == nil {
+		return
+	}
+
+	//已经切分的文件，上传失败的放回队列下次重试
+	uploadQueueMu.Lock()
+	pending := uploadPending
+	uploadPending = nil
+	uploadQueueMu.Unlock()
+
+	var failed []string
+	for _, fn := range pending {
+		if err := uploadFile(fn); err != nil {
+			diag("upload %s: %v", fn, err)
+			failed = append(failed, fn)
+			continue
+		}
+		delete(uploadOffsets, fn)
+	}
+
+	if len(failed) > 0 {
+		uploadQueueMu.Lock()
+		uploadPending = append(failed, uploadPending...)
+		uploadQueueMu.Unlock()
+	}
+
+	for _, f := range files {
+		if h := f.current(); h != nil && len(h.path) > 0 {
+			if err := uploadFile(h.path); err != nil {
+				diag("upload %s: %v", h.path, err)
+			}
+		}
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	上传文件从上次偏移开始新写入的内容，调用者需要持有锁
+ @author
+ 	agent
+ @param
+	fn					文件路径
+ @return
+ 	error				上传失败时返回错误信息，文件不存在时不算失败
+ @history
+ 	2026-10-16_14:28 	agent		创建
+*******************************************************************************/
+func uploadFile(fn string) error {
+
+	file, err := logStorage.OpenFile(fn, os.O_RDONLY, 0)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	buf := make([]byte, uploadChunk)
+	for {
+		offset := uploadOffsets[fn]
+		n, err := file.ReadAt(buf, offset)
+		if n > 0 {
+			if err := uploader(fn, offset, buf[:n]); err != nil {
+				return err
+			}
+			uploadOffsets[fn] = offset + int64(n)
+		}
+
+		if err == io.EOF || n == 0 {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package logger
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	检查日志配置，不修改任何设置，可以在启动时或通过-check-logging参数在上线前调用。
+ 	检查日志目录是否可写、级别和格式是否有效、采样和切分保留时间是否合理、
+ 	压缩算法是否存在、远程地址是否可以解析
+ 		例：
+ 			if errs := logger.ValidateConfig(cfg); len(errs) > 0 {
+ 				for _, err := range errs {
+ 					fmt.Println(err)
+ 				}
+ 				os.Exit(1)
+ 			}
+ @author
+ 	agent
+ @param
+	cfg					日志配置
+ @return
+ 	[]error				返回发现的全部问题，配置正确时返回nil
+ @history
+ 	2026-10-16_15:08 	agent		创建
+ 	2026-10-16_15:35 	agent		检查日志级别时间段规则
+*******************************************************************************/
+func ValidateConfig(cfg CONFIG) []error {
+
+	var errs []error
+	add := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("logger: "+format, args...))
+	}
+
+	if cfg.Level < ALL || cfg.Level > FATAL {
+		add("invalid level %d", cfg.Level)
+	}
+
+	if cfg.ConsoleFormat != FORMAT_TEXT && cfg.ConsoleFormat != FORMAT_JSON {
+		add("unknown console format %d", cfg.ConsoleFormat)
+	}
+
+	if len(cfg.Dir) > 0 {
+		if err := validateDir(cfg.Dir); err != nil {
+			add("log directory %s is not writable: %v", cfg.Dir, err)
+		}
+		if len(cfg.Name) == 0 {
+			add("empty log file name")
+		} else if strings.ContainsAny(cfg.Name, `/\`) {
+			add("log file name %q contains a path separator", cfg.Name)
+		}
+	}
+
+	if cfg.StormLimit < 0 {
+		add("negative storm limit %d", cfg.StormLimit)
+	} else if cfg.StormLimit > 0 && cfg.StormSample <= 1 {
+		add("storm sample %d drops every entry during a storm", cfg.StormSample)
+	}
+
+	period := 24 * time.Hour
+	switch {
+	case cfg.RotateInterval < 0:
+		add("negative rotate interval %v", cfg.RotateInterval)
+	case cfg.RotateInterval > 0 && cfg.RotateInterval < time.Minute:
+		add("rotate interval %v is shorter than a minute", cfg.RotateInterval)
+	case cfg.RotateInterval > 0 && cfg.RotateInterval < period:
+		period = cfg.RotateInterval
+	}
+
+	if cfg.Retention < 0 {
+		add("negative retention %v", cfg.Retention)
+	} else if cfg.Retention > 0 && cfg.Retention < period {
+		add("retention %v is shorter than the rotate interval %v", cfg.Retention, period)
+	}
+
+	if cfg.BackgroundWorkers < 0 {
+		add("negative background workers %d", cfg.BackgroundWorkers)
+	}
+
+	if cfg.AsyncQueue < 0 {
+		add("negative async queue %d", cfg.AsyncQueue)
+	}
+
+	for _, ls := range cfg.LevelSchedules {
+		_, errBegin := parseClock(ls.Begin)
+		_, errEnd := parseClock(ls.End)
+		_, errLevel := ParseLevel(ls.Level)
+		for _, err := range []error{errBegin, errEnd, errLevel} {
+			if err != nil {
+				add("level schedule: %s", strings.TrimPrefix(err.Error(), "logger: "))
+			}
+		}
+	}
+
+	if len(cfg.RemoteCompression) > 0 {
+		compressorLock.RLock()
+		_, ok := compressors[cfg.RemoteCompression]
+		compressorLock.RUnlock()
+		if !ok {
+			add("unknown compressor %q", cfg.RemoteCompression)
+		}
+	}
+
+	for _, addr := range cfg.Remote {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			add("remote %s: %v", addr, err)
+			continue
+		}
+		if _, err := net.LookupHost(host); err != nil {
+			add("remote %s: %v", addr, err)
+		}
+	}
+
+	return errs
+}
+
+/******************************************************************************
+ @brief
+ 	检查日志目录是否可写，目录不存在时创建，并写入和删除一个临时文件
+ @author
+ 	agent
+ @param
+	dir					日志目录
+ @return
+ 	error				不可写时返回错误信息
+ @history
+ 	2026-10-16_15:08 	agent		创建
+ 	2026-10-16_16:25 	agent		文件操作通过日志存储接口完成
+*******************************************************************************/
+func validateDir(dir string) error {
+	if err := logStorage.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	name := filepath.Join(dir, fmt.Sprintf(".logger-check-%d", os.Getpid()))
+	f, err := logStorage.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write([]byte("check\n"))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	logStorage.Remove(name)
+
+	return err
+}
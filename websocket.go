@@ -0,0 +1,400 @@
+package logger
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	websocketGUID    = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11" //RFC 6455握手使用的固定GUID
+	websocketQueue   = 256                                    //每个客户端的发送队列长度，队列满时丢弃日志
+	websocketMaxRead = 4096                                   //客户端消息的最大长度
+)
+
+/******************************************************************************
+ @brief
+ 	WebSocket广播输出目标类结构，既是输出目标也是http.Handler，
+ 	每条日志以文本消息广播给所有连接的客户端，只实现了服务端推送需要的RFC 6455子集，不依赖第三方库
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:20 	agent		创建
+*******************************************************************************/
+type WEBSOCKET_WRITER struct {
+	sync.Mutex                               //线程锁
+	clients    map[*websocketClient]struct{} //已连接的客户端
+}
+
+/******************************************************************************
+ @brief
+ 	WebSocket客户端
+ @author
+ 	agent
+ @history
+ 	2026-10-16_15:20 	agent		创建
+*******************************************************************************/
+type websocketClient struct {
+	conn    net.Conn      //客户端连接
+	level   int32         //客户端的日志级别，低于该级别的日志不发送
+	send    chan []byte   //已经编码的待发送帧
+	done    chan struct{} //客户端断开时关闭
+	closing sync.Once     //保证只关闭一次
+}
+
+/******************************************************************************
+ @brief
+ 	创建WebSocket广播输出目标，内部网页看板连接后即可实时显示服务器日志。
+ 	客户端连接时可以用level参数设置级别，连接后发送级别名称的文本消息可以随时修改；
+ 	发送队列满的慢客户端会丢弃日志，不会阻塞写日志
+ 		例：
+ 			ws := logger.NewWebSocketWriter()
+ 			logger.AddSink("dashboard", ws, logger.BUFFER_NONE, 0, 0)
+ 			http.Handle("/logs/live", ws)
+
+ 			const ws = new WebSocket("ws://127.0.0.1:18000/logs/live?level=warn")
+ 			ws.onmessage = e => console.log(e.data)
+ 			ws.send("ERROR")
+
+ 		接口没有认证，只应挂在内网管理端口上
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	*WEBSOCKET_WRITER	返回输出目标
+ @history
+ 	2026-10-16_15:20 	agent		创建
+*******************************************************************************/
+func NewWebSocketWriter() *WEBSOCKET_WRITER {
+	return &WEBSOCKET_WRITER{clients: map[*websocketClient]struct{}{}}
+}
+
+/******************************************************************************
+ @brief
+ 	将日志行广播给级别匹配的客户端
+ @author
+ 	agent
+ @param
+	b					要写入的日志行
+ @return
+ 	int					返回写入的字节数
+ 	error				总是返回nil
+ @history
+ 	2026-10-16_15:20 	agent		创建
+*******************************************************************************/
+func (w *WEBSOCKET_WRITER) Write(b []byte) (int, error) {
+	w.Lock()
+	defer w.Unlock()
+
+	if len(w.clients) == 0 {
+		return len(b), nil
+	}
+
+	entries, raws := parseEntries(b)
+	for i, e := range entries {
+		frame := websocketFrame(0x1, []byte(raws[i]))
+		for c := range w.clients {
+			if e.Level < LEVEL(atomic.LoadInt32(&c.level)) {
+				continue
+			}
+
+			select {
+			case c.send <- frame:
+			default:
+			}
+		}
+	}
+
+	return len(b), nil
+}
+
+/******************************************************************************
+ @brief
+ 	断开所有客户端
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	error				总是返回nil
+ @history
+ 	2026-10-16_15:20 	agent		创建
+*******************************************************************************/
+func (w *WEBSOCKET_WRITER) Close() error {
+	w.Lock()
+	defer w.Unlock()
+
+	for c := range w.clients {
+		c.close()
+		delete(w.clients, c)
+	}
+
+	return nil
+}
+
+/******************************************************************************
+ @brief
+ 	当前连接的客户端数量
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	int					返回客户端数量
+ @history
+ 	2026-10-16_15:20 	agent		创建
+*******************************************************************************/
+func (w *WEBSOCKET_WRITER) Clients() int {
+	w.Lock()
+	defer w.Unlock()
+
+	return len(w.clients)
+}
+
+/******************************************************************************
+ @brief
+ 	完成WebSocket握手并开始推送日志，直到客户端断开
+ @author
+ 	agent
+ @param
+	rw					HTTP应答
+	r					HTTP请求
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:20 	agent		创建
+*******************************************************************************/
+func (w *WEBSOCKET_WRITER) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if r.Method != http.MethodGet || !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || len(key) == 0 {
+		http.Error(rw, "websocket upgrade required", http.StatusBadRequest)
+		return
+	}
+
+	level := ALL
+	if s := r.FormValue("level"); len(s) > 0 {
+		var err error
+		if level, err = ParseLevel(s); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	conn, buf, err := http.NewResponseController(rw).Hijack()
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	conn.SetWriteDeadline(time.Now().Add(netWriteTimeout))
+	_, err = fmt.Fprintf(conn, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n",
+		base64.StdEncoding.EncodeToString(sum[:]))
+	if err != nil {
+		conn.Close()
+		return
+	}
+
+	c := &websocketClient{
+		conn:  conn,
+		level: int32(level),
+		send:  make(chan []byte, websocketQueue),
+		done:  make(chan struct{}),
+	}
+
+	w.Lock()
+	w.clients[c] = struct{}{}
+	w.Unlock()
+
+	go c.read(buf.Reader)
+	c.write()
+
+	w.Lock()
+	delete(w.clients, c)
+	w.Unlock()
+}
+
+/******************************************************************************
+ @brief
+ 	发送队列中的帧，直到客户端断开
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:20 	agent		创建
+*******************************************************************************/
+func (c *websocketClient) write() {
+	defer c.close()
+
+	for {
+		select {
+		case frame := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(netWriteTimeout))
+			if _, err := c.conn.Write(frame); err != nil {
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	读取客户端消息，文本消息为新的日志级别，回应ping，收到close或出错时断开
+ @author
+ 	agent
+ @param
+	r					连接的读取缓冲
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:20 	agent		创建
+*******************************************************************************/
+func (c *websocketClient) read(r *bufio.Reader) {
+	defer c.close()
+
+	for {
+		opcode, payload, err := websocketRead(r)
+		if err != nil {
+			return
+		}
+
+		switch opcode {
+		case 0x1:
+			if level, err := ParseLevel(strings.TrimSpace(string(payload))); err == nil {
+				atomic.StoreInt32(&c.level, int32(level))
+			}
+		case 0x8:
+			c.conn.SetWriteDeadline(time.Now().Add(netWriteTimeout))
+			c.conn.Write(websocketFrame(0x8, nil))
+			return
+		case 0x9:
+			select {
+			case c.send <- websocketFrame(0xA, payload):
+			default:
+			}
+		}
+	}
+}
+
+/******************************************************************************
+ @brief
+ 	断开客户端连接
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_15:20 	agent		创建
+*******************************************************************************/
+func (c *websocketClient) close() {
+	c.closing.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+/******************************************************************************
+ @brief
+ 	生成服务端发送的不分片、不带掩码的帧
+ @author
+ 	agent
+ @param
+	opcode				帧类型
+	payload				帧内容
+ @return
+ 	[]byte				返回帧
+ @history
+ 	2026-10-16_15:20 	agent		创建
+*******************************************************************************/
+func websocketFrame(opcode byte, payload []byte) []byte {
+	buf := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n < 126:
+		buf = append(buf, byte(n))
+	case n < 65536:
+		buf = append(buf, 126, byte(n>>8), byte(n))
+	default:
+		buf = append(buf, 127)
+		buf = binary.BigEndian.AppendUint64(buf, uint64(n))
+	}
+
+	return append(buf, payload...)
+}
+
+/******************************************************************************
+ @brief
+ 	读取一个客户端发送的帧，客户端的帧必须带掩码，分片的帧按各自的类型返回
+ @author
+ 	agent
+ @param
+	r					读取缓冲
+ @return
+ 	byte				返回帧类型
+ 	[]byte				返回去掉掩码后的帧内容
+ 	error				读取失败或帧无效时返回错误信息
+ @history
+ 	2026-10-16_15:20 	agent		创建
+*******************************************************************************/
+func websocketRead(r *bufio.Reader) (byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return 0, nil, err
+	}
+
+	if head[1]&0x80 == 0 {
+		return 0, nil, fmt.Errorf("logger: websocket unmasked client frame")
+	}
+
+	n := uint64(head[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+
+	if n > websocketMaxRead {
+		return 0, nil, fmt.Errorf("logger: websocket client frame too large (%d bytes)", n)
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(r, mask[:]); err != nil {
+		return 0, nil, err
+	}
+
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return head[0] & 0x0f, payload, nil
+}
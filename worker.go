@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"sync"
+)
+
+var (
+	workerLock    sync.Mutex     //后台任务线程锁
+	workerSlots   chan struct{}  //后台任务并发数量限制，nil表示不限制
+	workerPending sync.WaitGroup //尚未完成的后台任务，Flush时等待
+)
+
+/******************************************************************************
+ @brief
+ 	限制日志后台处理（切分后的校验和计算、只读保护、空文件清理、增量上传等）
+ 	的并发数量，避免日志的后台处理和业务逻辑抢占CPU。
+ 	设置后切分文件时的后处理不再占用切分流程，而是交给后台任务执行
+ 		例：
+ 			logger.SetBackgroundWorkers(1)
+ @author
+ 	agent
+ @param
+	n					最大并发数量，小于等于0表示不限制，后处理在切分时同步执行
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:45 	agent		创建
+*******************************************************************************/
+func SetBackgroundWorkers(n int) {
+	workerLock.Lock()
+	defer workerLock.Unlock()
+
+	if n <= 0 {
+		workerSlots = nil
+		return
+	}
+
+	workerSlots = make(chan struct{}, n)
+}
+
+/******************************************************************************
+ @brief
+ 	在当前协程中执行后台任务，并发数量达到上限时等待
+ @author
+ 	agent
+ @param
+	job					后台任务
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:45 	agent		创建
+*******************************************************************************/
+func runBackground(job func()) {
+	workerLock.Lock()
+	slots := workerSlots
+	workerLock.Unlock()
+
+	if slots != nil {
+		slots <- struct{}{}
+		defer func() { <-slots }()
+	}
+
+	job()
+}
+
+/******************************************************************************
+ @brief
+ 	提交后台任务，没有限制并发数量时同步执行，否则在新的协程中排队执行
+ @author
+ 	agent
+ @param
+	job					后台任务
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:45 	agent		创建
+*******************************************************************************/
+func goBackground(job func()) {
+	workerLock.Lock()
+	slots := workerSlots
+	workerLock.Unlock()
+
+	if slots == nil {
+		job()
+		return
+	}
+
+	workerPending.Add(1)
+	go func() {
+		defer workerPending.Done()
+		defer catchError()
+
+		slots <- struct{}{}
+		defer func() { <-slots }()
+
+		job()
+	}()
+}
+
+/******************************************************************************
+ @brief
+ 	等待所有已经提交的后台任务完成
+ @author
+ 	agent
+ @param
+	-
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:45 	agent		创建
+*******************************************************************************/
+func waitBackground() {
+	workerPending.Wait()
+}
@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"time"
+)
+
+/******************************************************************************
+ @brief
+ 	设置分类日志为只读保留模式，用于合规审计类日志。切分后的文件会被设为只读，
+ 	并且在retention保留期内不会被清理，即使超过SetRetention设置的保留时间
+ 		例：
+ 			logger.Category("payment").SetWORM(180 * 24 * time.Hour)
+ @author
+ 	agent
+ @param
+	retention			保留期，小于等于0表示关闭只读保留模式
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:27 	agent		创建
+ 	2026-10-16_14:43 	agent		分类日志文件创建后不再替换
+*******************************************************************************/
+func (c *CATEGORY) SetWORM(retention time.Duration) {
+	c.file.Lock()
+	defer c.file.Unlock()
+
+	c.file.worm = retention
+}
+
+/******************************************************************************
+ @brief
+ 	将切分后的文件设为只读
+ @author
+ 	agent
+ @param
+	fn					文件路径
+ @return
+ 	-
+ @history
+ 	2026-10-16_14:27 	agent		创建
+*******************************************************************************/
+func wormSeal(fn string) {
+	if len(fn) > 0 {
+		logStorage.Chmod(fn, 0444)
+	}
+}